@@ -2,31 +2,55 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/twmb/franz-go/pkg/kgo"
 
+	"payment-processing-system/internal/adapters/alerting"
+	grpcadapter "payment-processing-system/internal/adapters/grpc"
+	httphandler "payment-processing-system/internal/adapters/http"
+	kafkaadapter "payment-processing-system/internal/adapters/messaging/kafka"
+	"payment-processing-system/internal/adapters/schemaregistry"
+	chstorage "payment-processing-system/internal/adapters/storage/clickhouse"
+	"payment-processing-system/internal/adapters/storage/postgres"
 	"payment-processing-system/internal/antifraud"
+	"payment-processing-system/internal/antifraud/anomaly"
+	"payment-processing-system/internal/antifraud/features"
+	"payment-processing-system/internal/app"
 	"payment-processing-system/internal/config"
 	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
 	"payment-processing-system/internal/observability"
 )
 
 // dlqTopic is the name of our Dead-Letter Queue topic.
 var dlqTopic = "transactions.created.dlq"
 
+const (
+	transactionsTopic = "transactions.created"
+	consumerGroup     = "anti-fraud-group"
+)
+
 func main() {
 	// --- Configuration Setup ---
 	cfg, err := config.Load("configs/config.yaml")
-	
+
 	logger := observability.SetupLogger(cfg.App.Env)
 	logger.Info("anti-fraud analyzer запускается", "env", cfg.App.Env)
 
@@ -40,6 +64,13 @@ func main() {
 	// --- Component Initialization ---
 	kafkaBrokers := strings.Split(cfg.Kafka.BootstrapServers, ",")
 
+	// Events are read in every format; records with a schema need the schema registry.
+	var schemaRegistry *schemaregistry.Client
+	if cfg.Kafka.SchemaRegistryURL != "" {
+		schemaRegistry = schemaregistry.NewClient(cfg.Kafka.SchemaRegistryURL)
+	}
+	decoder := kafkaadapter.NewEventDecoder(schemaRegistry)
+
 	// Kafka Producer (for sending to DLQ)
 	dlqProducer, err := kgo.NewClient(
 		kgo.SeedBrokers(kafkaBrokers...),
@@ -73,108 +104,399 @@ func main() {
 		}
 	}()
 
-	// Fraud Rule Engine: Instantiate our chosen rule engine implementation.
-	// Thanks to the interface, we could easily swap this for a different engine.
-	ruleEngine := antifraud.NewCachingRuleEngine(rdb, cfg.AntiFraud)
-
-	// --- Application Start ---
-
-	// Subscribe to the main transaction topic.
-	consumerClient, err := kgo.NewClient(
-		kgo.SeedBrokers(kafkaBrokers...),
-		kgo.ConsumerGroup("anti-fraud-group"),
-		kgo.ConsumeTopics("transactions.created"),
-		kgo.DisableAutoCommit(), //TODO: Мы будем коммитить offset'ы вручную для большей надежности
-	)
+	// PostgreSQL: transactions with a REVIEW verdict are placed into the manual review queue.
+	repo, err := postgres.NewRepository(context.Background(), cfg.Postgres.DSN)
 	if err != nil {
-		logger.Error("failed to create Kafka consumer:", "error", err)
+		logger.Error("failed to connect to PostgreSQL", "error", err)
 		os.Exit(1)
 	}
-	defer consumerClient.Close()
+	defer repo.Close()
+	// Labels are exported by the payment gateway once the cases are decided.
+	reviewService := app.NewReviewService(repo, nil, time.Duration(cfg.Review.SLAMinutes)*time.Minute, cfg.Review.ExpiryDecision == "approve")
 
 	// Set up graceful shutdown.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	logger.Info("anti-fraud analyzer запущен и готов к работе...")
+	// Fraud Rule Engine: Instantiate our chosen rule engine implementation.
+	// Thanks to the interface, we could easily swap this for a different engine.
+	// The declarative rules are reloaded on change until shutdown.
+	ruleEngine, err := antifraud.NewEngineFromConfig(ctx, rdb, cfg.AntiFraud, logger)
+	if err != nil {
+		logger.Error("failed to load fraud rules", "error", err)
+		os.Exit(1)
+	}
+
+	// Shadow engines: evaluated next to the live engine, their verdicts are only recorded.
+	shadowEngines, err := antifraud.NewShadowEnginesFromConfig(ctx, rdb, cfg.AntiFraud, logger)
+	if err != nil {
+		logger.Error("failed to create shadow engines", "error", err)
+		os.Exit(1)
+	}
 
-	// Main processing loop.
-	run := true
-	for run {
-		select {
-		case <-ctx.Done(): // Exit loop on shutdown signal.
-			run = false
-		default:
-			fetches := consumerClient.PollFetches(ctx)
-			// Проверяем, не был ли клиент закрыт или контекст отменен
-			if fetches.IsClientClosed() || ctx.Err() != nil {
-				break // Выходим из цикла для грациозной остановки
-			}
-			
-			fetches.EachError(func(t string, p int32, err error) {
-				logger.Error("ошибка при чтении из kafka", "topic", t, "partition", p, "error", err)
-			})
-			fetches.EachRecord(func(record *kgo.Record) {
-				var tx domain.Transaction
-				if err := json.Unmarshal(record.Value, &tx); err != nil {
-					logger.Error("Не удалось распарсить сообщение. Отправка в DLQ.", "ERROR", err)
-					sendToDLQ(dlqProducer, record, "unmarshal_error", err.Error())
-					return // Пропускаем обработку этого сообщения
-				}
+	// Feature store: the analyzer is the only writer. Features describe the history before a transaction,
+	// so they are updated after the transaction is scored; its copy in ClickHouse is used for backfills.
+	featureRegistry, err := antifraud.NewFeatureRegistryFromConfig(rdb, cfg.AntiFraud.Features)
+	if err != nil {
+		logger.Error("failed to load fraud features", "error", err)
+		os.Exit(1)
+	}
+	txHistory := chstorage.NewTransactionStore(chConn)
+	reportWriter := app.NewReportWriter(chstorage.NewReportStore(chConn), app.ReportWriterOptions{
+		BatchSize:     cfg.Analyzer.BatchSize,
+		FlushInterval: time.Duration(cfg.Analyzer.FlushIntervalMs) * time.Millisecond,
+		Attempts:      cfg.Analyzer.WriteAttempts,
+		Backoff:       time.Duration(cfg.Analyzer.WriteBackoffMs) * time.Millisecond,
+	}, logger)
+	// The history, the feature values at decision time (joined with fraud labels by ch-query-tool export-training)
+	// and the shadow verdicts are written in batches of their own once the report of a transaction is stored.
+	recorder := &decisionRecorder{
+		conn:      chConn,
+		history:   txHistory,
+		snapshots: chstorage.NewSnapshotStore(chConn),
+		logger:    logger,
+	}
+	decisions := app.NewBatcher(recorder.flush, app.BatcherOptions{
+		BatchSize:     cfg.Analyzer.BatchSize,
+		FlushInterval: time.Duration(cfg.Analyzer.FlushIntervalMs) * time.Millisecond,
+	})
 
-				// Apply our fraud rules to the transaction.
-				result := ruleEngine.CheckTransaction(tx)
-
-				// Persist the analysis result to ClickHouse.
-				err = chConn.Exec(ctx, `
-				INSERT INTO default.fraud_reports (transaction_id, is_fraudulent, reason, card_hash, amount, processed_at) VALUES (?, ?, ?, ?, ?, ?)`,
-				tx.ID,
-				result.IsFraudulent,
-				result.Reason,
-				tx.CardNumberHash,
-				tx.Amount,
-				time.Now(),
-				)
-				
-				if err != nil {
-					logger.Error("Failed to insert into ClickHouse", "ERROR", err, "transaction_id", tx.ID)
-					//TODO: РЕализовать логику повторных попыток
+	// Merchant anomaly job: compares every complete bucket of merchant activity in ClickHouse with its baseline.
+	if cfg.Anomaly.Enabled {
+		detector, err := newAnomalyDetector(cfg.Anomaly, chConn, dlqProducer, logger)
+		if err != nil {
+			logger.Error("failed to create merchant anomaly detector", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.Anomaly.IntervalSeconds) * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
 					return
+				case <-ticker.C:
+					if err := detector.DetectAnomalies(ctx); err != nil {
+						logger.Error("failed to detect merchant anomalies", "error", err)
+					}
 				}
+			}
+		}()
+	}
+
+	// gRPC server: synchronous checks requested by the payment gateway before a transaction is saved.
+	// They use separate velocity counters, otherwise each transaction would be counted twice:
+	// once in pre-authorization and once when its event is consumed below.
+	preAuthCfg := cfg.AntiFraud
+	preAuthCfg.CounterKeyPrefix = "preauth_" + preAuthCfg.CounterKeyPrefix
+	preAuthEngine, err := antifraud.NewEngineFromConfig(ctx, rdb, preAuthCfg, logger)
+	if err != nil {
+		logger.Error("failed to load fraud rules", "error", err)
+		os.Exit(1)
+	}
+	grpcServer := grpcadapter.NewServer(grpcadapter.NewFraudServer(preAuthEngine, logger))
+
+	grpcListener, err := net.Listen("tcp", cfg.Server.PortAntiFraudGrpc)
+	if err != nil {
+		logger.Error("failed to listen for gRPC", "addr", cfg.Server.PortAntiFraudGrpc, "error", err)
+		os.Exit(1)
+	}
+	go func() {
+		logger.Info("gRPC server starting", "addr", grpcListener.Addr().String())
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("gRPC server failed", "error", err)
+		}
+	}()
+	defer grpcServer.GracefulStop()
+
+	// --- Application Start ---
+
+	// Failures that may pass go up the retry ladder: transactions.created.retry.1m, .retry.10m, .retry.1h;
+	// each retry topic is read by its own consumer once the delay of the tier has passed.
+	retryDelays := make([]time.Duration, len(cfg.Analyzer.RetryDelaysSeconds))
+	for i, d := range cfg.Analyzer.RetryDelaysSeconds {
+		retryDelays[i] = time.Duration(d) * time.Second
+	}
+	retryTiers := kafkaadapter.RetryTiers(transactionsTopic, retryDelays)
+	router := kafkaadapter.NewRetryRouter(dlqProducer, retryTiers, dlqTopic)
+
+	// fail routes a record that could not be processed: to the DLQ if it can never succeed,
+	// otherwise one step up the retry ladder. The offset is committed once the record is delivered;
+	// a record that could not be delivered is handled again by the consumer after a pause.
+	fail := func(ctx context.Context, record *kgo.Record, errorType string, cause error, transient bool, done func(error)) {
+		observability.RecordFraudCheckFailure(errorType)
+		delivered := func(err error) {
+			if err != nil {
+				// Критическая ошибка: сообщение не попало ни в retry, ни в DLQ. Offset не коммитится, сообщение обрабатывается повторно.
+				logger.Error("не удалось переотправить сообщение", "ERROR", err, "error_type", errorType)
+			}
+			done(err)
+		}
+		if !transient {
+			router.DeadLetter(ctx, record, errorType, cause, delivered)
+			return
+		}
+		router.Retry(ctx, record, errorType, cause, delivered)
+	}
+
+	handle := func(ctx context.Context, record *kgo.Record, done func(error)) {
+		start := time.Now()
+		event, err := decoder.Decode(ctx, record.Value)
+		if errors.Is(err, schemaregistry.ErrUnavailable) {
+			logger.Error("Schema registry недоступен. Отправка на повтор.", "ERROR", err)
+			fail(ctx, record, "schema_registry_error", err, true, done)
+			return
+		}
+		if err != nil {
+			logger.Error("Не удалось распарсить сообщение. Отправка в DLQ.", "ERROR", err)
+			fail(ctx, record, "unmarshal_error", err, false, done)
+			return // Пропускаем обработку этого сообщения
+		}
+		tx := event.Transaction()
+		// Continue the trace of the payment API that published the event.
+		ctx = event.Context(ctx)
 
-				logger.Info("транзакция успешно обработана", "transaction_id", tx.ID, "amount=%.2f", tx.Amount, "is_fraudulent", result.IsFraudulent)
+		// Apply our fraud rules to the transaction.
+		// "Could not evaluate" is not "clean": after the retries the event goes up the retry ladder.
+		result, err := checkWithRetry(ctx, ruleEngine, tx)
+		if err != nil {
+			logger.Error("Не удалось проверить транзакцию. Отправка на повтор.", "ERROR", err, "transaction_id", tx.ID)
+			fail(ctx, record, "evaluation_error", err, true, done)
+			return
+		}
 
-			})
+		if result.Decision == domain.DecisionReview {
+			if err := reviewService.OpenCase(ctx, tx, result); err != nil {
+				logger.Error("Не удалось открыть дело ручной проверки. Отправка на повтор.", "ERROR", err, "transaction_id", tx.ID)
+				fail(ctx, record, "review_case_error", err, true, done)
+				return
+			}
+		}
+
+		// Shadow engines and the feature snapshot run on the worker, next to the live check. Features are updated
+		// right away too, so the next transaction of the card sees this one; a redelivered transaction is not
+		// accounted twice. What is stored in ClickHouse waits for the report, see decisionRecorder.
+		var shadows []antifraud.ShadowResult
+		if len(shadowEngines) > 0 {
+			shadows = antifraud.EvaluateShadows(ctx, shadowEngines, tx)
+		}
+		var snapshot *domain.FeatureSnapshot
+		if featureRegistry != nil {
+			// Снимок берётся до обновления: это те же значения, что видели движки.
+			snapshot = featureSnapshot(ctx, featureRegistry, logger, tx, result)
+			if err := featureRegistry.Update(ctx, []domain.Transaction{tx}); err != nil {
+				logger.Error("не удалось обновить признаки антифрода", "error", err, "transaction_id", tx.ID)
+			}
+		}
 
-			// Commit offsets after successfully processing a batch of messages
-			if err := consumerClient.CommitUncommittedOffsets(ctx); err != nil {
-				logger.Error("error committing offsets", "error", err)
+		// Persist the analysis result, with its explanation, to ClickHouse.
+		// The offset is committed only once the batch with the report, then the batch with the rest, is written:
+		// a record sent to retry must not count in the metrics or the history twice.
+		// The callback runs for the whole batch of reports, so it only hands the decision on.
+		reportWriter.Write(domain.FraudReport{
+			TransactionID: tx.ID,
+			ProcessedAt:   time.Now(),
+			CardHash:      tx.CardNumberHash,
+			MerchantID:    tx.MerchantID,
+			Country:       tx.Country(),
+			Amount:        tx.Amount,
+			Result:        result,
+		}, fmt.Sprintf("%s:%d:%d", record.Topic, record.Partition, record.Offset), func(err error) {
+			if err != nil {
+				logger.Error("Failed to insert into ClickHouse. Отправка на повтор.", "ERROR", err, "transaction_id", tx.ID)
+				fail(ctx, record, "storage_error", err, true, done)
+				return
 			}
-			
+
+			observability.RecordFraudCheck(string(result.Decision), result.TriggeredRules, time.Since(start))
+			logger.Info("транзакция успешно обработана", "transaction_id", tx.ID, "amount=%.2f", tx.Amount, "is_fraudulent", result.IsFraudulent, "decision", result.Decision)
+			decisions.Add(decision{tx: tx, result: result, shadows: shadows, snapshot: snapshot, done: done})
+		})
+	}
+
+	// Subscribe to the main transaction topic and to the retry topics.
+	// Transactions of different cards are checked in parallel, the transactions of a card in order:
+	// velocity counters and features depend on the previous transactions of the card.
+	consumerOptions := func(group, topic string, delay time.Duration) kafkaadapter.ConsumerOptions {
+		return kafkaadapter.ConsumerOptions{
+			Brokers:              kafkaBrokers,
+			Group:                group,
+			Topics:               []string{topic},
+			Workers:              cfg.Analyzer.Workers,
+			MaxInFlight:          cfg.Analyzer.MaxInFlight,
+			MaxPartitionInFlight: cfg.Analyzer.MaxPartitionInFlight,
+			CommitInterval:       time.Duration(cfg.Analyzer.CommitIntervalMs) * time.Millisecond,
+			Delay:                delay,
+		}
+	}
+	var consumers consumerSet
+	lags := make(map[string]func() map[string]map[int32]int64)
+	groups := []kafkaadapter.ConsumerOptions{consumerOptions(consumerGroup, transactionsTopic, 0)}
+	for _, tier := range retryTiers {
+		groups = append(groups, consumerOptions(consumerGroup+"."+tier.Topic, tier.Topic, tier.Delay))
+	}
+	for _, opts := range groups {
+		consumer, err := kafkaadapter.NewConsumer(opts, handle, logger)
+		if err != nil {
+			logger.Error("failed to create Kafka consumer:", "error", err, "group", opts.Group)
+			os.Exit(1)
 		}
+		defer consumer.Close()
+		consumers = append(consumers, consumer)
+		lags[opts.Group] = consumer.Lag
 	}
 
+	prometheus.MustRegister(observability.NewConsumerLagCollector(lags))
+	controlHandler := httphandler.NewControlHandler([]httphandler.ReadinessCheck{
+		{Name: "kafka", Check: consumers[0].Client().Ping},
+		{Name: "redis", Check: func(ctx context.Context) error { return rdb.Ping(ctx).Err() }},
+		{Name: "clickhouse", Check: chConn.Ping},
+	}, consumers, logger)
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Get("/healthz", controlHandler.HandleHealthz)
+	r.Get("/readyz", controlHandler.HandleReadyz)
+	r.Handle("/metrics", promhttp.Handler())
+	defer serveHTTP(cfg.Server.PortAntiFraudHTTP, r, "control", logger)()
+
+	// Admin: остановить чтение из Kafka, например на время работ с ClickHouse.
+	// Без аутентификации, поэтому на отдельном порту, по умолчанию только на localhost.
+	admin := chi.NewRouter()
+	admin.Use(middleware.Recoverer)
+	admin.Post("/admin/consumer/pause", controlHandler.HandlePause)
+	admin.Post("/admin/consumer/resume", controlHandler.HandleResume)
+	defer serveHTTP(cfg.Server.PortAntiFraudAdmin, admin, "admin", logger)()
+
+	logger.Info("anti-fraud analyzer запущен и готов к работе...", "workers", cfg.Analyzer.Workers)
+
+	// The writers outlive the consumer: the consumer waits for the reports and the decisions of the records in flight.
+	writerCtx, stopWriter := context.WithCancel(context.WithoutCancel(ctx))
+	var writers sync.WaitGroup
+	writers.Go(func() { reportWriter.Run(writerCtx) })
+	writers.Go(func() { decisions.Run(writerCtx) })
+
+	// Main processing loop: returns on shutdown signal once the records in flight are checked and committed.
+	consumers.Run(ctx)
+	stopWriter()
+	writers.Wait()
+
 	logger.Info("anti-fraud analyzer останавливается...")
 }
 
-// sendToDLQ sends the original malformed message to the Dead-Letter Queue.
-func sendToDLQ(p *kgo.Client, originalRecord *kgo.Record, errorType, errorString string) {
-	dlqRecord := &kgo.Record{
-		Topic: dlqTopic,
-		Value: originalRecord.Value,
-		Key:   originalRecord.Key,
-		// Add headers with metadata about the failure for easier debugging.
-		Headers: []kgo.RecordHeader{
-			{Key: "error_type", Value: []byte(errorType)},
-			{Key: "error_string", Value: []byte(errorString)},
-			{Key: "original_topic", Value: []byte(originalRecord.Topic)},
-		},
-	}
-	// Sending asynchronously with a callback
-	p.Produce(context.Background(), dlqRecord, func(r *kgo.Record, err error) {
-		if err != nil {
-			// Критическая ошибка: потеря сообщения в DLQ недопустима.			
-			fmt.Fprintf(os.Stderr, "FATAL: Не удалось отправить сообщение в DLQ: %v\n", err)
+// serveHTTP starts an HTTP server on addr and returns the function that shuts it down.
+func serveHTTP(addr string, handler http.Handler, name string, logger *slog.Logger) func() {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		logger.Info("HTTP "+name+" server starting", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("HTTP "+name+" server failed", "error", err)
 		}
-	})
+	}()
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("HTTP "+name+" server shutdown failed", "error", err)
+		}
+	}
+}
+
+// featureSnapshot reads the declared features of tx as the engines saw them. A failure only costs
+// the transaction its place in training datasets, so it is logged and processing goes on.
+func featureSnapshot(ctx context.Context, registry *features.Registry, logger *slog.Logger, tx domain.Transaction, result domain.FraudResult) *domain.FeatureSnapshot {
+	values, err := registry.Get(ctx, tx, registry.Names())
+	if err != nil {
+		logger.Error("не удалось прочитать снимок признаков", "error", err, "transaction_id", tx.ID)
+		return nil
+	}
+	return &domain.FeatureSnapshot{
+		TransactionID: tx.ID,
+		DecidedAt:     time.Now(),
+		EngineVersion: result.EngineVersion,
+		Features:      values,
+	}
+}
+
+// newAnomalyDetector publishes the anomalies to Kafka and, if configured, to the alerter-service webhook.
+func newAnomalyDetector(cfg config.AnomalyConfig, conn clickhouse.Conn, producer *kgo.Client, logger *slog.Logger) (*anomaly.Detector, error) {
+	zScores := make(map[domain.MerchantMetric]float64, len(cfg.ZScores))
+	for metric, z := range cfg.ZScores {
+		zScores[domain.MerchantMetric(metric)] = z
+	}
+	publishers := []ports.AnomalyPublisher{kafkaadapter.NewAnomalyPublisher(producer, cfg.KafkaTopic)}
+	if cfg.AlerterURL != "" {
+		publishers = append(publishers, alerting.NewWebhook(cfg.AlerterURL))
+	}
+	return anomaly.NewDetector(chstorage.NewMerchantActivitySource(conn), anomaly.Options{
+		Bucket:          time.Duration(cfg.BucketMinutes) * time.Minute,
+		History:         time.Duration(cfg.HistoryDays) * 24 * time.Hour,
+		Baseline:        anomaly.Baseline(cfg.Baseline),
+		Alpha:           cfg.EWMAAlpha,
+		MinHistory:      cfg.MinHistoryBuckets,
+		MinTransactions: cfg.MinTransactions,
+		ZScores:         zScores,
+	}, logger, publishers...)
+}
+
+// evaluationAttempts is how many times a transaction is checked before it is considered unevaluable.
+const evaluationAttempts = 3
+
+// checkWithRetry runs the engine with a short exponential backoff between attempts.
+func checkWithRetry(ctx context.Context, engine domain.FraudRuleEngine, tx domain.Transaction) (domain.FraudResult, error) {
+	backoff := 100 * time.Millisecond
+	var err error
+	for attempt := 1; attempt <= evaluationAttempts; attempt++ {
+		var result domain.FraudResult
+		result, err = engine.CheckTransaction(ctx, tx)
+		if err == nil {
+			return result, nil
+		}
+		if attempt == evaluationAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return domain.FraudResult{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return domain.FraudResult{}, fmt.Errorf("fraud check failed after %d attempts: %w", evaluationAttempts, err)
+}
+
+// consumerSet runs the consumers of the main topic and of the retry topics together.
+type consumerSet []*kafkaadapter.Consumer
+
+// Run runs every consumer until ctx is cancelled.
+func (cs consumerSet) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range cs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (cs consumerSet) Pause() {
+	for _, c := range cs {
+		c.Pause()
+	}
+}
+
+func (cs consumerSet) Resume() {
+	for _, c := range cs {
+		c.Resume()
+	}
+}
+
+func (cs consumerSet) Paused() bool {
+	return cs[0].Paused()
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+
+	chstorage "payment-processing-system/internal/adapters/storage/clickhouse"
+	"payment-processing-system/internal/antifraud"
+	"payment-processing-system/internal/core/domain"
+)
+
+// decision is a checked transaction whose fraud report is written. What the worker gathered while checking it
+// is stored in batches by decisionRecorder, then done completes its record.
+type decision struct {
+	tx      domain.Transaction
+	result  domain.FraudResult
+	shadows []antifraud.ShadowResult
+	// snapshot holds the features the engines saw; nil without declared features or when they could not be read.
+	snapshot *domain.FeatureSnapshot
+	done     func(error)
+}
+
+// decisionRecorder writes the history, the feature snapshots and the shadow verdicts of the decisions.
+// A failure is only logged: the report, the record of the decision, is already stored.
+type decisionRecorder struct {
+	conn      clickhouse.Conn
+	history   *chstorage.TransactionStore
+	snapshots *chstorage.SnapshotStore
+	logger    *slog.Logger
+}
+
+// flush stores a batch of decisions with one insert per table and completes their records.
+func (r *decisionRecorder) flush(ctx context.Context, decisions []decision) {
+	txs := make([]domain.Transaction, len(decisions))
+	var snapshots []domain.FeatureSnapshot
+	shadows := false
+	for i, d := range decisions {
+		txs[i] = d.tx
+		if d.snapshot != nil {
+			snapshots = append(snapshots, *d.snapshot)
+		}
+		shadows = shadows || len(d.shadows) > 0
+	}
+
+	if err := r.history.SaveTransactions(ctx, txs); err != nil {
+		r.logger.Error("не удалось сохранить транзакции в историю", "error", err, "transactions", len(txs))
+	}
+	if len(snapshots) > 0 {
+		if err := r.snapshots.SaveFeatureSnapshots(ctx, snapshots); err != nil {
+			r.logger.Error("не удалось сохранить снимки признаков", "error", err, "transactions", len(snapshots))
+		}
+	}
+	if shadows {
+		recordShadows(ctx, r.conn, r.logger, decisions)
+	}
+	for _, d := range decisions {
+		d.done(nil)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// recordShadows stores the shadow verdicts of a batch of decisions next to the live ones.
+// Shadow failures are only logged: they must never affect the processing of the transactions.
+func recordShadows(ctx context.Context, conn clickhouse.Conn, logger *slog.Logger, decisions []decision) {
+	batch, err := conn.PrepareBatch(ctx, `INSERT INTO default.fraud_shadow_reports (transaction_id, engine, engine_version, decision, risk_score, triggered_rules, live_decision, error, processed_at)`)
+	if err != nil {
+		logger.Error("failed to prepare shadow batch", "error", err, "transactions", len(decisions))
+		return
+	}
+
+	now := time.Now()
+	rows := 0
+	for _, d := range decisions {
+		for _, r := range d.shadows {
+			var errText string
+			if r.Err != nil {
+				errText = r.Err.Error()
+				logger.Warn("shadow engine failed", "engine", r.Engine, "error", r.Err, "transaction_id", d.tx.ID)
+			}
+			rules := r.Result.TriggeredRules
+			if rules == nil {
+				rules = []string{}
+			}
+			if err := batch.Append(d.tx.ID, r.Engine, r.Result.EngineVersion, string(r.Result.Decision), r.Result.RiskScore, rules, string(d.result.Decision), errText, now); err != nil {
+				logger.Error("failed to append shadow result", "error", err, "engine", r.Engine)
+				_ = batch.Abort()
+				return
+			}
+			rows++
+		}
+	}
+	if rows == 0 {
+		_ = batch.Abort()
+		return
+	}
+	if err := batch.Send(); err != nil {
+		logger.Error("failed to insert shadow results into ClickHouse", "error", err, "transactions", len(decisions))
+	}
+}
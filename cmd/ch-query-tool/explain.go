@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	chstorage "payment-processing-system/internal/adapters/storage/clickhouse"
+	"payment-processing-system/internal/core/domain"
+)
+
+// newExplainCmd renders the stored explanation of the latest fraud check of a transaction.
+func newExplainCmd(dsn *string) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "explain <transaction_id>",
+		Short: "Explain why a transaction was allowed, declined or sent to review",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				log.Fatalf("Неверный transaction_id: %v", err)
+			}
+			conn := connect(*dsn)
+			defer func() {
+				if err := conn.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть ClickHouse connection: %v", err)
+				}
+			}()
+
+			report, err := chstorage.NewReportStore(conn).GetFraudReport(context.Background(), id)
+			if errors.Is(err, domain.ErrFraudReportNotFound) {
+				log.Fatalf("Транзакция %s ещё не проверялась антифродом", id)
+			}
+			if err != nil {
+				log.Fatalf("Query failed: %v", err)
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					log.Fatal(err)
+				}
+				return
+			}
+			printExplanation(os.Stdout, report)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the stored report as JSON")
+	return cmd
+}
+
+func printExplanation(out io.Writer, report domain.FraudReport) {
+	r := report.Result
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "Transaction\t%s\n", report.TransactionID)
+	fmt.Fprintf(w, "Checked at\t%s\n", report.ProcessedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Decision\t%s (risk score %.3f)\n", r.Decision, r.RiskScore)
+	fmt.Fprintf(w, "Engine\t%s\n", r.EngineVersion)
+	if r.Reason != "" {
+		fmt.Fprintf(w, "Reason\t%s\n", r.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Не удалось закрыть writer: %v", err)
+	}
+
+	if r.Explanation != nil {
+		printEngineExplanation(out, "", *r.Explanation)
+	}
+	for _, c := range r.Contributions {
+		status := fmt.Sprintf("%s, risk score %.3f, weight %g", c.Decision, c.RiskScore, c.Weight)
+		switch {
+		case c.Skipped:
+			status = "skipped"
+		case c.Error != "":
+			status = "error: " + c.Error
+		}
+		fmt.Fprintf(out, "\nEngine %s (%s)\n", c.Engine, status)
+		if c.Explanation != nil {
+			printEngineExplanation(out, "  ", *c.Explanation)
+		}
+	}
+}
+
+// printEngineExplanation prints the thresholds, the rules and the inputs of one engine, indented by indent.
+func printEngineExplanation(out io.Writer, indent string, e domain.FraudExplanation) {
+	if e.ReviewThreshold > 0 || e.DeclineThreshold > 0 {
+		fmt.Fprintf(out, "\n%sScore %.3f against thresholds: review >= %g, decline >= %g\n", indent, e.Score, e.ReviewThreshold, e.DeclineThreshold)
+	}
+
+	if len(e.Rules) > 0 {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%sMATCHED\tRULE\tACTION\tSCORE\tCONDITION\n", indent)
+		for _, rule := range e.Rules {
+			matched := "no"
+			if rule.Matched {
+				matched = "YES"
+			}
+			condition := rule.Condition
+			if condition == "" {
+				condition = rule.Description
+			}
+			fmt.Fprintf(w, "%s%s\t%s\t%s\t%.3f\t%s\n", indent, matched, rule.Name, rule.Action, rule.Score, condition)
+		}
+		if err := w.Flush(); err != nil {
+			log.Fatalf("Не удалось закрыть writer: %v", err)
+		}
+	}
+
+	if len(e.Inputs) > 0 {
+		names := make([]string, 0, len(e.Inputs))
+		for name := range e.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%sINPUT\tVALUE\n", indent)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s%s\t%s\n", indent, name, inputValue(e.Inputs[name]))
+		}
+		if err := w.Flush(); err != nil {
+			log.Fatalf("Не удалось закрыть writer: %v", err)
+		}
+	}
+}
+
+func inputValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "unknown"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case float64:
+		return strings.TrimSuffix(fmt.Sprintf("%.4f", v), ".0000")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+
+	chstorage "payment-processing-system/internal/adapters/storage/clickhouse"
+	redisadapter "payment-processing-system/internal/adapters/storage/redis"
+	"payment-processing-system/internal/antifraud/features"
+	"payment-processing-system/internal/core/domain"
+)
+
+// newBackfillFeaturesCmd rebuilds the online fraud features in Redis from the transaction history.
+func newBackfillFeaturesCmd(dsn *string) *cobra.Command {
+	var (
+		file          string
+		only          []string
+		since         time.Duration
+		redisAddr     string
+		keyPrefix     string
+		retentionDays int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backfill-features",
+		Short: "Recompute fraud features in Redis from the ClickHouse transaction history",
+		Long: "Resets the selected features (all declared ones by default) and recomputes them from the transactions\n" +
+			"created within --since. Run it after declaring a new feature, before rules or models rely on it.",
+		Run: func(_ *cobra.Command, _ []string) {
+			defs, err := features.LoadFile(file)
+			if err != nil {
+				log.Fatalf("Не удалось загрузить признаки: %v", err)
+			}
+			if defs, err = selectFeatures(defs, only); err != nil {
+				log.Fatal(err)
+			}
+
+			conn := connect(*dsn)
+			defer func() {
+				if err := conn.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть ClickHouse connection: %v", err)
+				}
+			}()
+			rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+			defer func() {
+				if err := rdb.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть redis connection: %v", err)
+				}
+			}()
+
+			store := redisadapter.NewFeatureStoreAdapter(rdb, keyPrefix, time.Duration(retentionDays)*24*time.Hour)
+			started := time.Now()
+			n, err := features.Backfill(context.Background(), chstorage.NewTransactionStore(conn), store, defs, started.Add(-since))
+			if err != nil {
+				log.Fatalf("Backfill failed after %d transactions: %v", n, err)
+			}
+			fmt.Printf("Recomputed %d features from %d transactions in %s\n", len(defs), n, time.Since(started).Round(time.Second))
+		},
+	}
+	cmd.Flags().StringVar(&file, "features", "configs/features.yaml", "Feature definition file")
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Recompute only these features")
+	cmd.Flags().DurationVar(&since, "since", 90*24*time.Hour, "How much history to replay")
+	cmd.Flags().StringVar(&redisAddr, "redis", "localhost:6379", "Redis address")
+	cmd.Flags().StringVar(&keyPrefix, "key-prefix", "fraud_features", "Feature key prefix (anti_fraud.features.key_prefix)")
+	cmd.Flags().IntVar(&retentionDays, "retention-days", 180, "Lifetime feature retention (anti_fraud.features.retention_days)")
+	return cmd
+}
+
+// selectFeatures keeps the named definitions; no names keep all of them.
+func selectFeatures(defs []domain.FeatureDefinition, names []string) ([]domain.FeatureDefinition, error) {
+	if len(names) == 0 {
+		return defs, nil
+	}
+	byName := make(map[string]domain.FeatureDefinition, len(defs))
+	for _, d := range defs {
+		byName[d.Name] = d
+	}
+	selected := make([]domain.FeatureDefinition, 0, len(names))
+	for _, name := range names {
+		d, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("feature %q is not declared", name)
+		}
+		selected = append(selected, d)
+	}
+	return selected, nil
+}
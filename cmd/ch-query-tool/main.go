@@ -31,7 +31,7 @@ func main() {
 				}
 			}()
 
-			query := "SELECT transaction_id, reason, processed_at FROM fraud_reports WHERE is_fraudulent = 1 ORDER BY processed_at DESC LIMIT 20"
+			query := "SELECT transaction_id, reason, processed_at FROM fraud_reports FINAL WHERE is_fraudulent = 1 ORDER BY processed_at DESC LIMIT 20"
 			rows, err := conn.Query(context.Background(), query)
 			if err != nil {
 				log.Fatalf("Query failed: %v", err)
@@ -43,7 +43,7 @@ func main() {
 				}
 			}()
 
-			rows, err = conn.Query(context.Background(), "SELECT transaction_id, reason, processed_at FROM fraud_reports WHERE is_fraudulent = 1 ORDER BY processed_at DESC LIMIT 20")
+			rows, err = conn.Query(context.Background(), "SELECT transaction_id, reason, processed_at FROM fraud_reports FINAL WHERE is_fraudulent = 1 ORDER BY processed_at DESC LIMIT 20")
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -95,7 +95,7 @@ func main() {
 			}()
 
 			// Forming a SQL query for data aggregation
-			query := "SELECT card_hash, count(*) AS total FROM fraud_reports GROUP BY card_hash ORDER BY total DESC LIMIT ?"
+			query := "SELECT card_hash, count(*) AS total FROM fraud_reports FINAL GROUP BY card_hash ORDER BY total DESC LIMIT ?"
 			rows, err := conn.Query(context.Background(), query, limit)
 			if err != nil {
 				log.Fatalf("Query failed: %v", err)
@@ -128,7 +128,7 @@ func main() {
 	topCardsCmd.Flags().Int("limit", 10, "Number of top cards to show")
 	//TODO: Логика для top-cards...
 
-	rootCmd.AddCommand(suspiciousCmd, topCardsCmd)
+	rootCmd.AddCommand(suspiciousCmd, topCardsCmd, newShadowCompareCmd(&dsn), newBackfillFeaturesCmd(&dsn), newExportTrainingCmd(), newRingCmd(&dsn), newExplainCmd(&dsn))
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Ошибка выполнения команды: %v", err)
 	}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/spf13/cobra"
+)
+
+// ringColumns maps the linking attributes to their columns in default.transactions.
+var ringColumns = []struct{ kind, column string }{
+	{"device", "device_id"},
+	{"email", "email_hash"},
+	{"ip", "ip"},
+}
+
+// ringAttribute is an attribute reached while walking a cluster.
+type ringAttribute struct {
+	kind, value string
+	cards       uint64
+	hub         bool
+}
+
+// newRingCmd shows the cluster of cards linked to a card through shared devices, emails and IPs.
+// The cluster is rebuilt from the transaction history with the same hub rule as the analyzer:
+// an attribute seen with more than --hub-cards cards is listed but does not link further cards.
+func newRingCmd(dsn *string) *cobra.Command {
+	var (
+		depth    int
+		maxCards int
+		hubCards int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ring <card_hash>",
+		Short: "Inspect the cluster of cards linked to a card through shared devices, emails and IPs",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			conn := connect(*dsn)
+			defer func() {
+				if err := conn.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть ClickHouse connection: %v", err)
+				}
+			}()
+			ctx := context.Background()
+
+			cards := map[string]int{args[0]: 0} // card -> hop distance
+			var attrs []ringAttribute
+			seenAttrs := make(map[string]bool)
+			frontier := []string{args[0]}
+			truncated := false
+			for hop := 1; hop <= depth && len(frontier) > 0 && !truncated; hop++ {
+				var next []string
+				for _, rc := range ringColumns {
+					rows, err := conn.Query(ctx, `
+						SELECT `+rc.column+` AS value, uniqExact(card_hash) AS cards, groupUniqArray(?)(card_hash) AS linked
+						FROM default.transactions
+						WHERE `+rc.column+` IN (
+						    SELECT DISTINCT `+rc.column+` FROM default.transactions WHERE card_hash IN ? AND `+rc.column+` != ''
+						)
+						GROUP BY value`, hubCards+1, frontier)
+					if err != nil {
+						log.Fatalf("Query failed: %v", err)
+					}
+					for rows.Next() {
+						var a ringAttribute
+						var linked []string
+						if err := rows.Scan(&a.value, &a.cards, &linked); err != nil {
+							log.Fatal(err)
+						}
+						key := rc.kind + ":" + a.value
+						if seenAttrs[key] {
+							continue
+						}
+						seenAttrs[key] = true
+						a.kind = rc.kind
+						a.hub = a.cards > uint64(hubCards)
+						attrs = append(attrs, a)
+						if a.hub {
+							continue
+						}
+						for _, c := range linked {
+							if _, ok := cards[c]; ok {
+								continue
+							}
+							if len(cards) >= maxCards {
+								truncated = true
+								break
+							}
+							cards[c] = hop
+							next = append(next, c)
+						}
+					}
+					if err := rows.Close(); err != nil {
+						log.Fatalf("Не удалось закрыть: %v", err)
+					}
+				}
+				frontier = next
+			}
+
+			printRing(ctx, conn, args[0], cards, attrs, truncated)
+		},
+	}
+	cmd.Flags().IntVar(&depth, "depth", 3, "How many card-attribute-card hops to follow")
+	cmd.Flags().IntVar(&maxCards, "max-cards", 200, "Stop after this many cards")
+	cmd.Flags().IntVar(&hubCards, "hub-cards", 100, "Attributes seen with more cards do not link cards (anti_fraud.rings.hub_cards)")
+	return cmd
+}
+
+func printRing(ctx context.Context, conn clickhouse.Conn, root string, cards map[string]int, attrs []ringAttribute, truncated bool) {
+	hashes := make([]string, 0, len(cards))
+	for c := range cards {
+		hashes = append(hashes, c)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		if cards[hashes[i]] != cards[hashes[j]] {
+			return cards[hashes[i]] < cards[hashes[j]]
+		}
+		return hashes[i] < hashes[j]
+	})
+
+	type cardSummary struct {
+		txs                 uint64
+		amount              float64
+		firstSeen, lastSeen time.Time
+		declines, reviews   uint64
+	}
+	summaries := make(map[string]*cardSummary, len(hashes))
+	for _, h := range hashes {
+		summaries[h] = &cardSummary{}
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT card_hash, count() AS txs, sum(amount) AS total, min(created_at) AS first_seen, max(created_at) AS last_seen
+		FROM default.transactions FINAL
+		WHERE card_hash IN ?
+		GROUP BY card_hash`, hashes)
+	if err != nil {
+		log.Fatalf("Query failed: %v", err)
+	}
+	for rows.Next() {
+		var h string
+		var s cardSummary
+		if err := rows.Scan(&h, &s.txs, &s.amount, &s.firstSeen, &s.lastSeen); err != nil {
+			log.Fatal(err)
+		}
+		*summaries[h] = s
+	}
+	if err := rows.Close(); err != nil {
+		log.Fatalf("Не удалось закрыть: %v", err)
+	}
+
+	rows, err = conn.Query(ctx, `
+		SELECT card_hash, countIf(decision = 'DECLINE') AS declines, countIf(decision = 'REVIEW') AS reviews
+		FROM default.fraud_reports FINAL
+		WHERE card_hash IN ?
+		GROUP BY card_hash`, hashes)
+	if err != nil {
+		log.Fatalf("Query failed: %v", err)
+	}
+	for rows.Next() {
+		var h string
+		var declines, reviews uint64
+		if err := rows.Scan(&h, &declines, &reviews); err != nil {
+			log.Fatal(err)
+		}
+		summaries[h].declines, summaries[h].reviews = declines, reviews
+	}
+	if err := rows.Close(); err != nil {
+		log.Fatalf("Не удалось закрыть: %v", err)
+	}
+
+	shared := 0
+	for _, a := range attrs {
+		if a.cards > 1 {
+			shared++
+		}
+	}
+	fmt.Printf("Cluster of %s: %d cards, %d attributes (%d shared)\n", root, len(hashes), len(attrs), shared)
+	if truncated {
+		fmt.Println("The cluster is larger than --max-cards; only the nearest cards are shown.")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "CARD HASH\tHOPS\tTXS\tAMOUNT\tDECLINES\tREVIEWS\tFIRST SEEN\tLAST SEEN"); err != nil {
+		log.Fatalf("Не удалось записать в writer: %v", err)
+	}
+	for _, h := range hashes {
+		s := summaries[h]
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%d\t%d\t%s\t%s\n", h, cards[h], s.txs, s.amount, s.declines, s.reviews,
+			s.firstSeen.Format(time.RFC3339), s.lastSeen.Format(time.RFC3339)); err != nil {
+			log.Fatalf("Не удалось записать в writer: %v", err)
+		}
+	}
+	if _, err := fmt.Fprintln(w, "\nATTRIBUTE\tVALUE\tCARDS\t"); err != nil {
+		log.Fatalf("Не удалось записать в writer: %v", err)
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].cards > attrs[j].cards })
+	for _, a := range attrs {
+		note := ""
+		if a.hub {
+			note = "hub, not followed"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", a.kind, a.value, a.cards, note); err != nil {
+			log.Fatalf("Не удалось записать в writer: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Не удалось закрыть writer: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newShadowCompareCmd compares the verdicts of shadow engines with the live verdicts.
+func newShadowCompareCmd(dsn *string) *cobra.Command {
+	var since time.Duration
+	var engine string
+	var examples int
+
+	cmd := &cobra.Command{
+		Use:   "shadow-compare",
+		Short: "Compare shadow engine decisions with live decisions",
+		Run: func(_ *cobra.Command, _ []string) {
+			conn := connect(*dsn)
+			defer func() {
+				if err := conn.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть ClickHouse connection: %v", err)
+				}
+			}()
+
+			where := "processed_at >= ?"
+			args := []any{time.Now().Add(-since)}
+			if engine != "" {
+				where += " AND engine = ?"
+				args = append(args, engine)
+			}
+
+			// Rates are computed over the transactions the shadow engine could evaluate.
+			query := `
+				SELECT engine,
+				       countIf(error = '')                                     AS evaluated,
+				       countIf(error != '')                                    AS failed,
+				       countIf(error = '' AND live_decision = 'DECLINE')       AS live_declines,
+				       countIf(error = '' AND decision = 'DECLINE')            AS shadow_declines,
+				       countIf(error = '' AND live_decision = 'REVIEW')        AS live_reviews,
+				       countIf(error = '' AND decision = 'REVIEW')             AS shadow_reviews,
+				       countIf(error = '' AND decision != live_decision)       AS disagreements
+				FROM fraud_shadow_reports FINAL
+				WHERE ` + where + `
+				GROUP BY engine
+				ORDER BY engine`
+			rows, err := conn.Query(context.Background(), query, args...)
+			if err != nil {
+				log.Fatalf("Query failed: %v", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			if _, err := fmt.Fprintln(w, "ENGINE\tEVALUATED\tFAILED\tLIVE DECLINE\tSHADOW DECLINE\tLIVE REVIEW\tSHADOW REVIEW\tDISAGREEMENT"); err != nil {
+				log.Fatalf("Не удалось записать в writer: %v", err)
+			}
+			for rows.Next() {
+				var name string
+				var evaluated, failed, liveDeclines, shadowDeclines, liveReviews, shadowReviews, disagreements uint64
+				if err := rows.Scan(&name, &evaluated, &failed, &liveDeclines, &shadowDeclines, &liveReviews, &shadowReviews, &disagreements); err != nil {
+					log.Fatal(err)
+				}
+				if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", name, evaluated, failed,
+					rate(liveDeclines, evaluated), rate(shadowDeclines, evaluated),
+					rate(liveReviews, evaluated), rate(shadowReviews, evaluated),
+					rate(disagreements, evaluated),
+				); err != nil {
+					log.Fatalf("Не удалось записать в writer: %v", err)
+				}
+			}
+			if err := rows.Close(); err != nil {
+				log.Fatalf("Не удалось закрыть: %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				log.Fatalf("Не удалось закрыть writer: %v", err)
+			}
+
+			if examples <= 0 {
+				return
+			}
+
+			rows, err = conn.Query(context.Background(), `
+				SELECT transaction_id, engine, live_decision, decision, risk_score, triggered_rules, processed_at
+				FROM fraud_shadow_reports FINAL
+				WHERE `+where+` AND error = '' AND decision != live_decision
+				ORDER BY processed_at DESC
+				LIMIT ?`, append(args, examples)...)
+			if err != nil {
+				log.Fatalf("Query failed: %v", err)
+			}
+			defer func() {
+				if err := rows.Close(); err != nil {
+					log.Fatalf("Не удалось закрыть: %v", err)
+				}
+			}()
+
+			fmt.Println()
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			if _, err := fmt.Fprintln(w, "TRANSACTION ID\tENGINE\tLIVE\tSHADOW\tSCORE\tSHADOW RULES\tPROCESSED AT"); err != nil {
+				log.Fatalf("Не удалось записать в writer: %v", err)
+			}
+			for rows.Next() {
+				var id, name, live, shadow string
+				var score float64
+				var rules []string
+				var processedAt time.Time
+				if err := rows.Scan(&id, &name, &live, &shadow, &score, &rules, &processedAt); err != nil {
+					log.Fatal(err)
+				}
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s\t%s\n", id, name, live, shadow, score,
+					strings.Join(rules, ","), processedAt.Format(time.RFC3339)); err != nil {
+					log.Fatalf("Не удалось записать в writer: %v", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				log.Fatalf("Не удалось закрыть writer: %v", err)
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&since, "since", 24*time.Hour, "Time window to compare, e.g. 1h or 168h")
+	cmd.Flags().StringVar(&engine, "engine", "", "Only compare this shadow engine")
+	cmd.Flags().IntVar(&examples, "examples", 10, "Number of recent disagreements to list (0 to skip)")
+	return cmd
+}
+
+// rate formats part/total as a percentage.
+func rate(part, total uint64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", float64(part)*100/float64(total))
+}
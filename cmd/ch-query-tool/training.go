@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"payment-processing-system/internal/antifraud/features"
+)
+
+// chDateTime is the parameter format of DateTime64 values in the ClickHouse HTTP interface; values are in UTC.
+const chDateTime = "2006-01-02 15:04:05.000"
+
+// newExportTrainingCmd exports a training dataset: every transaction with its feature values at decision time
+// and the fraud label known as of --as-of.
+//
+// The dataset is point-in-time correct: features come from the snapshot taken when the transaction was scored,
+// labels recorded after --as-of are ignored, and transactions younger than --label-delay before --as-of are left
+// out because their chargebacks may still be on the way. Unlabeled transactions are exported as legitimate.
+//
+// The rows are formatted by ClickHouse itself, so the command talks to its HTTP interface.
+func newExportTrainingCmd() *cobra.Command {
+	var (
+		httpURL     string
+		file        string
+		out         string
+		format      string
+		from, to    string
+		asOf        string
+		labelDelay  time.Duration
+		labeledOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-training",
+		Short: "Export a point-in-time correct training dataset (CSV or Parquet)",
+		Run: func(_ *cobra.Command, _ []string) {
+			var chFormat string
+			switch format {
+			case "csv":
+				chFormat = "CSVWithNames"
+			case "parquet":
+				chFormat = "Parquet"
+			default:
+				log.Fatalf("Unknown format %q: expected csv or parquet", format)
+			}
+
+			cutoff := time.Now().UTC()
+			if asOf != "" {
+				cutoff = mustParseTime("as-of", asOf)
+			}
+			end := cutoff.Add(-labelDelay)
+			if to != "" {
+				if end = mustParseTime("to", to); end.After(cutoff.Add(-labelDelay)) {
+					log.Fatalf("--to must be at least --label-delay (%s) before --as-of: labels of later transactions are not final", labelDelay)
+				}
+			}
+			start := end.AddDate(0, 0, -90)
+			if from != "" {
+				start = mustParseTime("from", from)
+			}
+			if !start.Before(end) {
+				log.Fatal("--from must be before --to")
+			}
+
+			defs, err := features.LoadFile(file)
+			if err != nil {
+				log.Fatalf("Не удалось загрузить признаки: %v", err)
+			}
+			names := make([]string, 0, len(defs))
+			for _, d := range defs {
+				names = append(names, d.Name)
+			}
+
+			w := io.Writer(os.Stdout)
+			if out != "" && out != "-" {
+				f, err := os.Create(out)
+				if err != nil {
+					log.Fatalf("Не удалось создать файл: %v", err)
+				}
+				defer func() {
+					if err := f.Close(); err != nil {
+						log.Fatalf("Не удалось закрыть файл: %v", err)
+					}
+				}()
+				w = f
+			}
+
+			params := url.Values{
+				"param_from":  {start.Format(chDateTime)},
+				"param_to":    {end.Format(chDateTime)},
+				"param_as_of": {cutoff.Format(chDateTime)},
+			}
+			if err := queryHTTP(context.Background(), httpURL, trainingQuery(names, labeledOnly)+" FORMAT "+chFormat, params, w); err != nil {
+				log.Fatalf("Export failed: %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "Exported transactions created in [%s, %s) labeled as of %s\n",
+				start.Format(time.RFC3339), end.Format(time.RFC3339), cutoff.Format(time.RFC3339))
+		},
+	}
+	cmd.Flags().StringVar(&httpURL, "http", "http://localhost:8123", "ClickHouse HTTP interface URL (credentials as user:password@)")
+	cmd.Flags().StringVar(&file, "features", "configs/features.yaml", "Feature definition file; every declared feature becomes a column")
+	cmd.Flags().StringVar(&out, "out", "", "Output file (stdout by default)")
+	cmd.Flags().StringVar(&format, "format", "csv", "Output format: csv or parquet")
+	cmd.Flags().StringVar(&from, "from", "", "Start of the creation time range, RFC 3339 (90 days before --to by default)")
+	cmd.Flags().StringVar(&to, "to", "", "End of the creation time range, RFC 3339 (--as-of minus --label-delay by default)")
+	cmd.Flags().StringVar(&asOf, "as-of", "", "Use only labels known at this time, RFC 3339 (now by default)")
+	cmd.Flags().DurationVar(&labelDelay, "label-delay", 60*24*time.Hour, "How long labels take to become final, e.g. the chargeback window")
+	cmd.Flags().BoolVar(&labeledOnly, "labeled-only", false, "Export only transactions with a label")
+	return cmd
+}
+
+// trainingQuery joins the transactions with their feature snapshots and the labels known as of {as_of}.
+// When a transaction has several labels, the latest one known as of {as_of} wins; fraud_labels is append-only,
+// so a label relabeled after {as_of} still has the version that was known then. Feature names are snake_case,
+// so they are safe to use as column names; a feature the transaction had no entity for is NULL.
+func trainingQuery(featureNames []string, labeledOnly bool) string {
+	columns := make([]string, 0, len(featureNames))
+	for _, name := range featureNames {
+		columns = append(columns, fmt.Sprintf("if(mapContains(s.features, '%[1]s'), s.features['%[1]s'], NULL) AS %[1]s", name))
+	}
+	featureColumns := ""
+	if len(columns) > 0 {
+		featureColumns = ",\n\t\t       " + strings.Join(columns, ",\n\t\t       ")
+	}
+	join := "LEFT JOIN"
+	if labeledOnly {
+		join = "INNER JOIN"
+	}
+
+	return `
+		WITH labels AS (
+		    SELECT transaction_id,
+		           argMax(is_fraud, labeled_at)             AS is_fraud,
+		           max(labeled_at)                          AS labeled_at,
+		           arrayStringConcat(groupUniqArray(source), ',') AS sources
+		    FROM default.fraud_labels
+		    WHERE labeled_at <= {as_of:DateTime64(3, 'UTC')}
+		    GROUP BY transaction_id
+		)
+		SELECT t.transaction_id AS transaction_id,
+		       t.created_at     AS created_at,
+		       t.amount         AS amount,
+		       t.currency       AS currency,
+		       t.bin_country    AS bin_country,
+		       t.ip_country     AS ip_country,
+		       s.engine_version AS engine_version` + featureColumns + `,
+		       l.transaction_id IS NOT NULL AS labeled,
+		       ifNull(l.is_fraud, 0)        AS is_fraud,
+		       ifNull(l.sources, '')        AS label_sources
+		FROM default.transactions AS t FINAL
+		INNER JOIN (
+		    SELECT transaction_id, engine_version, features
+		    FROM default.fraud_feature_snapshots FINAL
+		    WHERE decided_at <= {as_of:DateTime64(3, 'UTC')}
+		) AS s ON s.transaction_id = t.transaction_id
+		` + join + ` labels AS l ON l.transaction_id = t.transaction_id
+		WHERE t.created_at >= {from:DateTime64(3, 'UTC')} AND t.created_at < {to:DateTime64(3, 'UTC')}
+		ORDER BY t.created_at, t.transaction_id
+		SETTINGS join_use_nulls = 1`
+}
+
+// queryHTTP runs a query through the ClickHouse HTTP interface and copies the formatted result to w.
+func queryHTTP(ctx context.Context, rawURL, query string, params url.Values, w io.Writer) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid ClickHouse URL: %w", err)
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(query))
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach ClickHouse: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("ClickHouse returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to write the result: %w", err)
+	}
+	return nil
+}
+
+func mustParseTime(flag, value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("Invalid --%s %q: expected RFC 3339, e.g. 2025-06-01T00:00:00Z", flag, value)
+	}
+	return t.UTC()
+}
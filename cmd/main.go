@@ -10,19 +10,28 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	goredis "github.com/redis/go-redis/v9"
 
 	"payment-processing-system/internal/adapters/auth/opa"
+	"payment-processing-system/internal/adapters/geoip"
+	grpcadapter "payment-processing-system/internal/adapters/grpc"
 	httphandler "payment-processing-system/internal/adapters/http"
 	"payment-processing-system/internal/adapters/messaging/kafka"
 	_ "payment-processing-system/internal/adapters/messaging/mock"
+	"payment-processing-system/internal/adapters/schemaregistry"
+	chstorage "payment-processing-system/internal/adapters/storage/clickhouse"
 	"payment-processing-system/internal/adapters/storage/postgres"
 	"payment-processing-system/internal/adapters/storage/redis"
+	"payment-processing-system/internal/antifraud"
 	"payment-processing-system/internal/app"
 	"payment-processing-system/internal/auth"
 	"payment-processing-system/internal/config"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/events"
 	"payment-processing-system/internal/observability"
 )
 
@@ -82,8 +91,54 @@ func main() {
 		}
 	}()
 
-	// Kafka
-	broker, err := kafka.NewBroker([]string{cfg.Kafka.BootstrapServers}, cfg.Kafka.Topic, logger)
+	spendingLimitRepo, err := redis.NewSpendingLimitAdapter(cfg.Redis.Addr)
+	if err != nil {
+		logger.Error("Failed to connect to Redis", "ERROR", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := spendingLimitRepo.Close(); err != nil {
+			logger.Warn("Failed to close Redis connection", "ERROR", err)
+		}
+	}()
+
+	// Redis client for the pre-authorization fraud rules.
+	fraudRedis := goredis.NewClient(&goredis.Options{Addr: cfg.Redis.Addr})
+	defer func() {
+		if err := fraudRedis.Close(); err != nil {
+			logger.Warn("Failed to close Redis connection", "ERROR", err)
+		}
+	}()
+
+	// ClickHouse: decided review cases are exported there as fraud labels.
+	chConn, err := clickhouse.Open(&clickhouse.Options{Addr: []string{cfg.ClickHouse.Addr}})
+	if err != nil {
+		logger.Error("Failed to connect to ClickHouse", "ERROR", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := chConn.Close(); err != nil {
+			logger.Warn("Failed to close ClickHouse connection", "ERROR", err)
+		}
+	}()
+
+	// Kafka: events are encoded in the configured format. A schema incompatible with
+	// the one registered for the topic stops the startup before anything is published.
+	var schemaRegistry *schemaregistry.Client
+	if cfg.Kafka.SchemaRegistryURL != "" {
+		schemaRegistry = schemaregistry.NewClient(cfg.Kafka.SchemaRegistryURL)
+	}
+	serializer, err := events.NewSerializer(events.Format(cfg.Kafka.Serialization))
+	if err != nil {
+		logger.Error("Failed to create event serializer", "ERROR", err)
+		os.Exit(1)
+	}
+	encoder, err := kafka.NewEventEncoder(ctx, serializer, schemaRegistry, schemaregistry.ValueSubject(cfg.Kafka.Topic))
+	if err != nil {
+		logger.Error("Failed to register event schema", "ERROR", err, "serialization", cfg.Kafka.Serialization)
+		os.Exit(1)
+	}
+	broker, err := kafka.NewBroker([]string{cfg.Kafka.BootstrapServers}, cfg.Kafka.Topic, encoder, logger)
 	if err != nil {
 		logger.Error("Failed to create Kafka broker", "ERROR", err)
 		os.Exit(1)
@@ -92,8 +147,81 @@ func main() {
 	logger.Info("Kafka broker created")
 
 	// --- 5. Service Layer ---
-	transactionService := app.NewTransactionService(repo, broker)
+	spendingLimits := make([]domain.SpendingLimit, 0, len(cfg.SpendingLimits))
+	for _, l := range cfg.SpendingLimits {
+		spendingLimits = append(spendingLimits, domain.SpendingLimit{
+			Scope:     domain.LimitScope(l.Scope),
+			Period:    domain.LimitPeriod(l.Period),
+			MaxAmount: l.MaxAmount,
+			MaxCount:  l.MaxCount,
+		})
+	}
+	serviceOpts := []app.Option{
+		app.WithSpendingLimits(spendingLimitRepo, spendingLimits),
+	}
+	if cfg.GeoIP.IPDatabase != "" || cfg.GeoIP.BINDatabase != "" {
+		geoDB, err := geoip.Load(cfg.GeoIP.IPDatabase, cfg.GeoIP.BINDatabase)
+		if err != nil {
+			logger.Error("Failed to load GeoIP databases", "ERROR", err)
+			os.Exit(1)
+		}
+		serviceOpts = append(serviceOpts, app.WithGeoLookup(geoDB))
+		logger.Info("GeoIP databases loaded", "ip_database", cfg.GeoIP.IPDatabase, "bin_database", cfg.GeoIP.BINDatabase)
+	}
+	if cfg.PreAuth.Enabled {
+		var fraudEngine domain.FraudRuleEngine
+		switch cfg.PreAuth.Engine {
+		case "grpc":
+			fraudClient, err := grpcadapter.NewFraudClient(cfg.PreAuth.GrpcAddr, time.Duration(cfg.PreAuth.TimeoutMs)*time.Millisecond, logger)
+			if err != nil {
+				logger.Error("Failed to create fraud analyzer client", "ERROR", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := fraudClient.Close(); err != nil {
+					logger.Warn("Failed to close fraud analyzer client", "ERROR", err)
+				}
+			}()
+			fraudEngine = fraudClient
+		default:
+			// The gateway keeps its own velocity counters so the analyzer does not count transactions twice.
+			preAuthCfg := cfg.AntiFraud
+			preAuthCfg.CounterKeyPrefix = "preauth_" + preAuthCfg.CounterKeyPrefix
+			fraudEngine, err = antifraud.NewEngineFromConfig(ctx, fraudRedis, preAuthCfg, logger)
+			if err != nil {
+				logger.Error("Failed to load fraud rules", "ERROR", err)
+				os.Exit(1)
+			}
+		}
+		logger.Info("Pre-authorization fraud check enabled", "engine", cfg.PreAuth.Engine, "fail_mode", cfg.PreAuth.FailMode)
+		serviceOpts = append(serviceOpts, app.WithFraudCheck(
+			fraudEngine,
+			time.Duration(cfg.PreAuth.TimeoutMs)*time.Millisecond,
+			cfg.PreAuth.FailMode == "open",
+		))
+	}
+	// Pre-authorization declines are never published, so their reports are written here for the fraud report endpoint.
+	reportStore := chstorage.NewReportStore(chConn)
+	serviceOpts = append(serviceOpts, app.WithFraudReports(reportStore, logger))
+	transactionService := app.NewTransactionService(repo, broker, serviceOpts...)
 	transactionHandler := httphandler.NewTransactionHandler(transactionService, logger)
+	reserveService := app.NewReserveService(repo)
+	reserveHandler := httphandler.NewReserveHandler(reserveService, logger)
+	labelSink := chstorage.NewLabelSink(chConn)
+	reviewService := app.NewReviewService(
+		repo,
+		labelSink,
+		time.Duration(cfg.Review.SLAMinutes)*time.Minute,
+		cfg.Review.ExpiryDecision == "approve",
+	)
+	reviewHandler := httphandler.NewReviewHandler(reviewService, logger)
+	labelsHandler := httphandler.NewLabelsHandler(app.NewLabelService(labelSink), logger)
+	fraudReportHandler := httphandler.NewFraudReportHandler(app.NewFraudReportService(reportStore), logger)
+	listService := app.NewListService(repo, redis.NewListCacheAdapter(fraudRedis, cfg.AntiFraud.Lists.KeyPrefix))
+	listsHandler := httphandler.NewListsHandler(listService, logger)
+	if err := listService.SyncCache(ctx); err != nil {
+		logger.Warn("Failed to warm up the fraud list cache", "ERROR", err)
+	}
 	// authHandler := httphandler.NewAuthHandler(logger, jwtSecret)
 	rateLimiterMiddleware := httphandler.NewRateLimiterMiddleware(rateLimiterRepo, logger)
 	opaMiddleware := opa.NewMiddleware(cfg.OPA.URL, logger)
@@ -142,6 +270,21 @@ func main() {
 			opaMiddleware.Authorize,
 		)
 		r.Post("/transaction", transactionHandler.HandleCreateTransaction)
+		r.Get("/transaction/{transactionID}/fraud", fraudReportHandler.HandleGetFraudReport)
+		r.Get("/merchants/{merchantID}/reserve", reserveHandler.HandleGetReserve)
+		r.Put("/merchants/{merchantID}/reserve/config", reserveHandler.HandlePutReserveConfig)
+		r.Get("/review/cases", reviewHandler.HandleListCases)
+		r.Get("/review/cases/{caseID}", reviewHandler.HandleGetCase)
+		r.Post("/review/cases/{caseID}/claim", reviewHandler.HandleClaimCase)
+		r.Post("/review/cases/{caseID}/approve", reviewHandler.HandleApproveCase)
+		r.Post("/review/cases/{caseID}/reject", reviewHandler.HandleRejectCase)
+		r.Post("/fraud/labels", labelsHandler.HandleRecordLabels)
+		r.Get("/lists/entries", listsHandler.HandleListEntries)
+		r.Post("/lists/entries", listsHandler.HandleCreateEntry)
+		r.Post("/lists/entries/import", listsHandler.HandleImportEntries)
+		r.Get("/lists/entries/{entryID}", listsHandler.HandleGetEntry)
+		r.Put("/lists/entries/{entryID}", listsHandler.HandleUpdateEntry)
+		r.Delete("/lists/entries/{entryID}", listsHandler.HandleDeleteEntry)
 	})
 
 	// Protected routes: /profile (example)
@@ -171,6 +314,56 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// Merchant reserve job: hold new volume and release aged-out entries.
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.Reserve.ProcessIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				if err := reserveService.ProcessReserves(jobCtx); err != nil {
+					logger.Error("failed to process merchant reserves", "error", err)
+				}
+			}
+		}
+	}()
+
+	// Review SLA job: auto-decide overdue cases and retry failed label exports.
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.Review.ProcessIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				if err := reviewService.ProcessReviewQueue(jobCtx); err != nil {
+					logger.Error("failed to process review queue", "error", err)
+				}
+			}
+		}
+	}()
+
+	// Fraud list cache job: drop expired entries and repair writes that did not reach Redis.
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.AntiFraud.Lists.SyncIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				if err := listService.SyncCache(jobCtx); err != nil {
+					logger.Error("failed to sync fraud list cache", "error", err)
+				}
+			}
+		}
+	}()
+
 	// Start server
 	go func() {
 		logger.Info("HTTP server starting", "addr", srv.Addr)
@@ -14,6 +14,9 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"payment-processing-system/internal/config"
 	"payment-processing-system/internal/observability"
@@ -77,6 +80,9 @@ func main() {
 		}},
 		// Добавляем проверку для других наших сервисов, если у них есть health-check
 		{Name: "Anti-Fraud Analyzer", Func: func(ctx context.Context) error { return checkHTTPHealth(ctx, "http://localhost:8082/healthz", logger) }},
+		{Name: "Anti-Fraud gRPC", Func: func(ctx context.Context) error {
+			return checkGRPCHealth(ctx, cfg.Server.PortAntiFraudGrpc)
+		}},
 		{Name: "Alerter Service", Func: func(ctx context.Context) error { return checkHTTPHealth(ctx, "http://localhost:8081/healthz", logger) }},
 	}
 	
@@ -152,6 +158,27 @@ func checkHTTPHealth(ctx context.Context, url string, logger *slog.Logger) error
 	return nil
 }
 
+// checkGRPCHealth calls the standard grpc.health.v1 service
+func checkGRPCHealth(ctx context.Context, addr string) error {
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("некорректный статус: %s", resp.GetStatus())
+	}
+	return nil
+}
+
 func checkPostgres(ctx context.Context, dsn string, logger *slog.Logger) error {
 	conn, err := pgx.Connect(ctx, dsn)
 	if err != nil {
@@ -0,0 +1,92 @@
+// Package alerting delivers alerts to the alerter-service webhook.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+)
+
+// Webhook posts merchant anomalies to the alerter-service /alert endpoint in the Alertmanager format.
+// It implements the AnomalyPublisher port.
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhook creates a webhook client for the given URL.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{client: &http.Client{Timeout: 5 * time.Second}, url: url}
+}
+
+type alert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+type alertRequest struct {
+	Alerts []alert `json:"alerts"`
+}
+
+// PublishAnomalies implements the AnomalyPublisher interface method. All anomalies are sent in one request.
+func (w *Webhook) PublishAnomalies(ctx context.Context, anomalies []domain.MerchantAnomaly) error {
+	body := alertRequest{Alerts: make([]alert, 0, len(anomalies))}
+	for _, a := range anomalies {
+		body.Alerts = append(body.Alerts, alert{
+			Status: "firing",
+			Labels: map[string]string{
+				"alertname":   "MerchantAnomaly",
+				"severity":    severity(a),
+				"merchant_id": a.MerchantID,
+				"metric":      string(a.Metric),
+				"anomaly_id":  a.ID,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("Merchant %s: %s is %.4g against a baseline of %.4g (z-score %.1f)",
+					a.MerchantID, a.Metric, a.Value, a.Baseline, a.ZScore),
+				"z_score":   strconv.FormatFloat(a.ZScore, 'f', 2, 64),
+				"threshold": strconv.FormatFloat(a.Threshold, 'f', 2, 64),
+			},
+			StartsAt: a.BucketStart,
+			EndsAt:   a.BucketEnd,
+		})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alerter webhook call failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("alerter webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// severity is critical when the deviation is at least twice the threshold.
+func severity(a domain.MerchantAnomaly) string {
+	if a.ZScore >= 2*a.Threshold {
+		return "critical"
+	}
+	return "warning"
+}
@@ -6,11 +6,9 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
-)
-
-type contextKey string
 
-const claimsContextKey = contextKey("claims")
+	"payment-processing-system/internal/auth"
+)
 
 // Middleware for authorization via OPA.
 type Middleware struct {
@@ -36,14 +34,17 @@ type Input struct {
 }
 
 // OPAResponse - structure for response from OPA.
+// The Data API wraps the document of the queried package in "result".
 type OPAResponse struct {
-	Allow bool `json:"allow"`
+	Result struct {
+		Allow bool `json:"allow"`
+	} `json:"result"`
 }
 
 // Authorize is an HTTP middleware that performs permissions checking.
 func (m *Middleware) Authorize(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := r.Context().Value(claimsContextKey).(map[string]interface{})
+		claims, ok := auth.ClaimsFromContext(r.Context())
 		if !ok {
 			http.Error(w, "Claims not found in context", http.StatusInternalServerError)
 			return
@@ -94,7 +95,7 @@ func (m *Middleware) Authorize(next http.Handler) http.Handler {
 		}
 
 		// Checking the OPA solution
-		if !opaResp.Allow {
+		if !opaResp.Result.Allow {
 			http.Error(w, "Forbidden", http.StatusForbidden)
 			return
 		}
@@ -0,0 +1,155 @@
+// Package geoip resolves countries of IP addresses and card BINs from local CSV databases.
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+
+	"payment-processing-system/internal/core/domain"
+)
+
+// Database is an in-memory implementation of the GeoLookup port. It is read-only once loaded.
+//
+// The IP database is a CSV with the columns "network" (CIDR) and "country", e.g. an export of
+// GeoLite2-Country joined with its locations file. The BIN database is a CSV with the columns
+// "bin" (6 to 8 digits) and "country". Other columns are ignored; the longest match wins.
+type Database struct {
+	networks map[netip.Prefix]string
+	// v4Bits and v6Bits are the prefix lengths present in networks, longest first.
+	v4Bits []int
+	v6Bits []int
+	bins   map[string]string
+}
+
+// Load reads the databases. An empty path leaves that lookup empty, so every value resolves to "".
+func Load(ipPath, binPath string) (*Database, error) {
+	db := &Database{
+		networks: make(map[netip.Prefix]string),
+		bins:     make(map[string]string),
+	}
+	if ipPath != "" {
+		if err := readCSV(ipPath, "network", db.addNetwork); err != nil {
+			return nil, fmt.Errorf("failed to load IP database: %w", err)
+		}
+	}
+	if binPath != "" {
+		if err := readCSV(binPath, "bin", db.addBIN); err != nil {
+			return nil, fmt.Errorf("failed to load BIN database: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// IPCountry implements the GeoLookup interface method.
+func (db *Database) IPCountry(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+	bits := db.v6Bits
+	if addr.Is4() {
+		bits = db.v4Bits
+	}
+	for _, n := range bits {
+		prefix, err := addr.Prefix(n)
+		if err != nil {
+			continue
+		}
+		if country, ok := db.networks[prefix]; ok {
+			return country
+		}
+	}
+	return ""
+}
+
+// BINCountry implements the GeoLookup interface method.
+func (db *Database) BINCountry(bin string) string {
+	for n := min(len(bin), 8); n >= 6; n-- {
+		if country, ok := db.bins[bin[:n]]; ok {
+			return country
+		}
+	}
+	return ""
+}
+
+func (db *Database) addNetwork(key, country string) error {
+	prefix, err := netip.ParsePrefix(key)
+	if err != nil {
+		return fmt.Errorf("invalid network %q", key)
+	}
+	prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()).Masked()
+	db.networks[prefix] = country
+
+	bits := &db.v6Bits
+	if prefix.Addr().Is4() {
+		bits = &db.v4Bits
+	}
+	if !slices.Contains(*bits, prefix.Bits()) {
+		*bits = append(*bits, prefix.Bits())
+		slices.SortFunc(*bits, func(a, b int) int { return b - a })
+	}
+	return nil
+}
+
+func (db *Database) addBIN(key, country string) error {
+	if len(key) < 6 || len(key) > 8 || strings.Trim(key, "0123456789") != "" {
+		return fmt.Errorf("invalid BIN %q", key)
+	}
+	db.bins[key] = country
+	return nil
+}
+
+// readCSV calls add for every row with the key column and the normalized country.
+func readCSV(path, keyColumn string, add func(key, country string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	keyIdx, countryIdx := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case keyColumn:
+			keyIdx = i
+		case "country":
+			countryIdx = i
+		}
+	}
+	if keyIdx < 0 || countryIdx < 0 {
+		return fmt.Errorf("CSV header must contain the %q and \"country\" columns", keyColumn)
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if keyIdx >= len(record) || countryIdx >= len(record) {
+			return fmt.Errorf("line %d: missing columns", line)
+		}
+		country, err := domain.NormalizeCountry(record[countryIdx])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := add(strings.TrimSpace(record[keyIdx]), country); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
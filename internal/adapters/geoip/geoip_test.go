@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "db.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDatabase_Lookup(t *testing.T) {
+	ipPath := writeFile(t, "network,geoname_id,country\n"+
+		"203.0.113.0/24,1,au\n"+
+		"203.0.113.128/25,2,NZ\n"+
+		"2001:db8::/32,3,DE\n")
+	binPath := writeFile(t, "bin,country\n411111,US\n41111112,CA\n")
+
+	db, err := Load(ipPath, binPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "AU", db.IPCountry("203.0.113.7"))
+	assert.Equal(t, "NZ", db.IPCountry("203.0.113.200"), "the longest prefix wins")
+	assert.Equal(t, "AU", db.IPCountry("::ffff:203.0.113.7"))
+	assert.Equal(t, "DE", db.IPCountry("2001:db8::1"))
+	assert.Equal(t, "", db.IPCountry("198.51.100.1"))
+	assert.Equal(t, "", db.IPCountry("not an ip"))
+
+	assert.Equal(t, "US", db.BINCountry("41111111"))
+	assert.Equal(t, "CA", db.BINCountry("41111112"))
+	assert.Equal(t, "", db.BINCountry("55555555"))
+	assert.Equal(t, "", db.BINCountry(""))
+}
+
+func TestLoad_RejectsInvalidRows(t *testing.T) {
+	_, err := Load(writeFile(t, "network,country\n10.0.0.0/33,US\n"), "")
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = Load("", writeFile(t, "bin,country\n4111,US\n"))
+	assert.ErrorContains(t, err, "invalid BIN")
+
+	_, err = Load("", writeFile(t, "prefix,country\n"))
+	assert.ErrorContains(t, err, "header")
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	transactionsv1 "payment-processing-system/gen/go/proto/v1"
+	"payment-processing-system/internal/core/domain"
+)
+
+// explanationToProto carries the explanation of a result; nil stays unset.
+func explanationToProto(e *domain.FraudExplanation) *transactionsv1.FraudExplanation {
+	if e == nil {
+		return nil
+	}
+	out := &transactionsv1.FraudExplanation{
+		Engine:           e.Engine,
+		Decision:         decisionToProto[e.Decision],
+		Score:            e.Score,
+		ReviewThreshold:  e.ReviewThreshold,
+		DeclineThreshold: e.DeclineThreshold,
+	}
+	for _, r := range e.Rules {
+		out.Rules = append(out.Rules, &transactionsv1.RuleEvaluation{
+			Name:        r.Name,
+			Description: r.Description,
+			Condition:   r.Condition,
+			Matched:     r.Matched,
+			Action:      r.Action,
+			Score:       r.Score,
+			Inputs:      r.Inputs,
+		})
+	}
+	if len(e.Inputs) > 0 {
+		out.Inputs = make(map[string]*structpb.Value, len(e.Inputs))
+		for name, v := range e.Inputs {
+			value, err := structpb.NewValue(v)
+			if err != nil {
+				// Engines read numbers, strings and flags; anything else is carried as its text.
+				value = structpb.NewStringValue(fmt.Sprint(v))
+			}
+			out.Inputs[name] = value
+		}
+	}
+	return out
+}
+
+// explanationFromProto is the inverse of explanationToProto. Numeric inputs come back as float64,
+// as they do from the stored JSON.
+func explanationFromProto(e *transactionsv1.FraudExplanation) *domain.FraudExplanation {
+	if e == nil {
+		return nil
+	}
+	out := &domain.FraudExplanation{
+		Engine:           e.GetEngine(),
+		Decision:         decisionFromWire(e.GetDecision()),
+		Score:            e.GetScore(),
+		ReviewThreshold:  e.GetReviewThreshold(),
+		DeclineThreshold: e.GetDeclineThreshold(),
+	}
+	for _, r := range e.GetRules() {
+		out.Rules = append(out.Rules, domain.RuleEvaluation{
+			Name:        r.GetName(),
+			Description: r.GetDescription(),
+			Condition:   r.GetCondition(),
+			Matched:     r.GetMatched(),
+			Action:      r.GetAction(),
+			Score:       r.GetScore(),
+			Inputs:      r.GetInputs(),
+		})
+	}
+	if len(e.GetInputs()) > 0 {
+		out.Inputs = make(map[string]any, len(e.GetInputs()))
+		for name, v := range e.GetInputs() {
+			out.Inputs[name] = v.AsInterface()
+		}
+	}
+	return out
+}
+
+func contributionsToProto(contributions []domain.EngineContribution) []*transactionsv1.EngineContribution {
+	var out []*transactionsv1.EngineContribution
+	for _, c := range contributions {
+		out = append(out, &transactionsv1.EngineContribution{
+			Engine:        c.Engine,
+			EngineVersion: c.EngineVersion,
+			Decision:      decisionToProto[c.Decision],
+			RiskScore:     c.RiskScore,
+			Weight:        c.Weight,
+			Error:         c.Error,
+			Skipped:       c.Skipped,
+			Explanation:   explanationToProto(c.Explanation),
+		})
+	}
+	return out
+}
+
+func contributionsFromProto(contributions []*transactionsv1.EngineContribution) []domain.EngineContribution {
+	var out []domain.EngineContribution
+	for _, c := range contributions {
+		out = append(out, domain.EngineContribution{
+			Engine:        c.GetEngine(),
+			EngineVersion: c.GetEngineVersion(),
+			Decision:      decisionFromWire(c.GetDecision()),
+			RiskScore:     c.GetRiskScore(),
+			Weight:        c.GetWeight(),
+			Error:         c.GetError(),
+			Skipped:       c.GetSkipped(),
+			Explanation:   explanationFromProto(c.GetExplanation()),
+		})
+	}
+	return out
+}
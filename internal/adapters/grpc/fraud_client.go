@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	transactionsv1 "payment-processing-system/gen/go/proto/v1"
+	"payment-processing-system/internal/core/domain"
+)
+
+// FraudClient implements domain.FraudRuleEngine by calling the anti-fraud analyzer over gRPC.
+type FraudClient struct {
+	conn    *grpc.ClientConn
+	client  transactionsv1.FraudAnalyzerServiceClient
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// NewFraudClient creates a client for the analyzer at addr.
+// The connection is established lazily on the first call.
+func NewFraudClient(addr string, timeout time.Duration, logger *slog.Logger) (*FraudClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return &FraudClient{
+		conn:    conn,
+		client:  transactionsv1.NewFraudAnalyzerServiceClient(conn),
+		timeout: timeout,
+		logger:  logger,
+	}, nil
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (c *FraudClient) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.AnalyzeTransaction(ctx, &transactionsv1.AnalyzeTransactionRequest{
+		TransactionId:   tx.ID.String(),
+		CardNumberHash:  tx.CardNumberHash,
+		Amount:          tx.Amount,
+		Currency:        tx.Currency,
+		Timestamp:       timestamppb.New(tx.CreatedAt),
+		MerchantId:      tx.MerchantID,
+		CustomerId:      tx.CustomerID,
+		Bin:             tx.BIN,
+		BinCountry:      tx.BINCountry,
+		EmailHash:       tx.EmailHash,
+		EmailDomain:     tx.EmailDomain,
+		PhoneHash:       tx.PhoneHash,
+		Ip:              tx.IP,
+		IpCountry:       tx.IPCountry,
+		DeviceId:        tx.DeviceID,
+		UserAgent:       tx.UserAgent,
+		BillingAddress:  addressToProto(tx.BillingAddress),
+		ShippingAddress: addressToProto(tx.ShippingAddress),
+	})
+	if err != nil {
+		c.logger.Error("fraud analyzer gRPC call failed", "transaction_id", tx.ID, "error", err)
+		return domain.FraudResult{}, fmt.Errorf("fraud analyzer call failed: %w", err)
+	}
+
+	return domain.FraudResult{
+		IsFraudulent:   resp.GetIsFraudulent(),
+		Reason:         resp.GetReason(),
+		RiskScore:      resp.GetRiskScore(),
+		TriggeredRules: resp.GetTriggeredRules(),
+		Decision:       decisionFromProto(resp.GetDecision(), resp.GetIsFraudulent()),
+		EngineVersion:  resp.GetEngineVersion(),
+		Contributions:  contributionsFromProto(resp.GetContributions()),
+		Explanation:    explanationFromProto(resp.GetExplanation()),
+	}, nil
+}
+
+// addressToProto leaves an address that was not given unset.
+func addressToProto(a domain.AddressFingerprint) *transactionsv1.Address {
+	if a == (domain.AddressFingerprint{}) {
+		return nil
+	}
+	return &transactionsv1.Address{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
+
+// decisionFromProto maps the wire decision; analyzers that do not set it are judged by is_fraudulent.
+func decisionFromProto(d transactionsv1.FraudDecision, isFraudulent bool) domain.FraudDecision {
+	if decision := decisionFromWire(d); decision != "" {
+		return decision
+	}
+	if isFraudulent {
+		return domain.DecisionDecline
+	}
+	return domain.DecisionAllow
+}
+
+// decisionFromWire maps the wire decision; an unspecified one is empty.
+func decisionFromWire(d transactionsv1.FraudDecision) domain.FraudDecision {
+	switch d {
+	case transactionsv1.FraudDecision_FRAUD_DECISION_ALLOW:
+		return domain.DecisionAllow
+	case transactionsv1.FraudDecision_FRAUD_DECISION_REVIEW:
+		return domain.DecisionReview
+	case transactionsv1.FraudDecision_FRAUD_DECISION_DECLINE:
+		return domain.DecisionDecline
+	}
+	return ""
+}
+
+// Close closes the underlying connection.
+func (c *FraudClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	transactionsv1 "payment-processing-system/gen/go/proto/v1"
+	"payment-processing-system/internal/core/domain"
+)
+
+// FraudServer exposes a FraudRuleEngine as the FraudAnalyzerService gRPC service.
+type FraudServer struct {
+	transactionsv1.UnimplementedFraudAnalyzerServiceServer
+
+	engine domain.FraudRuleEngine
+	logger *slog.Logger
+}
+
+// NewFraudServer creates a new gRPC handler around the given engine.
+func NewFraudServer(engine domain.FraudRuleEngine, logger *slog.Logger) *FraudServer {
+	return &FraudServer{
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// AnalyzeTransaction implements transactionsv1.FraudAnalyzerServiceServer.
+func (s *FraudServer) AnalyzeTransaction(ctx context.Context, req *transactionsv1.AnalyzeTransactionRequest) (*transactionsv1.AnalyzeTransactionResponse, error) {
+	id, err := uuid.Parse(req.GetTransactionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid transaction_id")
+	}
+	if req.GetCardNumberHash() == "" {
+		return nil, status.Error(codes.InvalidArgument, "card_number_hash is required")
+	}
+
+	tx := domain.Transaction{
+		ID:              id,
+		Amount:          req.GetAmount(),
+		Currency:        req.GetCurrency(),
+		CardNumberHash:  req.GetCardNumberHash(),
+		MerchantID:      req.GetMerchantId(),
+		CustomerID:      req.GetCustomerId(),
+		BIN:             req.GetBin(),
+		BINCountry:      req.GetBinCountry(),
+		EmailHash:       req.GetEmailHash(),
+		EmailDomain:     req.GetEmailDomain(),
+		PhoneHash:       req.GetPhoneHash(),
+		IP:              req.GetIp(),
+		IPCountry:       req.GetIpCountry(),
+		DeviceID:        req.GetDeviceId(),
+		UserAgent:       req.GetUserAgent(),
+		BillingAddress:  addressFromProto(req.GetBillingAddress()),
+		ShippingAddress: addressFromProto(req.GetShippingAddress()),
+		CreatedAt:       time.Now(),
+	}
+	if req.GetTimestamp() != nil {
+		tx.CreatedAt = req.GetTimestamp().AsTime()
+	}
+
+	result, err := s.engine.CheckTransaction(ctx, tx)
+	if err != nil {
+		s.logger.Error("fraud engine failed", "transaction_id", id, "error", err)
+		return nil, status.Error(codes.Unavailable, "fraud check unavailable")
+	}
+	s.logger.Debug("транзакция проверена по gRPC", "transaction_id", id, "decision", result.Decision)
+
+	return &transactionsv1.AnalyzeTransactionResponse{
+		TransactionId:  req.GetTransactionId(),
+		IsFraudulent:   result.IsFraudulent,
+		Reason:         result.Reason,
+		RiskScore:      result.RiskScore,
+		TriggeredRules: result.TriggeredRules,
+		Decision:       decisionToProto[result.Decision],
+		EngineVersion:  result.EngineVersion,
+		Contributions:  contributionsToProto(result.Contributions),
+		Explanation:    explanationToProto(result.Explanation),
+	}, nil
+}
+
+func addressFromProto(a *transactionsv1.Address) domain.AddressFingerprint {
+	return domain.AddressFingerprint{Country: a.GetCountry(), PostalCode: a.GetPostalCode(), Hash: a.GetHash()}
+}
+
+var decisionToProto = map[domain.FraudDecision]transactionsv1.FraudDecision{
+	domain.DecisionAllow:   transactionsv1.FraudDecision_FRAUD_DECISION_ALLOW,
+	domain.DecisionReview:  transactionsv1.FraudDecision_FRAUD_DECISION_REVIEW,
+	domain.DecisionDecline: transactionsv1.FraudDecision_FRAUD_DECISION_DECLINE,
+}
+
+// NewServer builds a gRPC server with the fraud service, standard health checking and reflection registered.
+func NewServer(fraud *FraudServer) *grpc.Server {
+	srv := grpc.NewServer()
+	transactionsv1.RegisterFraudAnalyzerServiceServer(srv, fraud)
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus(transactionsv1.FraudAnalyzerService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	reflection.Register(srv)
+	return srv
+}
@@ -0,0 +1,235 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	transactionsv1 "payment-processing-system/gen/go/proto/v1"
+	"payment-processing-system/internal/app"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+type stubEngine struct {
+	got    domain.Transaction
+	err    error
+	result *domain.FraudResult
+}
+
+func (e *stubEngine) CheckTransaction(_ context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	e.got = tx
+	if e.err != nil {
+		return domain.FraudResult{}, e.err
+	}
+	if e.result != nil {
+		return *e.result, nil
+	}
+	if tx.Amount > 1000 {
+		return domain.FraudResult{
+			IsFraudulent:   true,
+			Reason:         "Amount exceeds threshold",
+			RiskScore:      1,
+			TriggeredRules: []string{"amount_threshold"},
+			Decision:       domain.DecisionDecline,
+			EngineVersion:  "stub/v1",
+		}, nil
+	}
+	return domain.FraudResult{Decision: domain.DecisionAllow, EngineVersion: "stub/v1"}, nil
+}
+
+func newTestClient(conn *grpc.ClientConn) *FraudClient {
+	return &FraudClient{
+		client:  transactionsv1.NewFraudAnalyzerServiceClient(conn),
+		timeout: time.Second,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+// startServer runs the gRPC server over an in-memory listener and returns a client connection to it.
+func startServer(t *testing.T, engine domain.FraudRuleEngine) *grpc.ClientConn {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := NewServer(NewFraudServer(engine, logger))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestFraudClient_RoundTrip(t *testing.T) {
+	engine := &stubEngine{}
+	client := newTestClient(startServer(t, engine))
+
+	tx := domain.Transaction{
+		ID:             uuid.New(),
+		Amount:         1500,
+		Currency:       "USD",
+		CardNumberHash: "abc123",
+		MerchantID:     "merchant-1",
+		CustomerID:     "customer-1",
+		CreatedAt:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	result, err := client.CheckTransaction(context.Background(), tx)
+
+	require.NoError(t, err)
+	assert.True(t, result.IsFraudulent)
+	assert.Equal(t, "Amount exceeds threshold", result.Reason)
+	assert.Equal(t, domain.DecisionDecline, result.Decision)
+	assert.Equal(t, []string{"amount_threshold"}, result.TriggeredRules)
+	assert.Equal(t, 1.0, result.RiskScore)
+	assert.Equal(t, "stub/v1", result.EngineVersion)
+	assert.Equal(t, tx.ID, engine.got.ID)
+	assert.Equal(t, tx.CardNumberHash, engine.got.CardNumberHash)
+	assert.Equal(t, tx.MerchantID, engine.got.MerchantID)
+	assert.Equal(t, tx.CustomerID, engine.got.CustomerID)
+	assert.True(t, tx.CreatedAt.Equal(engine.got.CreatedAt))
+}
+
+func TestFraudClient_RoundTripsSignals(t *testing.T) {
+	engine := &stubEngine{}
+	client := newTestClient(startServer(t, engine))
+
+	tx := domain.Transaction{
+		ID:             uuid.New(),
+		Amount:         10,
+		Currency:       "EUR",
+		CardNumberHash: "abc123",
+		MerchantID:     "merchant-1",
+		CustomerID:     "customer-1",
+		BIN:            "411111",
+		BINCountry:     "DE",
+		EmailHash:      "e1f2a3b4",
+		EmailDomain:    "example.com",
+		PhoneHash:      "p5q6r7s8",
+		IP:             "203.0.113.10",
+		IPCountry:      "NL",
+		DeviceID:       "device-1",
+		UserAgent:      "Mozilla/5.0",
+		BillingAddress: domain.AddressFingerprint{Country: "DE", PostalCode: "10115", Hash: "addr-1"},
+		CreatedAt:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	_, err := client.CheckTransaction(context.Background(), tx)
+
+	require.NoError(t, err)
+	got := engine.got
+	assert.True(t, tx.CreatedAt.Equal(got.CreatedAt))
+	got.CreatedAt = tx.CreatedAt
+	assert.Equal(t, tx, got)
+}
+
+// Stubs - the gateway's storage, broker and report store
+type stubRepository struct{}
+
+func (stubRepository) Save(context.Context, domain.Transaction) error { return nil }
+
+type stubBroker struct{}
+
+func (stubBroker) PublishTransactionCreated(context.Context, domain.Transaction) error { return nil }
+
+type stubReports struct {
+	saved []domain.FraudReport
+}
+
+func (r *stubReports) SaveFraudReports(_ context.Context, reports []domain.FraudReport, _ string) error {
+	r.saved = append(r.saved, reports...)
+	return nil
+}
+
+func (r *stubReports) GetFraudReport(context.Context, uuid.UUID) (domain.FraudReport, error) {
+	return domain.FraudReport{}, domain.ErrFraudReportNotFound
+}
+
+func TestFraudClient_PreAuthDeclineReportKeepsExplanation(t *testing.T) {
+	explanation := &domain.FraudExplanation{
+		Engine:           "rules/v3",
+		Decision:         domain.DecisionDecline,
+		Score:            0.9,
+		ReviewThreshold:  0.5,
+		DeclineThreshold: 0.8,
+		Rules: []domain.RuleEvaluation{
+			{Name: "large_amount", Condition: "amount > 1000", Matched: true, Action: "score", Score: 0.6, Inputs: []string{"amount"}},
+			{Name: "night", Condition: "hour < 6", Inputs: []string{"hour"}},
+		},
+		Inputs: map[string]any{"amount": 1500.0, "hour": 14, "ip_country": "NL", "device_id": nil},
+	}
+	engine := &stubEngine{result: &domain.FraudResult{
+		IsFraudulent:   true,
+		Reason:         "large_amount",
+		RiskScore:      0.9,
+		TriggeredRules: []string{"rules:large_amount"},
+		Decision:       domain.DecisionDecline,
+		EngineVersion:  "composite/v1",
+		Contributions: []domain.EngineContribution{
+			{Engine: "rules", EngineVersion: "rules/v3", Decision: domain.DecisionDecline, RiskScore: 0.9, Weight: 1, Explanation: explanation},
+			{Engine: "model", Error: "deadline exceeded"},
+		},
+		Explanation: explanation,
+	}}
+	reports := &stubReports{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := app.NewTransactionService(stubRepository{}, stubBroker{},
+		app.WithFraudCheck(newTestClient(startServer(t, engine)), time.Second, false),
+		app.WithFraudReports(reports, logger),
+	)
+
+	_, err := service.CreateTransaction(context.Background(), ports.CreateTransactionInput{
+		Amount:         1500,
+		Currency:       "EUR",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	})
+
+	require.ErrorIs(t, err, domain.ErrTransactionDeclined)
+	require.Len(t, reports.saved, 1)
+	got := reports.saved[0].Result
+	// Numbers come back as float64, as they do from the stored JSON.
+	want := *explanation
+	want.Inputs = map[string]any{"amount": 1500.0, "hour": 14.0, "ip_country": "NL", "device_id": nil}
+	assert.Equal(t, want, got.Explain())
+	require.Len(t, got.Contributions, 2)
+	assert.Equal(t, want, *got.Contributions[0].Explanation)
+	assert.Equal(t, 1.0, got.Contributions[0].Weight)
+	assert.Equal(t, "deadline exceeded", got.Contributions[1].Error)
+	assert.Empty(t, got.Contributions[1].Decision)
+}
+
+func TestFraudClient_EngineErrorIsReturned(t *testing.T) {
+	client := newTestClient(startServer(t, &stubEngine{err: errors.New("redis down")}))
+
+	_, err := client.CheckTransaction(context.Background(), domain.Transaction{ID: uuid.New(), CardNumberHash: "abc123"})
+
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+}
+
+func TestFraudServer_HealthServing(t *testing.T) {
+	conn := startServer(t, &stubEngine{})
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -9,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"payment-processing-system/internal/auth"
 )
 
 // JWTMiddleware проверяет JWT и сохраняет claims в контекст
@@ -57,7 +57,7 @@ func JWTMiddleware(jwtSecret []byte, logger *slog.Logger) func(http.Handler) htt
 			}
 
 			// Сохраняем claims в типизированный контекст
-			ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+			ctx := auth.ContextWithClaims(r.Context(), claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
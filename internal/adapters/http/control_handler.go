@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds every dependency check of /readyz.
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck is a dependency that must be reachable for a service to be ready.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Consumption is the part of a message consumer that can be paused by an operator.
+type Consumption interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// ControlHandler serves the health, readiness and admin endpoints of a background service.
+type ControlHandler struct {
+	checks      []ReadinessCheck
+	consumption Consumption
+	logger      *slog.Logger
+}
+
+// NewControlHandler creates a new ControlHandler instance.
+func NewControlHandler(checks []ReadinessCheck, consumption Consumption, logger *slog.Logger) *ControlHandler {
+	return &ControlHandler{
+		checks:      checks,
+		consumption: consumption,
+		logger:      logger,
+	}
+}
+
+// HandleHealthz reports that the process is alive.
+func (h *ControlHandler) HandleHealthz(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Paused bool              `json:"paused"`
+	Checks map[string]string `json:"checks"`
+}
+
+// HandleReadyz checks every dependency and answers 503 if one of them is unreachable.
+func (h *ControlHandler) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	resp := readinessResponse{Status: "ready", Paused: h.consumption.Paused(), Checks: make(map[string]string, len(h.checks))}
+	status := http.StatusOK
+	for _, c := range h.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		err := c.Check(ctx)
+		cancel()
+		if err != nil {
+			h.logger.Warn("зависимость недоступна", "check", c.Name, "error", err)
+			resp.Checks[c.Name] = err.Error()
+			resp.Status = "not ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[c.Name] = "ok"
+	}
+	h.writeJSON(w, status, resp)
+}
+
+// HandlePause stops consuming; the messages in flight are still processed.
+func (h *ControlHandler) HandlePause(w http.ResponseWriter, _ *http.Request) {
+	h.consumption.Pause()
+	h.writeJSON(w, http.StatusOK, map[string]bool{"paused": true})
+}
+
+// HandleResume continues consuming after HandlePause.
+func (h *ControlHandler) HandleResume(w http.ResponseWriter, _ *http.Request) {
+	h.consumption.Resume()
+	h.writeJSON(w, http.StatusOK, map[string]bool{"paused": false})
+}
+
+func (h *ControlHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
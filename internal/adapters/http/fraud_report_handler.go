@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// FraudReportHandler serves the fraud decision of a transaction with its explanation,
+// so support staff can answer why a transaction was declined or sent to review.
+type FraudReportHandler struct {
+	service ports.FraudReportService
+	logger  *slog.Logger
+}
+
+// NewFraudReportHandler creates a new FraudReportHandler instance.
+func NewFraudReportHandler(service ports.FraudReportService, logger *slog.Logger) *FraudReportHandler {
+	return &FraudReportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type fraudReportResponse struct {
+	TransactionID  uuid.UUID                   `json:"transaction_id"`
+	ProcessedAt    time.Time                   `json:"processed_at"`
+	Decision       domain.FraudDecision        `json:"decision"`
+	RiskScore      float64                     `json:"risk_score"`
+	Reason         string                      `json:"reason,omitempty"`
+	TriggeredRules []string                    `json:"triggered_rules"`
+	EngineVersion  string                      `json:"engine_version,omitempty"`
+	Contributions  []domain.EngineContribution `json:"contributions,omitempty"`
+	Explanation    *domain.FraudExplanation    `json:"explanation"`
+}
+
+// HandleGetFraudReport returns the latest fraud decision of the transaction.
+func (h *FraudReportHandler) HandleGetFraudReport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "transactionID"))
+	if err != nil {
+		h.writeJSONError(w, "invalid transaction id", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetFraudReport(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	triggered := report.Result.TriggeredRules
+	if triggered == nil {
+		triggered = []string{}
+	}
+	h.writeJSON(w, http.StatusOK, fraudReportResponse{
+		TransactionID:  report.TransactionID,
+		ProcessedAt:    report.ProcessedAt,
+		Decision:       report.Result.Decision,
+		RiskScore:      report.Result.RiskScore,
+		Reason:         report.Result.Reason,
+		TriggeredRules: triggered,
+		EngineVersion:  report.Result.EngineVersion,
+		Contributions:  report.Result.Contributions,
+		Explanation:    report.Result.Explanation,
+	})
+}
+
+func (h *FraudReportHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrFraudReportNotFound):
+		h.writeJSONError(w, "the transaction has not been checked for fraud yet", http.StatusNotFound)
+
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		h.logger.Warn("temporary failure in external dependency", "error", err)
+		h.writeJSONError(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+
+	default:
+		h.logger.Error("unexpected error during fraud report request", "error", err)
+		h.writeJSONError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *FraudReportHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
+
+func (h *FraudReportHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"errors"
@@ -30,6 +31,87 @@ type createTransactionRequest struct {
 	CardNumber     string  `json:"card_number"`
 	Amount         float64 `json:"amount"`
 	Currency       string  `json:"currency"`
+	MerchantID     string  `json:"merchant_id,omitempty"`
+	CustomerID     string  `json:"customer_id,omitempty"`
+
+	// Optional buyer signals for the fraud checks.
+	Email             string          `json:"email,omitempty"`
+	Phone             string          `json:"phone,omitempty"`
+	IP                string          `json:"ip,omitempty"`
+	UserAgent         string          `json:"user_agent,omitempty"`
+	DeviceFingerprint string          `json:"device_fingerprint,omitempty"`
+	BillingAddress    *addressRequest `json:"billing_address,omitempty"`
+	ShippingAddress   *addressRequest `json:"shipping_address,omitempty"`
+}
+
+type addressRequest struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// addSignals validates and normalizes the buyer signals of the request into in.
+// Errors match domain.ErrInvalidSignal and name the offending field.
+func (req createTransactionRequest) addSignals(in *ports.CreateTransactionInput) error {
+	var err error
+	if req.Email != "" {
+		if in.Email, err = domain.NormalizeEmail(req.Email); err != nil {
+			return err
+		}
+	}
+	if req.Phone != "" {
+		if in.Phone, err = domain.NormalizePhone(req.Phone); err != nil {
+			return err
+		}
+	}
+	if req.IP != "" {
+		if in.IP, err = domain.NormalizeIP(req.IP); err != nil {
+			return err
+		}
+	}
+	if req.DeviceFingerprint != "" {
+		if in.DeviceID, err = domain.NormalizeDeviceID(req.DeviceFingerprint); err != nil {
+			return err
+		}
+	}
+	in.UserAgent = domain.NormalizeUserAgent(req.UserAgent)
+
+	if req.BillingAddress != nil {
+		if in.BillingAddress, err = req.BillingAddress.toDomain(); err != nil {
+			return fmt.Errorf("billing_address: %w", err)
+		}
+	}
+	if req.ShippingAddress != nil {
+		if in.ShippingAddress, err = req.ShippingAddress.toDomain(); err != nil {
+			return fmt.Errorf("shipping_address: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a addressRequest) toDomain() (domain.Address, error) {
+	return domain.Address{
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}.Normalize()
+}
+
+type limitExceededResponse struct {
+	Error string `json:"error"`
+	Limit struct {
+		Scope     string  `json:"scope"`
+		Period    string  `json:"period"`
+		Kind      string  `json:"kind"`
+		MaxAmount float64 `json:"max_amount,omitempty"`
+		MaxCount  int     `json:"max_count,omitempty"`
+	} `json:"limit"`
 }
 
 
@@ -47,13 +129,32 @@ func (h *TransactionHandler) HandleCreateTransaction(w http.ResponseWriter, r *h
 		return
 	}
 
-	tx, err := h.service.CreateTransaction(r.Context(), req.Amount, req.Currency, req.CardNumber, idemKey)
+	in := ports.CreateTransactionInput{
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		CardNumber:     req.CardNumber,
+		MerchantID:     req.MerchantID,
+		CustomerID:     req.CustomerID,
+		IdempotencyKey: idemKey,
+	}
+	if err := req.addSignals(&in); err != nil {
+		h.writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.service.CreateTransaction(r.Context(), in)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidAmount), 
 			errors.Is(err, domain.ErrInvalidCard):
 			h.writeJSONError(w, "invalid input data", http.StatusBadRequest)
 
+		case errors.Is(err, domain.ErrTransactionDeclined):
+			h.writeDeclined(w, err)
+
+		case errors.Is(err, domain.ErrLimitExceeded):
+			h.writeLimitExceeded(w, err)
+
 		case errors.Is(err, domain.ErrIdempotencyKeyUsed):
 			h.writeJSONError(w, "idempotency key already used", http.StatusConflict)
 
@@ -78,6 +179,42 @@ func (h *TransactionHandler) HandleCreateTransaction(w http.ResponseWriter, r *h
 	}
 }
 
+// writeDeclined tells the client that the attempt was recorded but declined, and why.
+func (h *TransactionHandler) writeDeclined(w http.ResponseWriter, err error) {
+	resp := map[string]string{"error": "transaction declined"}
+	var declinedErr *domain.DeclinedError
+	if errors.As(err, &declinedErr) {
+		resp["transaction_id"] = declinedErr.TransactionID.String()
+		resp["reason"] = declinedErr.Reason
+	}
+	h.logger.Info("transaction declined by pre-authorization fraud check", "error", err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPaymentRequired)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write JSON error response", "error", err)
+	}
+}
+
+// writeLimitExceeded tells the client which spending limit the transaction would have exceeded.
+func (h *TransactionHandler) writeLimitExceeded(w http.ResponseWriter, err error) {
+	resp := limitExceededResponse{Error: "spending limit exceeded"}
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		resp.Limit.Scope = string(limitErr.Limit.Scope)
+		resp.Limit.Period = string(limitErr.Limit.Period)
+		resp.Limit.Kind = string(limitErr.Kind)
+		resp.Limit.MaxAmount = limitErr.Limit.MaxAmount
+		resp.Limit.MaxCount = limitErr.Limit.MaxCount
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("Failed to write JSON error response", "error", err)
+	}
+}
+
 func (h *TransactionHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"payment-processing-system/internal/auth"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// maxLabelsBytes bounds the body of a label request.
+const maxLabelsBytes = 4 << 20
+
+// LabelsHandler serves the API recording confirmed fraud outcomes: chargebacks and merchant reports.
+type LabelsHandler struct {
+	service ports.LabelService
+	logger  *slog.Logger
+}
+
+// NewLabelsHandler creates a new LabelsHandler instance.
+func NewLabelsHandler(service ports.LabelService, logger *slog.Logger) *LabelsHandler {
+	return &LabelsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type fraudLabelRequest struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	IsFraud       bool      `json:"is_fraud"`
+	Source        string    `json:"source"`
+	Note          string    `json:"note"`
+	// LabeledAt is when the outcome became known, e.g. when the chargeback was received; now by default.
+	LabeledAt *time.Time `json:"labeled_at"`
+}
+
+type fraudLabelsRequest struct {
+	Labels []fraudLabelRequest `json:"labels"`
+}
+
+type fraudLabelsResponse struct {
+	Recorded int `json:"recorded"`
+}
+
+// HandleRecordLabels records a batch of labels. Nothing is recorded unless every label is valid.
+func (h *LabelsHandler) HandleRecordLabels(w http.ResponseWriter, r *http.Request) {
+	var req fraudLabelsRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLabelsBytes)).Decode(&req); err != nil {
+		h.writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims, _ := auth.ClaimsFromContext(r.Context())
+	caller := auth.Subject(claims)
+	labels := make([]domain.FraudLabel, 0, len(req.Labels))
+	for _, l := range req.Labels {
+		label := domain.FraudLabel{
+			TransactionID: l.TransactionID,
+			IsFraud:       l.IsFraud,
+			Source:        l.Source,
+			LabeledBy:     caller,
+			Note:          l.Note,
+		}
+		if l.LabeledAt != nil {
+			label.LabeledAt = *l.LabeledAt
+		}
+		labels = append(labels, label)
+	}
+
+	if err := h.service.RecordLabels(r.Context(), labels); err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, fraudLabelsResponse{Recorded: len(labels)})
+}
+
+func (h *LabelsHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidLabel):
+		h.writeJSONError(w, err.Error(), http.StatusBadRequest)
+
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		h.logger.Warn("temporary failure in external dependency", "error", err)
+		h.writeJSONError(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+
+	default:
+		h.logger.Error("unexpected error during label request", "error", err)
+		h.writeJSONError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *LabelsHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
+
+func (h *LabelsHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,303 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"payment-processing-system/internal/auth"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+	// maxListImportBytes bounds the CSV body of a bulk import.
+	maxListImportBytes = 4 << 20
+)
+
+// ListsHandler serves the blocklist and allowlist management API.
+type ListsHandler struct {
+	service ports.ListService
+	logger  *slog.Logger
+}
+
+// NewListsHandler creates a new ListsHandler instance.
+func NewListsHandler(service ports.ListService, logger *slog.Logger) *ListsHandler {
+	return &ListsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type listEntryRequest struct {
+	List      string     `json:"list"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type listEntryUpdateRequest struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type listEntryResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	List      string     `json:"list"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type listImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// HandleListEntries returns list entries, newest first, optionally filtered by list and kind.
+func (h *ListsHandler) HandleListEntries(w http.ResponseWriter, r *http.Request) {
+	list := domain.ListType(r.URL.Query().Get("list"))
+	if list != "" && !list.Valid() {
+		h.writeJSONError(w, "invalid list", http.StatusBadRequest)
+		return
+	}
+	kind := domain.ListKind(r.URL.Query().Get("kind"))
+	if kind != "" && !kind.Valid() {
+		h.writeJSONError(w, "invalid kind", http.StatusBadRequest)
+		return
+	}
+	limit := defaultListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxListLimit {
+			h.writeJSONError(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	entries, err := h.service.ListEntries(r.Context(), list, kind, limit)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	resp := make([]listEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, toListEntryResponse(e))
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleCreateEntry adds a value to a list. Adding an existing value updates its reason and expiry.
+func (h *ListsHandler) HandleCreateEntry(w http.ResponseWriter, r *http.Request) {
+	var req listEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.service.AddEntry(r.Context(), domain.ListEntry{
+		List:      domain.ListType(req.List),
+		Kind:      domain.ListKind(req.Kind),
+		Value:     req.Value,
+		Reason:    req.Reason,
+		CreatedBy: h.caller(r),
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, toListEntryResponse(*e))
+}
+
+// HandleImportEntries adds every row of a CSV body. The header names the columns:
+// list, kind and value are required, reason and expires_at (RFC 3339) are optional.
+// Nothing is imported unless every row is valid.
+func (h *ListsHandler) HandleImportEntries(w http.ResponseWriter, r *http.Request) {
+	entries, err := parseListCSV(http.MaxBytesReader(w, r.Body, maxListImportBytes), h.caller(r))
+	if err != nil {
+		h.writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.service.ImportEntries(r.Context(), entries)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, listImportResponse{Imported: len(saved)})
+}
+
+// HandleGetEntry returns one list entry.
+func (h *ListsHandler) HandleGetEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.entryID(w, r)
+	if !ok {
+		return
+	}
+	e, err := h.service.GetEntry(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toListEntryResponse(*e))
+}
+
+// HandleUpdateEntry replaces the reason and the expiry of a list entry.
+func (h *ListsHandler) HandleUpdateEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.entryID(w, r)
+	if !ok {
+		return
+	}
+	var req listEntryUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.service.UpdateEntry(r.Context(), id, req.Reason, req.ExpiresAt)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toListEntryResponse(*e))
+}
+
+// HandleDeleteEntry removes a value from its list.
+func (h *ListsHandler) HandleDeleteEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.entryID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteEntry(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseListCSV reads list entries from CSV with a header row.
+func parseListCSV(r io.Reader, createdBy string) ([]domain.ListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"list", "kind", "value"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain the %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []domain.ListEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		e := domain.ListEntry{
+			List:      domain.ListType(field(record, "list")),
+			Kind:      domain.ListKind(field(record, "kind")),
+			Value:     field(record, "value"),
+			Reason:    field(record, "reason"),
+			CreatedBy: createdBy,
+		}
+		if raw := field(record, "expires_at"); raw != "" {
+			expiresAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: expires_at must be an RFC 3339 time", line)
+			}
+			e.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (h *ListsHandler) entryID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "entryID"))
+	if err != nil {
+		h.writeJSONError(w, "invalid entry id", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// caller identifies who changes the lists by the subject of the access token.
+func (h *ListsHandler) caller(r *http.Request) string {
+	claims, _ := auth.ClaimsFromContext(r.Context())
+	return auth.Subject(claims)
+}
+
+func (h *ListsHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidListEntry):
+		h.writeJSONError(w, err.Error(), http.StatusBadRequest)
+
+	case errors.Is(err, domain.ErrListEntryNotFound):
+		h.writeJSONError(w, "list entry not found", http.StatusNotFound)
+
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		h.logger.Warn("temporary failure in external dependency", "error", err)
+		h.writeJSONError(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+
+	default:
+		h.logger.Error("unexpected error during list request", "error", err)
+		h.writeJSONError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *ListsHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
+
+func (h *ListsHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
+
+func toListEntryResponse(e domain.ListEntry) listEntryResponse {
+	return listEntryResponse{
+		ID:        e.ID,
+		List:      string(e.List),
+		Kind:      string(e.Kind),
+		Value:     e.Value,
+		Reason:    e.Reason,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		ExpiresAt: e.ExpiresAt,
+	}
+}
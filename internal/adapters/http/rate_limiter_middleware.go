@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"payment-processing-system/internal/core/domain"
 	"payment-processing-system/internal/core/ports"
 )
 
@@ -40,7 +41,7 @@ func (m *RateLimiterMiddleware) Handler(next http.Handler) http.Handler {
 		window := 1 * time.Minute // в минуту
 
 		// Check if the request is allowed.
-		allowed, err := m.repo.IsAllowed(r.Context(), ip, limit, window)
+		allowed, err := m.repo.IsAllowed(r.Context(), domain.VelocityKey(domain.VelocityIP, ip), limit, window)
 		if err != nil {
 			m.logger.Error("ошибка при проверке rate limit в Redis", "ERROR", err)
 			// "Fail-open": If our rate limiter (Redis) is not working, we should not
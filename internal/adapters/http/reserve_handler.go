@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// ReserveHandler serves the merchant rolling reserve API.
+type ReserveHandler struct {
+	service ports.ReserveService
+	logger  *slog.Logger
+}
+
+// NewReserveHandler creates a new ReserveHandler instance.
+func NewReserveHandler(service ports.ReserveService, logger *slog.Logger) *ReserveHandler {
+	return &ReserveHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type reserveConfigRequest struct {
+	RollingPercent float64 `json:"rolling_percent"`
+	HoldDays       int     `json:"hold_days"`
+	MinimumReserve float64 `json:"minimum_reserve"`
+	Currency       string  `json:"currency"`
+}
+
+type reserveConfigResponse struct {
+	MerchantID     string    `json:"merchant_id"`
+	RollingPercent float64   `json:"rolling_percent"`
+	HoldDays       int       `json:"hold_days"`
+	MinimumReserve float64   `json:"minimum_reserve"`
+	Currency       string    `json:"currency"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type reserveReleaseResponse struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
+}
+
+type reserveBalanceResponse struct {
+	MerchantID     string                   `json:"merchant_id"`
+	Currency       string                   `json:"currency"`
+	Held           float64                  `json:"held"`
+	MinimumReserve float64                  `json:"minimum_reserve"`
+	Schedule       []reserveReleaseResponse `json:"release_schedule"`
+}
+
+// HandlePutReserveConfig creates or replaces the reserve configuration of a merchant.
+func (h *ReserveHandler) HandlePutReserveConfig(w http.ResponseWriter, r *http.Request) {
+	var req reserveConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cfg, err := h.service.ConfigureReserve(r.Context(), domain.ReserveConfig{
+		MerchantID:     chi.URLParam(r, "merchantID"),
+		RollingPercent: req.RollingPercent,
+		HoldDays:       req.HoldDays,
+		MinimumReserve: req.MinimumReserve,
+		Currency:       req.Currency,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, reserveConfigResponse{
+		MerchantID:     cfg.MerchantID,
+		RollingPercent: cfg.RollingPercent,
+		HoldDays:       cfg.HoldDays,
+		MinimumReserve: cfg.MinimumReserve,
+		Currency:       cfg.Currency,
+		EffectiveFrom:  cfg.EffectiveFrom,
+		UpdatedAt:      cfg.UpdatedAt,
+	})
+}
+
+// HandleGetReserve returns the held balance and the release schedule of a merchant.
+func (h *ReserveHandler) HandleGetReserve(w http.ResponseWriter, r *http.Request) {
+	balance, err := h.service.GetReserveBalance(r.Context(), chi.URLParam(r, "merchantID"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := reserveBalanceResponse{
+		MerchantID:     balance.MerchantID,
+		Currency:       balance.Currency,
+		Held:           balance.Held,
+		MinimumReserve: balance.MinimumReserve,
+		Schedule:       make([]reserveReleaseResponse, 0, len(balance.Schedule)),
+	}
+	for _, rel := range balance.Schedule {
+		resp.Schedule = append(resp.Schedule, reserveReleaseResponse{
+			Date:   rel.Date.Format(time.DateOnly),
+			Amount: rel.Amount,
+		})
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ReserveHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidReserveConfig):
+		h.writeJSONError(w, "invalid reserve configuration", http.StatusBadRequest)
+
+	case errors.Is(err, domain.ErrReserveConfigNotFound):
+		h.writeJSONError(w, "reserve is not configured for merchant", http.StatusNotFound)
+
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		h.logger.Warn("temporary failure in external dependency", "error", err)
+		h.writeJSONError(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+
+	default:
+		h.logger.Error("unexpected error during reserve request", "error", err)
+		h.writeJSONError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *ReserveHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
+
+func (h *ReserveHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"payment-processing-system/internal/auth"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+const (
+	defaultReviewListLimit = 50
+	maxReviewListLimit     = 500
+)
+
+// ReviewHandler serves the manual review queue API for fraud analysts.
+type ReviewHandler struct {
+	service ports.ReviewService
+	logger  *slog.Logger
+}
+
+// NewReviewHandler creates a new ReviewHandler instance.
+func NewReviewHandler(service ports.ReviewService, logger *slog.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type reviewDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+type reviewCaseResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	TransactionID  uuid.UUID  `json:"transaction_id"`
+	Status         string     `json:"status"`
+	RiskScore      float64    `json:"risk_score"`
+	Reason         string     `json:"reason,omitempty"`
+	TriggeredRules []string   `json:"triggered_rules"`
+	AssignedTo     string     `json:"assigned_to,omitempty"`
+	DecidedBy      string     `json:"decided_by,omitempty"`
+	AutoDecided    bool       `json:"auto_decided"`
+	Note           string     `json:"note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DueAt          time.Time  `json:"due_at"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
+
+// HandleListCases returns the cases in the requested status, the undecided ones by default.
+func (h *ReviewHandler) HandleListCases(w http.ResponseWriter, r *http.Request) {
+	status := domain.ReviewStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.ReviewOpen, domain.ReviewClaimed, domain.ReviewApproved, domain.ReviewRejected:
+	default:
+		h.writeJSONError(w, "invalid status", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultReviewListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxReviewListLimit {
+			h.writeJSONError(w, "limit must be between 1 and 500", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	cases, err := h.service.ListCases(r.Context(), status, limit)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	resp := make([]reviewCaseResponse, 0, len(cases))
+	for _, c := range cases {
+		resp = append(resp, toReviewCaseResponse(c))
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleGetCase returns one case.
+func (h *ReviewHandler) HandleGetCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.caseID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.service.GetCase(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toReviewCaseResponse(*c))
+}
+
+// HandleClaimCase assigns the case to the calling analyst.
+func (h *ReviewHandler) HandleClaimCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.caseID(w, r)
+	if !ok {
+		return
+	}
+	analyst, ok := h.analyst(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.service.ClaimCase(r.Context(), id, analyst)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toReviewCaseResponse(*c))
+}
+
+// HandleApproveCase completes the transaction of the case.
+func (h *ReviewHandler) HandleApproveCase(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, true)
+}
+
+// HandleRejectCase declines the transaction of the case as fraud.
+func (h *ReviewHandler) HandleRejectCase(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, false)
+}
+
+func (h *ReviewHandler) decide(w http.ResponseWriter, r *http.Request, approve bool) {
+	id, ok := h.caseID(w, r)
+	if !ok {
+		return
+	}
+	analyst, ok := h.analyst(w, r)
+	if !ok {
+		return
+	}
+
+	// The note is optional, so is the body.
+	var req reviewDecisionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeJSONError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	c, err := h.service.DecideCase(r.Context(), id, analyst, approve, req.Note)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toReviewCaseResponse(*c))
+}
+
+func (h *ReviewHandler) caseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "caseID"))
+	if err != nil {
+		h.writeJSONError(w, "invalid case id", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// analyst identifies the caller by the subject of the access token.
+func (h *ReviewHandler) analyst(w http.ResponseWriter, r *http.Request) (string, bool) {
+	claims, _ := auth.ClaimsFromContext(r.Context())
+	sub := auth.Subject(claims)
+	if sub == "" {
+		h.writeJSONError(w, "token has no subject", http.StatusForbidden)
+		return "", false
+	}
+	return sub, true
+}
+
+func (h *ReviewHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrReviewCaseNotFound):
+		h.writeJSONError(w, "review case not found", http.StatusNotFound)
+
+	case errors.Is(err, domain.ErrReviewCaseClosed):
+		h.writeJSONError(w, "review case is already decided", http.StatusConflict)
+
+	case errors.Is(err, domain.ErrReviewCaseClaimed):
+		h.writeJSONError(w, "review case is claimed by another analyst", http.StatusConflict)
+
+	case errors.Is(err, domain.ErrReviewCaseConflict):
+		h.writeJSONError(w, "review case was changed concurrently, reload it and retry", http.StatusConflict)
+
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		h.logger.Warn("temporary failure in external dependency", "error", err)
+		h.writeJSONError(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+
+	default:
+		h.logger.Error("unexpected error during review request", "error", err)
+		h.writeJSONError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *ReviewHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to write json response", "ERROR", err)
+	}
+}
+
+func (h *ReviewHandler) writeJSONError(w http.ResponseWriter, message string, status int) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
+
+func toReviewCaseResponse(c domain.ReviewCase) reviewCaseResponse {
+	rules := c.TriggeredRules
+	if rules == nil {
+		rules = []string{}
+	}
+	return reviewCaseResponse{
+		ID:             c.ID,
+		TransactionID:  c.TransactionID,
+		Status:         string(c.Status),
+		RiskScore:      c.RiskScore,
+		Reason:         c.Reason,
+		TriggeredRules: rules,
+		AssignedTo:     c.AssignedTo,
+		DecidedBy:      c.DecidedBy,
+		AutoDecided:    c.AutoDecided,
+		Note:           c.Note,
+		CreatedAt:      c.CreatedAt,
+		DueAt:          c.DueAt,
+		ClaimedAt:      c.ClaimedAt,
+		DecidedAt:      c.DecidedAt,
+	}
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"payment-processing-system/internal/core/domain"
+)
+
+// AnomalyPublisher is an implementation of the AnomalyPublisher port for Kafka.
+// Anomalies are keyed by merchant, so the events of a merchant stay in order.
+type AnomalyPublisher struct {
+	client *kgo.Client
+	topic  string
+}
+
+// NewAnomalyPublisher creates a publisher over an existing client.
+func NewAnomalyPublisher(client *kgo.Client, topic string) *AnomalyPublisher {
+	return &AnomalyPublisher{client: client, topic: topic}
+}
+
+// anomalyMessage is the event published for every anomaly.
+type anomalyMessage struct {
+	ID          string  `json:"id"`
+	MerchantID  string  `json:"merchant_id"`
+	Metric      string  `json:"metric"`
+	BucketStart string  `json:"bucket_start"`
+	BucketEnd   string  `json:"bucket_end"`
+	Value       float64 `json:"value"`
+	Baseline    float64 `json:"baseline"`
+	StdDev      float64 `json:"std_dev"`
+	ZScore      float64 `json:"z_score"`
+	Threshold   float64 `json:"threshold"`
+	DetectedAt  string  `json:"detected_at"`
+}
+
+// PublishAnomalies implements the AnomalyPublisher interface method. It waits until every event is delivered.
+func (p *AnomalyPublisher) PublishAnomalies(ctx context.Context, anomalies []domain.MerchantAnomaly) error {
+	records := make([]*kgo.Record, 0, len(anomalies))
+	for _, a := range anomalies {
+		payload, err := json.Marshal(anomalyMessage{
+			ID:          a.ID,
+			MerchantID:  a.MerchantID,
+			Metric:      string(a.Metric),
+			BucketStart: a.BucketStart.UTC().Format("2006-01-02T15:04:05Z07:00"),
+			BucketEnd:   a.BucketEnd.UTC().Format("2006-01-02T15:04:05Z07:00"),
+			Value:       a.Value,
+			Baseline:    a.Baseline,
+			StdDev:      a.StdDev,
+			ZScore:      a.ZScore,
+			Threshold:   a.Threshold,
+			DetectedAt:  a.DetectedAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal anomaly: %w", err)
+		}
+		records = append(records, &kgo.Record{Topic: p.topic, Key: []byte(a.MerchantID), Value: payload})
+	}
+	if err := p.client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("failed to publish anomalies to kafka: %w", err)
+	}
+	return nil
+}
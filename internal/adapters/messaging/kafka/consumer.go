@@ -0,0 +1,645 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Handler processes one record. Records with the same ordering key are handled one at a time, in offset order.
+// The handler calls done once the record needs no more work, possibly after it returns, e.g. when its result
+// is written in a batch; the offset of the record is not committed before. Calling done with an error hands the
+// record to the handler again after RetryBackoff, possibly after later records of its key; the offsets after it
+// in the partition are not committed meanwhile. A record still failing at shutdown is read again on the next run.
+type Handler func(ctx context.Context, record *kgo.Record, done func(error))
+
+// ConsumerOptions configures a Consumer.
+type ConsumerOptions struct {
+	Brokers []string
+	Group   string
+	Topics  []string
+	// Workers is how many records are handled concurrently.
+	Workers int
+	// MaxInFlight bounds the polled records that are not handled yet; the consumer stops polling at the bound.
+	MaxInFlight int
+	// MaxPartitionInFlight pauses fetching from a partition with that many records in flight,
+	// so a slow partition does not take all of MaxInFlight. Fetching resumes at half of it.
+	MaxPartitionInFlight int
+	// CommitInterval is how often the handled offsets are committed.
+	CommitInterval time.Duration
+	// Delay holds every record until Delay after its timestamp, e.g. on a retry topic. A worker waiting for a record
+	// holds its place in MaxInFlight, so a topic whose records are not due yet is paused like a slow one.
+	Delay time.Duration
+	// RetryBackoff is how long a record whose handler failed waits before it is handled again.
+	RetryBackoff time.Duration
+}
+
+// Consumer reads a consumer group with a pool of workers. A record goes to the worker chosen by its ordering key,
+// so the records of a key are handled in order while different keys, within a partition or not, are handled in parallel.
+//
+// An offset is committed once the record and every record before it in the partition are handled:
+// after a crash or a rebalance records may be handled again, but none is skipped.
+type Consumer struct {
+	client  *kgo.Client
+	opts    ConsumerOptions
+	handler Handler
+	logger  *slog.Logger
+	workers []chan task
+
+	mu         sync.Mutex
+	drained    *sync.Cond
+	inFlight   int
+	assigned   map[topicPartition]bool
+	partitions map[topicPartition]*partitionState
+	// freed wakes up the poll loop waiting for room under MaxInFlight.
+	freed chan struct{}
+	// paused is set by Pause: the topics are not fetched until Resume.
+	paused atomic.Bool
+	// retries are the failed records waiting for RetryBackoff, in the order they failed; retried wakes up
+	// the goroutine handing them back to the workers. Once stopping is set, failed records are given up.
+	retries  []task
+	retried  chan struct{}
+	stopping bool
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+type task struct {
+	record    *kgo.Record
+	offset    *pendingOffset
+	partition *partitionState
+	// retryAt is when a failed record is handed to the handler again.
+	retryAt time.Time
+}
+
+type pendingOffset struct {
+	offset int64
+	epoch  int32
+	done   bool
+	// started is set while the record is with the handler, abandoned once it is given up.
+	started   bool
+	abandoned bool
+}
+
+// partitionState tracks the records of a partition from the poll until their offset can be committed.
+type partitionState struct {
+	// pending are the dispatched records whose offsets are not committable yet, in offset order.
+	pending []*pendingOffset
+	commit  kgo.EpochOffset
+	dirty   bool
+	paused  bool
+	// inFlight counts the dispatched records that are neither done nor abandoned.
+	inFlight int
+	// highWatermark is the offset after the last record of the partition at the last fetch.
+	highWatermark int64
+	// released is closed when the partition is revoked or lost, waking up its delayed records.
+	released chan struct{}
+}
+
+// NewConsumer creates the consumer group client. Offsets are committed by the consumer only.
+func NewConsumer(opts ConsumerOptions, handler Handler, logger *slog.Logger) (*Consumer, error) {
+	if opts.Workers < 1 || opts.MaxInFlight < opts.Workers {
+		return nil, fmt.Errorf("kafka consumer needs at least one worker and at least a record in flight per worker")
+	}
+	if opts.MaxPartitionInFlight < 1 || opts.MaxPartitionInFlight > opts.MaxInFlight {
+		opts.MaxPartitionInFlight = opts.MaxInFlight
+	}
+	if opts.CommitInterval <= 0 {
+		opts.CommitInterval = time.Second
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+
+	c := &Consumer{
+		opts:       opts,
+		handler:    handler,
+		logger:     logger,
+		workers:    make([]chan task, opts.Workers),
+		assigned:   make(map[topicPartition]bool),
+		partitions: make(map[topicPartition]*partitionState),
+		freed:      make(chan struct{}, 1),
+		retried:    make(chan struct{}, 1),
+	}
+	c.drained = sync.NewCond(&c.mu)
+	for i := range c.workers {
+		// Never blocks the poll loop: there are at most MaxInFlight records in flight, and the records
+		// of released partitions still queued are dropped as soon as a worker reaches them.
+		c.workers[i] = make(chan task, 2*opts.MaxInFlight)
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(opts.Brokers...),
+		kgo.ConsumerGroup(opts.Group),
+		kgo.ConsumeTopics(opts.Topics...),
+		kgo.DisableAutoCommit(),
+		kgo.OnPartitionsAssigned(c.onAssigned),
+		kgo.OnPartitionsRevoked(c.onRevoked),
+		kgo.OnPartitionsLost(c.onLost),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return c, nil
+}
+
+// Client returns the underlying client.
+func (c *Consumer) Client() *kgo.Client {
+	return c.client
+}
+
+// Run polls and handles records until ctx is cancelled. Records already polled are still handled,
+// and committed once done, before Run returns; the handler gets a context that is not cancelled with ctx.
+// Records still delayed when ctx is cancelled are left to the next run.
+func (c *Consumer) Run(ctx context.Context) {
+	handleCtx := context.WithoutCancel(ctx)
+	workers := c.startWorkers(ctx)
+	retryCtx, stopRetries := context.WithCancel(ctx)
+	retrier := c.startRetries(retryCtx)
+
+	committed := make(chan struct{})
+	go func() {
+		defer close(committed)
+		ticker := time.NewTicker(c.opts.CommitInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.commit(handleCtx, nil)
+			}
+		}
+	}()
+
+	for {
+		room, ok := c.waitForRoom(ctx)
+		if !ok {
+			break
+		}
+		fetches := c.client.PollRecords(ctx, room)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			break
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			c.logger.Error("ошибка при чтении из kafka", "topic", t, "partition", p, "error", err)
+		})
+		c.dispatch(fetches)
+	}
+
+	stopRetries()
+	<-retrier
+	c.stopWorkers(workers)
+	c.waitDone()
+	<-committed
+	c.commit(handleCtx, nil)
+}
+
+// startWorkers starts a goroutine per worker queue. Delayed records are abandoned once ctx is cancelled.
+func (c *Consumer) startWorkers(ctx context.Context) *sync.WaitGroup {
+	handleCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for _, queue := range c.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				if !c.waitDue(ctx, t) || !c.start(t) {
+					c.abandon(t)
+					continue
+				}
+				var once sync.Once
+				c.handler(handleCtx, t.record, func(err error) {
+					once.Do(func() {
+						if err != nil {
+							c.retry(t)
+							return
+						}
+						c.complete(t)
+					})
+				})
+			}
+		}()
+	}
+	return &wg
+}
+
+// startRetries starts the goroutine that hands failed records back to their workers once RetryBackoff has passed.
+// When ctx is cancelled it gives up the records still waiting, and the ones failing later; the returned channel
+// is closed then.
+func (c *Consumer) startRetries(ctx context.Context) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		timer := time.NewTimer(c.opts.RetryBackoff)
+		defer timer.Stop()
+		for {
+			c.mu.Lock()
+			var due []task
+			now := time.Now()
+			for len(c.retries) > 0 && !c.retries[0].retryAt.After(now) {
+				due = append(due, c.retries[0])
+				c.retries = c.retries[1:]
+			}
+			wait := c.opts.RetryBackoff
+			if len(c.retries) > 0 {
+				wait = time.Until(c.retries[0].retryAt)
+			}
+			c.mu.Unlock()
+
+			for _, t := range due {
+				c.workers[c.worker(t.record)] <- t
+			}
+
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				c.mu.Lock()
+				c.stopping = true
+				left := c.retries
+				c.retries = nil
+				c.mu.Unlock()
+				for _, t := range left {
+					c.abandon(t)
+				}
+				return
+			case <-c.retried:
+			case <-timer.C:
+			}
+		}
+	}()
+	return stopped
+}
+
+// retry queues a record whose handler failed for startRetries. The record keeps its place in the partition,
+// so the offsets after it are not committed before it is done.
+func (c *Consumer) retry(t task) {
+	c.mu.Lock()
+	if c.stopping || isClosed(t.partition.released) {
+		// The release of the partition is waiting for the record: the next owner handles it.
+		c.mu.Unlock()
+		c.abandon(t)
+		return
+	}
+	// Until it is handed to the handler again, a release of the partition gives the record up like a queued one.
+	t.offset.started = false
+	t.retryAt = time.Now().Add(c.opts.RetryBackoff)
+	c.retries = append(c.retries, t)
+	c.mu.Unlock()
+	select {
+	case c.retried <- struct{}{}:
+	default:
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitDue waits until the record is Delay old; it returns false if ctx is cancelled
+// or the partition of the record is released first.
+func (c *Consumer) waitDue(ctx context.Context, t task) bool {
+	if c.opts.Delay <= 0 {
+		return true
+	}
+	wait := time.Until(t.record.Timestamp.Add(c.opts.Delay))
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.partition.released:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// start marks a record as handed to the handler; it returns false if the record was given up meanwhile,
+// e.g. because its partition was released while it was queued.
+func (c *Consumer) start(t task) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.offset.abandoned {
+		return false
+	}
+	t.offset.started = true
+	return true
+}
+
+// stopWorkers waits until the handler returned for every queued record.
+func (c *Consumer) stopWorkers(wg *sync.WaitGroup) {
+	for _, queue := range c.workers {
+		close(queue)
+	}
+	wg.Wait()
+}
+
+// waitDone waits until every dispatched record is done or abandoned.
+func (c *Consumer) waitDone() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.inFlight > 0 {
+		c.drained.Wait()
+	}
+}
+
+// Close leaves the group and closes the client. It must be called after Run returns.
+func (c *Consumer) Close() {
+	c.client.Close()
+}
+
+// Pause stops fetching the topics; the records in flight are still handled and committed.
+func (c *Consumer) Pause() {
+	if !c.paused.Swap(true) {
+		c.client.PauseFetchTopics(c.opts.Topics...)
+		c.logger.Warn("чтение из kafka приостановлено", "topics", c.opts.Topics)
+	}
+}
+
+// Resume fetches the topics again after Pause.
+func (c *Consumer) Resume() {
+	if c.paused.Swap(false) {
+		c.client.ResumeFetchTopics(c.opts.Topics...)
+		c.logger.Info("чтение из kafka возобновлено", "topics", c.opts.Topics)
+	}
+}
+
+// Paused reports whether the consumption is paused by Pause.
+func (c *Consumer) Paused() bool {
+	return c.paused.Load()
+}
+
+// Lag returns by topic and partition how many records of the assigned partitions are not done yet:
+// the high watermark of the last fetch minus the committable offset.
+func (c *Consumer) Lag() map[string]map[int32]int64 {
+	lag := make(map[string]map[int32]int64)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for tp, ps := range c.partitions {
+		var position int64
+		switch {
+		case len(ps.pending) > 0:
+			position = ps.pending[0].offset
+		case ps.commit.Offset > 0:
+			position = ps.commit.Offset
+		default:
+			continue
+		}
+		if lag[tp.topic] == nil {
+			lag[tp.topic] = make(map[int32]int64)
+		}
+		lag[tp.topic][tp.partition] = max(ps.highWatermark-position, 0)
+	}
+	return lag
+}
+
+// waitForRoom returns how many records may be polled, waiting while MaxInFlight records are in flight.
+func (c *Consumer) waitForRoom(ctx context.Context) (int, bool) {
+	for {
+		c.mu.Lock()
+		room := c.opts.MaxInFlight - c.inFlight
+		c.mu.Unlock()
+		if room > 0 {
+			return room, true
+		}
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-c.freed:
+		}
+	}
+}
+
+// dispatch queues the polled records to the workers and pauses the partitions that reached MaxPartitionInFlight.
+func (c *Consumer) dispatch(fetches kgo.Fetches) {
+	var tasks []task
+	pause := make(map[string][]int32)
+
+	c.mu.Lock()
+	fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+		tp := topicPartition{p.Topic, p.Partition}
+		// The partition was revoked after the poll: its new owner reads the records again.
+		if !c.assigned[tp] {
+			return
+		}
+		ps, ok := c.partitions[tp]
+		if !ok {
+			ps = &partitionState{released: make(chan struct{})}
+			c.partitions[tp] = ps
+		}
+		ps.highWatermark = max(ps.highWatermark, p.HighWatermark)
+	})
+	fetches.EachRecord(func(r *kgo.Record) {
+		tp := topicPartition{r.Topic, r.Partition}
+		if !c.assigned[tp] {
+			return
+		}
+		ps := c.partitions[tp]
+		offset := &pendingOffset{offset: r.Offset, epoch: r.LeaderEpoch}
+		ps.pending = append(ps.pending, offset)
+		ps.inFlight++
+		c.inFlight++
+		tasks = append(tasks, task{record: r, offset: offset, partition: ps})
+		if !ps.paused && len(ps.pending) >= c.opts.MaxPartitionInFlight {
+			ps.paused = true
+			pause[r.Topic] = append(pause[r.Topic], r.Partition)
+		}
+	})
+	c.mu.Unlock()
+
+	if len(pause) > 0 {
+		c.client.PauseFetchPartitions(pause)
+		c.logger.Debug("чтение партиций приостановлено: воркеры не успевают", "partitions", pause)
+	}
+	for _, t := range tasks {
+		c.workers[c.worker(t.record)] <- t
+	}
+}
+
+// worker returns the index of the worker that handles the key of r, so records with one key are handled in order.
+func (c *Consumer) worker(r *kgo.Record) int {
+	h := fnv.New32a()
+	_, _ = h.Write(r.Key)
+	return int(h.Sum32() % uint32(len(c.workers)))
+}
+
+// complete marks a record done, advances the committable offset of its partition
+// and resumes the partition once half of its records in flight are handled.
+func (c *Consumer) complete(t task) {
+	tp := topicPartition{t.record.Topic, t.record.Partition}
+
+	c.mu.Lock()
+	t.offset.done = true
+	c.inFlight--
+	ps := t.partition
+	ps.inFlight--
+	for len(ps.pending) > 0 && ps.pending[0].done {
+		head := ps.pending[0]
+		ps.commit = kgo.EpochOffset{Epoch: head.epoch, Offset: head.offset + 1}
+		ps.dirty = true
+		ps.pending = ps.pending[1:]
+	}
+	resume := ps.paused && len(ps.pending) <= c.opts.MaxPartitionInFlight/2
+	if resume {
+		ps.paused = false
+	}
+	c.drained.Broadcast()
+	c.mu.Unlock()
+
+	if resume {
+		c.client.ResumeFetchPartitions(map[string][]int32{tp.topic: {tp.partition}})
+	}
+	select {
+	case c.freed <- struct{}{}:
+	default:
+	}
+}
+
+// abandon gives up a record: its offset and the ones after it are not committed,
+// so the record is read again by the next owner of the partition.
+func (c *Consumer) abandon(t task) {
+	c.mu.Lock()
+	if t.offset.abandoned {
+		// Already given up when its partition was released.
+		c.mu.Unlock()
+		return
+	}
+	t.offset.abandoned = true
+	c.inFlight--
+	t.partition.inFlight--
+	c.drained.Broadcast()
+	c.mu.Unlock()
+	select {
+	case c.freed <- struct{}{}:
+	default:
+	}
+}
+
+// commit commits the committable offsets of the given partitions, or of all partitions if only is nil.
+func (c *Consumer) commit(ctx context.Context, only map[topicPartition]bool) {
+	offsets := make(map[string]map[int32]kgo.EpochOffset)
+	c.mu.Lock()
+	for tp, ps := range c.partitions {
+		if !ps.dirty || (only != nil && !only[tp]) {
+			continue
+		}
+		if offsets[tp.topic] == nil {
+			offsets[tp.topic] = make(map[int32]kgo.EpochOffset)
+		}
+		offsets[tp.topic][tp.partition] = ps.commit
+		ps.dirty = false
+	}
+	c.mu.Unlock()
+	if len(offsets) == 0 {
+		return
+	}
+
+	c.client.CommitOffsetsSync(ctx, offsets, func(_ *kgo.Client, _ *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error) {
+		if err != nil {
+			c.logger.Error("error committing offsets", "error", err)
+			return
+		}
+		for _, topic := range resp.Topics {
+			for _, p := range topic.Partitions {
+				if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+					c.logger.Error("error committing offsets", "topic", topic.Topic, "partition", p.Partition, "error", err)
+				}
+			}
+		}
+	})
+}
+
+func (c *Consumer) onAssigned(_ context.Context, _ *kgo.Client, assigned map[string][]int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, partitions := range assigned {
+		for _, p := range partitions {
+			c.assigned[topicPartition{topic, p}] = true
+		}
+	}
+}
+
+// onRevoked lets the records of the revoked partitions finish and commits them before the partitions move on.
+func (c *Consumer) onRevoked(ctx context.Context, client *kgo.Client, revoked map[string][]int32) {
+	released := c.release(revoked)
+	c.commit(ctx, released)
+	c.forget(client, released, revoked)
+}
+
+// onLost lets the records of the lost partitions finish; their offsets cannot be committed any more.
+func (c *Consumer) onLost(_ context.Context, client *kgo.Client, lost map[string][]int32) {
+	c.forget(client, c.release(lost), lost)
+}
+
+// release stops dispatching the partitions and waits until the records already handed to the handler are done.
+// The records not handed over yet, queued or waiting for their delay, are left to the next owner right away.
+func (c *Consumer) release(partitions map[string][]int32) map[topicPartition]bool {
+	released := make(map[topicPartition]bool)
+	c.mu.Lock()
+	for topic, ps := range partitions {
+		for _, p := range ps {
+			tp := topicPartition{topic, p}
+			delete(c.assigned, tp)
+			released[tp] = true
+		}
+	}
+	for tp := range released {
+		ps := c.partitions[tp]
+		if ps == nil {
+			continue
+		}
+		select {
+		case <-ps.released:
+		default:
+			close(ps.released)
+		}
+		for _, p := range ps.pending {
+			if !p.started && !p.abandoned {
+				p.abandoned = true
+				ps.inFlight--
+				c.inFlight--
+			}
+		}
+		for ps.inFlight > 0 {
+			c.drained.Wait()
+		}
+	}
+	c.drained.Broadcast()
+	c.mu.Unlock()
+	select {
+	case c.freed <- struct{}{}:
+	default:
+	}
+	return released
+}
+
+// forget drops the state of released partitions and resumes them, so they are fetched when assigned again.
+func (c *Consumer) forget(client *kgo.Client, released map[topicPartition]bool, partitions map[string][]int32) {
+	c.mu.Lock()
+	for tp := range released {
+		delete(c.partitions, tp)
+	}
+	c.mu.Unlock()
+	client.ResumeFetchPartitions(partitions)
+}
@@ -0,0 +1,247 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func newTestConsumer(t *testing.T, maxPartitionInFlight int, handler Handler) *Consumer {
+	t.Helper()
+	c, err := NewConsumer(ConsumerOptions{
+		Brokers:              []string{"127.0.0.1:1"},
+		Group:                "test",
+		Topics:               []string{"tx"},
+		Workers:              4,
+		MaxInFlight:          100,
+		MaxPartitionInFlight: maxPartitionInFlight,
+	}, handler, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	c.onAssigned(context.Background(), c.client, map[string][]int32{"tx": {0}})
+	return c
+}
+
+// fetch returns records of partition 0 of tx with the given keys, starting at offset 0.
+func fetch(keys ...string) kgo.Fetches {
+	var records []*kgo.Record
+	for i, key := range keys {
+		records = append(records, &kgo.Record{Topic: "tx", Partition: 0, Offset: int64(i), Key: []byte(key)})
+	}
+	return kgo.Fetches{{Topics: []kgo.FetchTopic{{Topic: "tx", Partitions: []kgo.FetchPartition{{Partition: 0, HighWatermark: 10, Records: records}}}}}}
+}
+
+func TestConsumer_KeepsKeyOrderAndCommitsContiguousOffsets(t *testing.T) {
+	var mu sync.Mutex
+	handled := make(map[string][]int64)
+	c := newTestConsumer(t, 0, func(_ context.Context, r *kgo.Record, done func(error)) {
+		defer done(nil)
+		// Key a is slow, so the records of b finish first.
+		if string(r.Key) == "a" {
+			time.Sleep(time.Millisecond)
+		}
+		mu.Lock()
+		handled[string(r.Key)] = append(handled[string(r.Key)], r.Offset)
+		mu.Unlock()
+	})
+
+	workers := c.startWorkers(context.Background())
+	c.dispatch(fetch("a", "b", "a", "b", "a", "b", "a", "b"))
+	c.stopWorkers(workers)
+
+	assert.Equal(t, []int64{0, 2, 4, 6}, handled["a"])
+	assert.Equal(t, []int64{1, 3, 5, 7}, handled["b"])
+	ps := c.partitions[topicPartition{"tx", 0}]
+	assert.Empty(t, ps.pending)
+	assert.Equal(t, int64(8), ps.commit.Offset)
+	assert.True(t, ps.dirty)
+	assert.Zero(t, c.inFlight)
+}
+
+func TestConsumer_PausesSaturatedPartition(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestConsumer(t, 4, func(_ context.Context, _ *kgo.Record, done func(error)) {
+		<-release
+		done(nil)
+	})
+
+	workers := c.startWorkers(context.Background())
+	c.dispatch(fetch("a", "b", "c", "d", "e"))
+	assert.Equal(t, map[string][]int32{"tx": {0}}, c.client.PauseFetchPartitions(nil))
+
+	// Nothing is committable while the first record is in flight.
+	ps := c.partitions[topicPartition{"tx", 0}]
+	c.mu.Lock()
+	assert.False(t, ps.dirty)
+	c.mu.Unlock()
+
+	close(release)
+	c.stopWorkers(workers)
+	assert.Empty(t, c.client.PauseFetchPartitions(nil))
+	assert.Equal(t, int64(5), ps.commit.Offset)
+}
+
+func TestConsumer_DropsRecordsOfRevokedPartitions(t *testing.T) {
+	c := newTestConsumer(t, 0, func(context.Context, *kgo.Record, func(error)) {
+		t.Error("a record of a revoked partition was handled")
+	})
+	released := c.release(map[string][]int32{"tx": {0}})
+	c.forget(c.client, released, map[string][]int32{"tx": {0}})
+
+	workers := c.startWorkers(context.Background())
+	c.dispatch(fetch("a"))
+	c.stopWorkers(workers)
+	assert.Empty(t, c.partitions)
+}
+
+func TestConsumer_CommitsOnlyDoneRecords(t *testing.T) {
+	var acks []func(error)
+	var mu sync.Mutex
+	c := newTestConsumer(t, 0, func(_ context.Context, _ *kgo.Record, done func(error)) {
+		mu.Lock()
+		acks = append(acks, done)
+		mu.Unlock()
+	})
+
+	workers := c.startWorkers(context.Background())
+	c.dispatch(fetch("a", "a", "a"))
+	c.stopWorkers(workers)
+
+	ps := c.partitions[topicPartition{"tx", 0}]
+	assert.False(t, ps.dirty)
+	assert.Equal(t, 3, c.inFlight)
+
+	// The batch of the first two records is written; done may be called twice.
+	acks[1](nil)
+	acks[0](nil)
+	acks[0](nil)
+	assert.Equal(t, int64(2), ps.commit.Offset)
+	assert.Equal(t, 1, c.inFlight)
+
+	assert.Equal(t, map[string]map[int32]int64{"tx": {0: 8}}, c.Lag())
+
+	acks[2](nil)
+	c.waitDone()
+	assert.Equal(t, int64(3), ps.commit.Offset)
+	assert.Equal(t, map[string]map[int32]int64{"tx": {0: 7}}, c.Lag())
+}
+
+func TestConsumer_PauseStopsFetchingTopics(t *testing.T) {
+	c := newTestConsumer(t, 0, func(_ context.Context, _ *kgo.Record, done func(error)) { done(nil) })
+
+	c.Pause()
+	assert.True(t, c.Paused())
+	assert.Equal(t, []string{"tx"}, c.client.PauseFetchTopics())
+	c.Resume()
+	assert.False(t, c.Paused())
+	assert.Empty(t, c.client.PauseFetchTopics())
+}
+
+func TestConsumer_AbandonsDelayedRecordsOnShutdown(t *testing.T) {
+	c := newTestConsumer(t, 0, func(context.Context, *kgo.Record, func(error)) {
+		t.Error("a record was handled before its delay")
+	})
+	c.opts.Delay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := c.startWorkers(ctx)
+	f := fetch("a")
+	f[0].Topics[0].Partitions[0].Records[0].Timestamp = time.Now()
+	c.dispatch(f)
+	cancel()
+	c.stopWorkers(workers)
+	c.waitDone()
+
+	ps := c.partitions[topicPartition{"tx", 0}]
+	assert.False(t, ps.dirty)
+	assert.Zero(t, ps.inFlight)
+}
+
+func TestConsumer_AbandonsDelayedRecordsOfRevokedPartitions(t *testing.T) {
+	c := newTestConsumer(t, 0, func(context.Context, *kgo.Record, func(error)) {
+		t.Error("a record of a revoked partition was handled")
+	})
+	c.opts.Delay = time.Hour
+
+	workers := c.startWorkers(context.Background())
+	f := fetch("a", "b")
+	for _, r := range f[0].Topics[0].Partitions[0].Records {
+		r.Timestamp = time.Now()
+	}
+	c.dispatch(f)
+
+	// The revoke does not wait an hour for the delayed records.
+	released := c.release(map[string][]int32{"tx": {0}})
+	c.forget(c.client, released, map[string][]int32{"tx": {0}})
+	c.stopWorkers(workers)
+
+	assert.Zero(t, c.inFlight)
+	assert.Empty(t, c.partitions)
+}
+
+func TestConsumer_RetriesFailedRecordAndResumesPartition(t *testing.T) {
+	var failed atomic.Bool
+	c := newTestConsumer(t, 4, func(_ context.Context, r *kgo.Record, done func(error)) {
+		if r.Offset == 1 && !failed.Swap(true) {
+			done(errors.New("dlq unavailable"))
+			return
+		}
+		done(nil)
+	})
+	c.opts.RetryBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := c.startWorkers(ctx)
+	retrier := c.startRetries(ctx)
+	c.dispatch(fetch("a", "b", "c", "d", "e"))
+	assert.Equal(t, map[string][]int32{"tx": {0}}, c.client.PauseFetchPartitions(nil))
+
+	// The failed record holds the commit point until its retry is done, then the partition is fetched again.
+	ps := c.partitions[topicPartition{"tx", 0}]
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return ps.commit.Offset == 5
+	}, time.Second, time.Millisecond)
+	assert.True(t, failed.Load())
+	assert.Empty(t, c.client.PauseFetchPartitions(nil))
+
+	cancel()
+	<-retrier
+	c.stopWorkers(workers)
+	assert.Zero(t, c.inFlight)
+}
+
+func TestConsumer_GivesUpFailingRecordOnShutdown(t *testing.T) {
+	c := newTestConsumer(t, 0, func(_ context.Context, r *kgo.Record, done func(error)) {
+		if r.Offset == 1 {
+			done(errors.New("dlq unavailable"))
+			return
+		}
+		done(nil)
+	})
+	c.opts.RetryBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := c.startWorkers(ctx)
+	retrier := c.startRetries(ctx)
+	c.dispatch(fetch("a", "a", "a"))
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-retrier
+	c.stopWorkers(workers)
+	c.waitDone()
+
+	ps := c.partitions[topicPartition{"tx", 0}]
+	assert.Equal(t, int64(1), ps.commit.Offset)
+	assert.Zero(t, c.inFlight)
+}
@@ -2,7 +2,6 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -10,18 +9,23 @@ import (
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/events"
 )
 
+// EventSource is the CloudEvents source of the events published by the payment API.
+const EventSource = "/payment-api"
+
 // Broker is an implementation of the MessageBroker port for Kafka.
 type Broker struct {
-	client *kgo.Client
-	topic  string
-	logger *slog.Logger
-	wg     sync.WaitGroup
+	client  *kgo.Client
+	topic   string
+	encoder *EventEncoder
+	logger  *slog.Logger
+	wg      sync.WaitGroup
 }
 
-// NewBroker creates a new Kafka broker instance.
-func NewBroker(bootstrapServers []string, topic string, logger *slog.Logger) (*Broker, error) {
+// NewBroker creates a new Kafka broker instance; events are encoded by encoder.
+func NewBroker(bootstrapServers []string, topic string, encoder *EventEncoder, logger *slog.Logger) (*Broker, error) {
 	opts := []kgo.Opt{
 		kgo.SeedBrokers(bootstrapServers...),
 		kgo.DefaultProduceTopic(topic),
@@ -41,32 +45,30 @@ func NewBroker(bootstrapServers []string, topic string, logger *slog.Logger) (*B
 	}
 
 	return &Broker{
-		client: client,
-		topic:  topic,
-		logger: logger,
+		client:  client,
+		topic:   topic,
+		encoder: encoder,
+		logger:  logger,
 	}, nil
 }
 
 // PublishTransactionCreated publishes an event about the creation of a transaction.
 func (b *Broker) PublishTransactionCreated(ctx context.Context, tx domain.Transaction) error {
-	// Creating a message structure for Kafka
-	message := map[string]interface{}{
-		"transaction_id":   tx.ID.String(),
-		"amount":           tx.Amount,
-		"currency":         tx.Currency,
-		"card_number_hash": tx.CardNumberHash,
-		"status":           string(tx.Status),
-		"idempotency_key":  tx.IdempotencyKey.String(),
-		"created_at":       tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-	payload, err := json.Marshal(message)
+	// The event is encoded by the shared contract: the consumers decode it with the same package.
+	payload, err := b.encoder.Encode(events.NewTransactionCreated(ctx, EventSource, tx))
 	if err != nil {
-		return fmt.Errorf("failed to marshal transaction: %w", err)
+		return fmt.Errorf("failed to encode transaction event: %w", err)
 	}
 
+	// Keying by card puts every transaction of a card on one partition, so one analyzer replica sees them in order.
+	key := tx.CardNumberHash
+	if key == "" {
+		key = tx.ID.String()
+	}
 	record := &kgo.Record{
-		Key:   []byte(tx.ID.String()),
-		Value: payload,
+		Key:     []byte(key),
+		Value:   payload,
+		Headers: []kgo.RecordHeader{{Key: "content-type", Value: []byte(b.encoder.ContentType())}},
 	}
 
 	b.wg.Add(1)
@@ -82,6 +84,7 @@ func (b *Broker) PublishTransactionCreated(ctx context.Context, tx domain.Transa
 
 	return nil
 }
+
 // Close gracefully stops the producer.
 func (b *Broker) Close() {
 	b.logger.Info("ожидание завершения отправки сообщений в kafka...")
@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Headers of the records sent to the retry topics and to the DLQ.
+const (
+	HeaderErrorType      = "error_type"
+	HeaderErrorString    = "error_string"
+	HeaderOriginalTopic  = "original_topic"
+	HeaderRetryAttempt   = "retry_attempt"
+	HeaderFirstFailureAt = "first_failure_at"
+)
+
+// RetryTier is a retry topic whose records are handled Delay after they were sent to it.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+}
+
+// RetryTiers names a tier per delay after the topic, e.g. transactions.created.retry.10m.
+func RetryTiers(topic string, delays []time.Duration) []RetryTier {
+	tiers := make([]RetryTier, len(delays))
+	for i, d := range delays {
+		tiers[i] = RetryTier{Topic: topic + ".retry." + delayName(d), Delay: d}
+	}
+	return tiers
+}
+
+func delayName(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// RetryRouter moves failed records up the retry ladder: a record failing with a transient error goes
+// to the next retry topic, and to the DLQ once the last tier failed too.
+type RetryRouter struct {
+	producer *kgo.Client
+	tiers    []RetryTier
+	dlqTopic string
+	now      func() time.Time
+}
+
+// NewRetryRouter creates a router producing with an existing client.
+func NewRetryRouter(producer *kgo.Client, tiers []RetryTier, dlqTopic string) *RetryRouter {
+	return &RetryRouter{producer: producer, tiers: tiers, dlqTopic: dlqTopic, now: time.Now}
+}
+
+// Retry sends the record to its next retry tier, or to the DLQ if the tiers are exhausted.
+// done is called with the outcome of the delivery.
+func (r *RetryRouter) Retry(ctx context.Context, record *kgo.Record, errorType string, cause error, done func(error)) {
+	attempt := RetryAttempt(record)
+	if attempt >= len(r.tiers) {
+		r.DeadLetter(ctx, record, errorType, cause, done)
+		return
+	}
+	r.produce(ctx, r.failed(record, r.tiers[attempt].Topic, attempt+1, errorType, cause), done)
+}
+
+// DeadLetter sends the record to the DLQ; it is not retried automatically any more.
+func (r *RetryRouter) DeadLetter(ctx context.Context, record *kgo.Record, errorType string, cause error, done func(error)) {
+	r.produce(ctx, r.failed(record, r.dlqTopic, RetryAttempt(record), errorType, cause), done)
+}
+
+// failed copies the record to topic with the failure headers. The headers of the original record, e.g. the trace
+// context, are kept; the original topic and the time of the first failure are kept from the first failure.
+func (r *RetryRouter) failed(record *kgo.Record, topic string, attempt int, errorType string, cause error) *kgo.Record {
+	originalTopic := record.Topic
+	firstFailure := r.now().UTC().Format(time.RFC3339Nano)
+	headers := make([]kgo.RecordHeader, 0, len(record.Headers)+5)
+	for _, h := range record.Headers {
+		switch h.Key {
+		case HeaderOriginalTopic:
+			originalTopic = string(h.Value)
+		case HeaderFirstFailureAt:
+			firstFailure = string(h.Value)
+		case HeaderErrorType, HeaderErrorString, HeaderRetryAttempt:
+		default:
+			headers = append(headers, h)
+		}
+	}
+	headers = append(headers,
+		kgo.RecordHeader{Key: HeaderErrorType, Value: []byte(errorType)},
+		kgo.RecordHeader{Key: HeaderErrorString, Value: []byte(cause.Error())},
+		kgo.RecordHeader{Key: HeaderOriginalTopic, Value: []byte(originalTopic)},
+		kgo.RecordHeader{Key: HeaderRetryAttempt, Value: []byte(strconv.Itoa(attempt))},
+		kgo.RecordHeader{Key: HeaderFirstFailureAt, Value: []byte(firstFailure)},
+	)
+	// The timestamp is set by the client when the record is produced: the delay of the tier counts from it.
+	return &kgo.Record{Topic: topic, Key: record.Key, Value: record.Value, Headers: headers}
+}
+
+func (r *RetryRouter) produce(ctx context.Context, record *kgo.Record, done func(error)) {
+	r.producer.Produce(ctx, record, func(_ *kgo.Record, err error) {
+		if err != nil {
+			err = fmt.Errorf("failed to send record to %s: %w", record.Topic, err)
+		}
+		done(err)
+	})
+}
+
+// RetryAttempt returns how many retry tiers the record has been through.
+func RetryAttempt(record *kgo.Record) int {
+	for _, h := range record.Headers {
+		if h.Key == HeaderRetryAttempt {
+			n, err := strconv.Atoi(string(h.Value))
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
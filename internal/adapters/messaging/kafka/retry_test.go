@@ -0,0 +1,58 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func headers(r *kgo.Record) map[string]string {
+	h := make(map[string]string, len(r.Headers))
+	for _, header := range r.Headers {
+		h[header.Key] = string(header.Value)
+	}
+	return h
+}
+
+func TestRetryTiers(t *testing.T) {
+	tiers := RetryTiers("transactions.created", []time.Duration{time.Minute, 10 * time.Minute, time.Hour, 90 * time.Second})
+	assert.Equal(t, []RetryTier{
+		{Topic: "transactions.created.retry.1m", Delay: time.Minute},
+		{Topic: "transactions.created.retry.10m", Delay: 10 * time.Minute},
+		{Topic: "transactions.created.retry.1h", Delay: time.Hour},
+		{Topic: "transactions.created.retry.90s", Delay: 90 * time.Second},
+	}, tiers)
+}
+
+func TestRetryRouter_KeepsFirstFailure(t *testing.T) {
+	first := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	r := NewRetryRouter(nil, RetryTiers("tx", []time.Duration{time.Minute, time.Hour}), "tx.dlq")
+	r.now = func() time.Time { return first }
+
+	original := &kgo.Record{Topic: "tx", Key: []byte("k"), Value: []byte("v"), Headers: []kgo.RecordHeader{{Key: "traceparent", Value: []byte("00-abc")}}}
+	retried := r.failed(original, "tx.retry.1m", 1, "storage_error", errors.New("timeout"))
+	assert.Equal(t, map[string]string{
+		"traceparent":      "00-abc",
+		"error_type":       "storage_error",
+		"error_string":     "timeout",
+		"original_topic":   "tx",
+		"retry_attempt":    "1",
+		"first_failure_at": "2026-03-10T12:00:00Z",
+	}, headers(retried))
+	assert.Equal(t, 1, RetryAttempt(retried))
+
+	// The second failure happens an hour later on the retry topic.
+	r.now = func() time.Time { return first.Add(time.Hour) }
+	retried.Topic = "tx.retry.1m"
+	again := r.failed(retried, "tx.retry.1h", 2, "evaluation_error", errors.New("redis down"))
+	h := headers(again)
+	assert.Equal(t, "tx", h["original_topic"])
+	assert.Equal(t, "2026-03-10T12:00:00Z", h["first_failure_at"])
+	assert.Equal(t, "evaluation_error", h["error_type"])
+	assert.Equal(t, "2", h["retry_attempt"])
+	assert.Len(t, again.Headers, 6)
+	assert.Equal(t, []byte("k"), again.Key)
+}
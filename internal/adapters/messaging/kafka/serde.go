@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"payment-processing-system/internal/adapters/schemaregistry"
+	"payment-processing-system/internal/events"
+)
+
+// ErrNoSchemaRegistry means a record in the schema registry wire format was read without a registry configured.
+var ErrNoSchemaRegistry = errors.New("schema registry is not configured")
+
+// contentTypes are the content-type headers of the records by format.
+var contentTypes = map[events.Format]string{
+	events.FormatJSON:     events.ContentType,
+	events.FormatProtobuf: "application/x-protobuf",
+	events.FormatAvro:     "application/avro",
+}
+
+// EventEncoder encodes the transaction events of a producer in the configured format. The values of the
+// formats with a schema carry the schema ID in the Confluent wire format.
+type EventEncoder struct {
+	serializer events.Serializer
+	schemaID   int
+}
+
+// NewEventEncoder checks the schema of the serializer against the latest version of subject and registers it:
+// an incompatible change stops the producer at startup instead of breaking the consumers.
+// The JSON format needs no registry, registry may be nil.
+func NewEventEncoder(ctx context.Context, serializer events.Serializer, registry *schemaregistry.Client, subject string) (*EventEncoder, error) {
+	s, ok := serializer.(events.SchemaSerializer)
+	if !ok {
+		return &EventEncoder{serializer: serializer}, nil
+	}
+	if registry == nil {
+		return nil, fmt.Errorf("%s serialization: %w", serializer.Format(), ErrNoSchemaRegistry)
+	}
+	schema := schemaregistry.Schema{Type: s.SchemaType(), Schema: s.Schema()}
+	if err := registry.CheckCompatibility(ctx, subject, schema); err != nil {
+		return nil, err
+	}
+	id, err := registry.Register(ctx, subject, schema)
+	if err != nil {
+		return nil, err
+	}
+	return &EventEncoder{serializer: serializer, schemaID: id}, nil
+}
+
+// Encode returns the record value of the event.
+func (e *EventEncoder) Encode(event events.TransactionCreatedEvent) ([]byte, error) {
+	payload, err := e.serializer.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if e.schemaID == 0 {
+		return payload, nil
+	}
+	value := schemaregistry.AppendHeader(make([]byte, 0, len(payload)+6), e.schemaID)
+	if e.serializer.Format() == events.FormatProtobuf {
+		// TransactionCreatedEvent is the first message of events.proto.
+		value = schemaregistry.AppendMessageIndexes(value, 0)
+	}
+	return append(value, payload...), nil
+}
+
+// ContentType is the content-type header of the records.
+func (e *EventEncoder) ContentType() string {
+	return contentTypes[e.serializer.Format()]
+}
+
+// EventDecoder reads transaction events in any format, so the producers can switch formats while the
+// consumers run: a record in the wire format is decoded by the type of its schema, any other as JSON.
+type EventDecoder struct {
+	registry *schemaregistry.Client
+	json     events.JSONSerializer
+	avro     *events.AvroSerializer
+	protobuf events.ProtobufSerializer
+}
+
+// NewEventDecoder creates a decoder; without a registry only JSON records can be read.
+func NewEventDecoder(registry *schemaregistry.Client) *EventDecoder {
+	return &EventDecoder{registry: registry, avro: events.NewAvroSerializer()}
+}
+
+// Decode decodes the record value. Errors wrapping schemaregistry.ErrUnavailable are transient.
+func (d *EventDecoder) Decode(ctx context.Context, value []byte) (events.TransactionCreatedEvent, error) {
+	if !schemaregistry.Framed(value) {
+		return d.json.Unmarshal(value, "")
+	}
+	if d.registry == nil {
+		return events.TransactionCreatedEvent{}, ErrNoSchemaRegistry
+	}
+	id, payload, err := schemaregistry.ReadHeader(value)
+	if err != nil {
+		return events.TransactionCreatedEvent{}, err
+	}
+	schema, err := d.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return events.TransactionCreatedEvent{}, err
+	}
+	switch schema.TypeName() {
+	case events.SchemaTypeAvro:
+		return d.avro.Unmarshal(payload, schema.Schema)
+	case events.SchemaTypeProtobuf:
+		indexes, payload, err := schemaregistry.ReadMessageIndexes(payload)
+		if err != nil {
+			return events.TransactionCreatedEvent{}, err
+		}
+		if !slices.Equal(indexes, []int{0}) {
+			return events.TransactionCreatedEvent{}, fmt.Errorf("%w: protobuf message %v of schema %d", events.ErrUnsupportedEvent, indexes, id)
+		}
+		return d.protobuf.Unmarshal(payload, schema.Schema)
+	default:
+		return events.TransactionCreatedEvent{}, fmt.Errorf("%w: schema type %s of schema %d", events.ErrUnsupportedEvent, schema.TypeName(), id)
+	}
+}
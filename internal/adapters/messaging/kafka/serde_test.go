@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"payment-processing-system/internal/adapters/schemaregistry"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/events"
+)
+
+const testSubject = "transactions.created-value"
+
+func newTestRegistry(t *testing.T) *schemaregistry.Client {
+	t.Helper()
+	server := httptest.NewServer(schemaregistry.NewStub())
+	t.Cleanup(server.Close)
+	return schemaregistry.NewClient(server.URL)
+}
+
+func testEvent() events.TransactionCreatedEvent {
+	return events.NewTransactionCreated(context.Background(), EventSource, domain.Transaction{
+		ID:             uuid.New(),
+		Amount:         42.5,
+		Currency:       "EUR",
+		CardNumberHash: "card-1",
+		Status:         domain.StatusProcessing,
+		IdempotencyKey: uuid.New(),
+		CreatedAt:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+}
+
+// The decoder reads every format from the same topic, so the producers can switch formats one by one.
+func TestEventDecoder_ReadsEveryFormat(t *testing.T) {
+	registry := newTestRegistry(t)
+	decoder := NewEventDecoder(registry)
+	event := testEvent()
+
+	for _, format := range []events.Format{events.FormatJSON, events.FormatProtobuf, events.FormatAvro} {
+		serializer, err := events.NewSerializer(format)
+		require.NoError(t, err)
+		// Each format has its subject: the stub rejects a change of schema type.
+		encoder, err := NewEventEncoder(context.Background(), serializer, registry, testSubject+"."+string(format))
+		require.NoError(t, err, format)
+		value, err := encoder.Encode(event)
+		require.NoError(t, err)
+
+		decoded, err := decoder.Decode(context.Background(), value)
+
+		require.NoError(t, err, format)
+		assert.Equal(t, event, decoded, format)
+		assert.Equal(t, format != events.FormatJSON, schemaregistry.Framed(value), format)
+	}
+}
+
+func TestNewEventEncoder_RejectsIncompatibleSchemaAtStartup(t *testing.T) {
+	registry := newTestRegistry(t)
+	// The latest version has a required field the current schema does not know.
+	_, err := registry.Register(context.Background(), testSubject, schemaregistry.Schema{
+		Type:   events.SchemaTypeAvro,
+		Schema: `{"type":"record","name":"TransactionCreatedEvent","namespace":"payment.transactions.v1","fields":[{"name":"id","type":"int"}]}`,
+	})
+	require.NoError(t, err)
+
+	_, err = NewEventEncoder(context.Background(), events.NewAvroSerializer(), registry, testSubject)
+
+	assert.ErrorIs(t, err, schemaregistry.ErrIncompatible)
+}
+
+func TestEventDecoder_NeedsRegistryForFramedRecords(t *testing.T) {
+	encoder, err := NewEventEncoder(context.Background(), events.NewAvroSerializer(), newTestRegistry(t), testSubject)
+	require.NoError(t, err)
+	value, err := encoder.Encode(testEvent())
+	require.NoError(t, err)
+
+	_, err = NewEventDecoder(nil).Decode(context.Background(), value)
+
+	assert.ErrorIs(t, err, ErrNoSchemaRegistry)
+}
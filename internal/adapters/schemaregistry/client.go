@@ -0,0 +1,173 @@
+// Package schemaregistry is a client of the Confluent Schema Registry REST API, its wire format
+// and an in-process registry for tests and local runs.
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+var (
+	// ErrNotFound means the registry has no such subject, version or schema.
+	ErrNotFound = errors.New("not found in schema registry")
+	// ErrIncompatible means the schema breaks the compatibility rules of the subject.
+	ErrIncompatible = errors.New("incompatible schema")
+	// ErrUnavailable means the registry could not be reached or failed; the call may succeed later.
+	ErrUnavailable = errors.New("schema registry unavailable")
+)
+
+// Schema is a schema as the registry keeps it; an empty type is AVRO.
+type Schema struct {
+	Type   string `json:"schemaType,omitempty"`
+	Schema string `json:"schema"`
+}
+
+// TypeName is the schema type with the default spelled out.
+func (s Schema) TypeName() string {
+	if s.Type == "" {
+		return "AVRO"
+	}
+	return s.Type
+}
+
+// Error is an error answer of the registry.
+type Error struct {
+	Status  int    `json:"-"`
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+// Is maps the HTTP status to ErrNotFound, ErrIncompatible and ErrUnavailable.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrIncompatible:
+		return e.Status == http.StatusConflict
+	case ErrUnavailable:
+		return e.Status >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// ValueSubject is the subject of the record values of a topic (TopicNameStrategy).
+func ValueSubject(topic string) string {
+	return topic + "-value"
+}
+
+// Client calls the registry; schemas read by ID are cached, they never change.
+type Client struct {
+	client *http.Client
+	url    string
+
+	mu   sync.Mutex
+	byID map[int]Schema
+}
+
+// NewClient creates a client for the registry at url.
+func NewClient(url string) *Client {
+	return &Client{
+		client: &http.Client{Timeout: 5 * time.Second},
+		url:    strings.TrimSuffix(url, "/"),
+		byID:   make(map[int]Schema),
+	}
+}
+
+// Register registers the schema under subject and returns its ID; a schema registered before keeps its ID.
+func (c *Client) Register(ctx context.Context, subject string, schema Schema) (int, error) {
+	var resp struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", schema, &resp); err != nil {
+		return 0, fmt.Errorf("failed to register schema of %s: %w", subject, err)
+	}
+	return resp.ID, nil
+}
+
+// CheckCompatibility checks the schema against the latest version of subject.
+// A subject without versions accepts any schema.
+func (c *Client) CheckCompatibility(ctx context.Context, subject string, schema Schema) error {
+	var resp struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+	err := c.do(ctx, http.MethodPost, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest?verbose=true", schema, &resp)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check compatibility of %s: %w", subject, err)
+	}
+	if !resp.IsCompatible {
+		return fmt.Errorf("%w with the latest version of %s: %s", ErrIncompatible, subject, strings.Join(resp.Messages, "; "))
+	}
+	return nil
+}
+
+// SchemaByID returns the schema with the ID, as found in the header of a record.
+func (c *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	c.mu.Lock()
+	schema, ok := c.byID[id]
+	c.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &schema); err != nil {
+		return Schema{}, fmt.Errorf("failed to get schema %d: %w", id, err)
+	}
+	c.mu.Lock()
+	c.byID[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		regErr := &Error{Status: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(regErr); err != nil {
+			regErr.Code, regErr.Message = resp.StatusCode, resp.Status
+		}
+		return regErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userV1 = `{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}`
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(NewStub())
+	t.Cleanup(server.Close)
+	return NewClient(server.URL)
+}
+
+func TestClient_RegistersAndReadsSchemas(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	schema := Schema{Type: "AVRO", Schema: userV1}
+
+	require.NoError(t, client.CheckCompatibility(ctx, "users-value", schema))
+	id, err := client.Register(ctx, "users-value", schema)
+	require.NoError(t, err)
+	again, err := client.Register(ctx, "users-value", schema)
+	require.NoError(t, err)
+	got, err := client.SchemaByID(ctx, id)
+
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+	assert.Equal(t, schema, got)
+}
+
+func TestClient_RejectsIncompatibleSchema(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	_, err := client.Register(ctx, "users-value", Schema{Type: "AVRO", Schema: userV1})
+	require.NoError(t, err)
+	withDefault := Schema{Type: "AVRO", Schema: `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"email","type":"string","default":""}]}`}
+	required := Schema{Type: "AVRO", Schema: `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"email","type":"string"}]}`}
+
+	assert.NoError(t, client.CheckCompatibility(ctx, "users-value", withDefault))
+	assert.ErrorIs(t, client.CheckCompatibility(ctx, "users-value", required), ErrIncompatible)
+	_, err = client.Register(ctx, "users-value", required)
+	assert.ErrorIs(t, err, ErrIncompatible)
+}
+
+func TestClient_ErrorKinds(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.SchemaByID(context.Background(), 42)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewClient("http://127.0.0.1:1").SchemaByID(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestWireFormat(t *testing.T) {
+	value := AppendHeader(nil, 7)
+	value = AppendMessageIndexes(value, 0)
+	value = append(value, "payload"...)
+
+	id, payload, err := ReadHeader(value)
+	require.NoError(t, err)
+	indexes, payload, err := ReadMessageIndexes(payload)
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, []int{0}, indexes)
+	assert.Equal(t, "payload", string(payload))
+
+	nested, rest, err := ReadMessageIndexes(AppendMessageIndexes(nil, 1, 2))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, nested)
+	assert.Empty(t, rest)
+
+	_, _, err = ReadHeader([]byte(`{"id":1}`))
+	assert.ErrorIs(t, err, ErrNotFramed)
+}
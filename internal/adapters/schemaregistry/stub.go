@@ -0,0 +1,133 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hamba/avro/v2"
+)
+
+// Stub is an in-process registry serving the part of the REST API the Client uses. Subjects are kept
+// in memory with the BACKWARD compatibility of the registry default: a new Avro schema must read the
+// data of the latest version. Other schema types are not parsed and always compatible.
+type Stub struct {
+	router chi.Router
+
+	mu       sync.Mutex
+	schemas  []Schema         // the ID of a schema is its index + 1
+	subjects map[string][]int // schema IDs by version
+}
+
+// NewStub creates an empty registry; serve it with httptest.NewServer.
+func NewStub() *Stub {
+	s := &Stub{subjects: make(map[string][]int)}
+	r := chi.NewRouter()
+	r.Post("/subjects/{subject}/versions", s.handleRegister)
+	r.Post("/compatibility/subjects/{subject}/versions/latest", s.handleCompatibility)
+	r.Get("/schemas/ids/{id}", s.handleSchema)
+	s.router = r
+	return s
+}
+
+func (s *Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Stub) handleRegister(w http.ResponseWriter, r *http.Request) {
+	subject := chi.URLParam(r, "subject")
+	schema, ok := readSchema(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.subjects[subject] {
+		if s.schemas[id-1] == schema {
+			writeJSON(w, http.StatusOK, map[string]int{"id": id})
+			return
+		}
+	}
+	if err := s.compatible(subject, schema); err != nil {
+		writeJSON(w, http.StatusConflict, Error{Code: http.StatusConflict, Message: err.Error()})
+		return
+	}
+	s.schemas = append(s.schemas, schema)
+	id := len(s.schemas)
+	s.subjects[subject] = append(s.subjects[subject], id)
+	writeJSON(w, http.StatusOK, map[string]int{"id": id})
+}
+
+func (s *Stub) handleCompatibility(w http.ResponseWriter, r *http.Request) {
+	subject := chi.URLParam(r, "subject")
+	schema, ok := readSchema(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subjects[subject]) == 0 {
+		writeJSON(w, http.StatusNotFound, Error{Code: 40401, Message: "Subject '" + subject + "' not found."})
+		return
+	}
+	resp := map[string]any{"is_compatible": true}
+	if err := s.compatible(subject, schema); err != nil {
+		resp = map[string]any{"is_compatible": false, "messages": []string{err.Error()}}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Stub) handleSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil || id < 1 || id > len(s.schemas) {
+		writeJSON(w, http.StatusNotFound, Error{Code: 40403, Message: "Schema not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.schemas[id-1])
+}
+
+// compatible checks the schema against the latest version of the subject; the caller holds mu.
+func (s *Stub) compatible(subject string, schema Schema) error {
+	versions := s.subjects[subject]
+	if len(versions) == 0 {
+		return nil
+	}
+	latest := s.schemas[versions[len(versions)-1]-1]
+	if latest.TypeName() != schema.TypeName() {
+		return fmt.Errorf("schema type %s differs from the latest %s", schema.TypeName(), latest.TypeName())
+	}
+	if schema.TypeName() != "AVRO" {
+		return nil
+	}
+	reader, err := avro.Parse(schema.Schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	writer, err := avro.Parse(latest.Schema)
+	if err != nil {
+		return fmt.Errorf("invalid latest schema: %w", err)
+	}
+	return avro.NewSchemaCompatibility().Compatible(reader, writer)
+}
+
+func readSchema(w http.ResponseWriter, r *http.Request) (Schema, bool) {
+	var schema Schema
+	if err := json.NewDecoder(r.Body).Decode(&schema); err != nil || schema.Schema == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, Error{Code: 42201, Message: "Invalid schema"})
+		return Schema{}, false
+	}
+	return schema, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// magicByte starts every record of the wire format, followed by the big-endian 4-byte schema ID.
+const magicByte = 0
+
+// ErrNotFramed means the record does not start with the wire format header.
+var ErrNotFramed = errors.New("record is not in the schema registry wire format")
+
+// Framed tells whether the value starts with the wire format header.
+func Framed(value []byte) bool {
+	return len(value) >= 5 && value[0] == magicByte
+}
+
+// AppendHeader appends the wire format header of the schema.
+func AppendHeader(dst []byte, id int) []byte {
+	dst = append(dst, magicByte)
+	return binary.BigEndian.AppendUint32(dst, uint32(id))
+}
+
+// ReadHeader splits a record into its schema ID and payload.
+func ReadHeader(value []byte) (int, []byte, error) {
+	if !Framed(value) {
+		return 0, nil, ErrNotFramed
+	}
+	return int(binary.BigEndian.Uint32(value[1:5])), value[5:], nil
+}
+
+// AppendMessageIndexes appends the path of a Protobuf message in its schema: the count, then the
+// index at each nesting level, as zig-zag varints. The first message, [0], is written as a single 0.
+func AppendMessageIndexes(dst []byte, indexes ...int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return append(dst, 0)
+	}
+	dst = binary.AppendVarint(dst, int64(len(indexes)))
+	for _, i := range indexes {
+		dst = binary.AppendVarint(dst, int64(i))
+	}
+	return dst
+}
+
+// ReadMessageIndexes reads the path of a Protobuf message and returns the message payload.
+func ReadMessageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 || count > int64(len(payload)) {
+		return nil, nil, fmt.Errorf("invalid protobuf message indexes")
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int{0}, payload, nil
+	}
+	indexes := make([]int, 0, count)
+	for range count {
+		i, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("invalid protobuf message indexes")
+		}
+		indexes = append(indexes, int(i))
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
@@ -0,0 +1,37 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"payment-processing-system/internal/core/domain"
+)
+
+// LabelSink is an implementation of the FraudLabelSink port for ClickHouse.
+type LabelSink struct {
+	conn clickhouse.Conn
+}
+
+// NewLabelSink creates a sink writing to default.fraud_labels.
+func NewLabelSink(conn clickhouse.Conn) *LabelSink {
+	return &LabelSink{conn: conn}
+}
+
+// SaveFraudLabels implements the FraudLabelSink interface method.
+func (s *LabelSink) SaveFraudLabels(ctx context.Context, labels []domain.FraudLabel) error {
+	batch, err := s.conn.PrepareBatch(ctx, `INSERT INTO default.fraud_labels (transaction_id, is_fraud, source, labeled_by, note, labeled_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare fraud label batch: %w", err)
+	}
+	for _, l := range labels {
+		if err := batch.Append(l.TransactionID, l.IsFraud, l.Source, l.LabeledBy, l.Note, l.LabeledAt); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to append fraud label: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to save fraud labels: %w", err)
+	}
+	return nil
+}
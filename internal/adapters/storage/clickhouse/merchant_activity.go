@@ -0,0 +1,83 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"payment-processing-system/internal/core/domain"
+)
+
+// MerchantActivitySource aggregates default.transactions with the verdicts from default.fraud_reports,
+// adding the attempts declined before they were published, which only have a fraud report.
+// It implements the MerchantActivitySource port.
+type MerchantActivitySource struct {
+	conn clickhouse.Conn
+}
+
+// NewMerchantActivitySource creates a source over an existing connection.
+func NewMerchantActivitySource(conn clickhouse.Conn) *MerchantActivitySource {
+	return &MerchantActivitySource{conn: conn}
+}
+
+// MerchantActivity implements the MerchantActivitySource interface method.
+// A transaction counts as declined if its verdict is DECLINE; transactions without a merchant are left out.
+func (s *MerchantActivitySource) MerchantActivity(ctx context.Context, from, to time.Time, bucket time.Duration) ([]domain.MerchantActivity, error) {
+	decline := string(domain.DecisionDecline)
+	// A published transaction is checked shortly after it is created; the history it is looked up in
+	// starts a little earlier, so one checked just after from is not taken for a declined attempt.
+	historyFrom := from.Add(-time.Hour)
+	rows, err := s.conn.Query(ctx, `
+		SELECT
+			merchant_id,
+			toStartOfInterval(at, INTERVAL ? SECOND) AS bucket,
+			country,
+			count(),
+			sum(amount),
+			countIf(decision = ?)
+		FROM (
+			SELECT t.merchant_id AS merchant_id, t.created_at AS at,
+				if(t.bin_country != '', t.bin_country, t.ip_country) AS country, t.amount AS amount, r.decision AS decision
+			FROM default.transactions AS t FINAL
+			LEFT JOIN (
+				SELECT transaction_id, decision
+				FROM default.fraud_reports FINAL
+				WHERE processed_at >= ?
+			) AS r ON r.transaction_id = t.transaction_id
+			WHERE t.created_at >= ? AND t.created_at < ? AND t.merchant_id != ''
+
+			UNION ALL
+
+			-- Declined by the gateway before publication: only its fraud report is stored.
+			SELECT merchant_id, toDateTime64(processed_at, 3) AS at, toString(country) AS country, amount, decision
+			FROM default.fraud_reports FINAL
+			WHERE processed_at >= ? AND processed_at < ? AND merchant_id != '' AND decision = ?
+				AND transaction_id NOT IN (SELECT transaction_id FROM default.transactions WHERE created_at >= ?)
+		)
+		GROUP BY merchant_id, bucket, country
+		ORDER BY bucket, merchant_id, country`,
+		int64(bucket/time.Second), decline, from, from, to, from, to, decline, historyFrom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query merchant activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []domain.MerchantActivity
+	for rows.Next() {
+		var (
+			a                      domain.MerchantActivity
+			transactions, declines uint64
+		)
+		if err := rows.Scan(&a.MerchantID, &a.BucketStart, &a.Country, &transactions, &a.Amount, &declines); err != nil {
+			return nil, fmt.Errorf("failed to scan merchant activity: %w", err)
+		}
+		a.Transactions = int(transactions)
+		a.Declines = int(declines)
+		activity = append(activity, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read merchant activity: %w", err)
+	}
+	return activity, nil
+}
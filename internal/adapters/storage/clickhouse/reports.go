@@ -0,0 +1,104 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
+	"payment-processing-system/internal/core/domain"
+)
+
+// ReportStore keeps the fraud check results in default.fraud_reports, a ReplacingMergeTree with a row per transaction:
+// a later check of the same transaction replaces the earlier one. It implements the FraudReportRepository port.
+type ReportStore struct {
+	conn clickhouse.Conn
+}
+
+// NewReportStore creates a store over an existing connection.
+func NewReportStore(conn clickhouse.Conn) *ReportStore {
+	return &ReportStore{conn: conn}
+}
+
+// SaveFraudReports appends the reports. The explanation is stored as JSON; a result without one
+// gets the explanation built from the result itself. The check time is the version of the row.
+func (s *ReportStore) SaveFraudReports(ctx context.Context, reports []domain.FraudReport, dedupToken string) error {
+	if dedupToken != "" {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"insert_deduplication_token": dedupToken}))
+	}
+	batch, err := s.conn.PrepareBatch(ctx, `INSERT INTO default.fraud_reports (transaction_id, is_fraudulent, reason, card_hash, merchant_id, country, amount, processed_at, risk_score, decision, triggered_rules, engine_version, engine_contributions, explanation, version)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare fraud report batch: %w", err)
+	}
+	for _, r := range reports {
+		contributions := []byte("[]")
+		if r.Result.Contributions != nil {
+			if contributions, err = json.Marshal(r.Result.Contributions); err != nil {
+				_ = batch.Abort()
+				return fmt.Errorf("failed to marshal engine contributions: %w", err)
+			}
+		}
+		explanation, err := json.Marshal(r.Result.Explain())
+		if err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to marshal fraud explanation: %w", err)
+		}
+		triggered := r.Result.TriggeredRules
+		if triggered == nil {
+			triggered = []string{}
+		}
+		if err := batch.Append(r.TransactionID, r.Result.IsFraudulent, r.Result.Reason, r.CardHash, r.MerchantID, r.Country, r.Amount, r.ProcessedAt,
+			r.Result.RiskScore, string(r.Result.Decision), triggered, r.Result.EngineVersion, string(contributions), string(explanation), uint64(r.ProcessedAt.UnixNano())); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to append fraud report: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to save fraud reports: %w", err)
+	}
+	return nil
+}
+
+// GetFraudReport implements the FraudReportRepository interface method.
+// A transaction checked more than once, e.g. after a redelivery, returns its latest report even before the parts are merged.
+func (s *ReportStore) GetFraudReport(ctx context.Context, transactionID uuid.UUID) (domain.FraudReport, error) {
+	row := s.conn.QueryRow(ctx, `
+		SELECT transaction_id, processed_at, card_hash, amount, is_fraudulent, reason, risk_score, decision,
+			triggered_rules, engine_version, engine_contributions, explanation
+		FROM default.fraud_reports FINAL
+		WHERE transaction_id = ?`, transactionID)
+
+	var (
+		r                          domain.FraudReport
+		decision                   string
+		contributions, explanation string
+	)
+	err := row.Scan(&r.TransactionID, &r.ProcessedAt, &r.CardHash, &r.Amount, &r.Result.IsFraudulent, &r.Result.Reason,
+		&r.Result.RiskScore, &decision, &r.Result.TriggeredRules, &r.Result.EngineVersion, &contributions, &explanation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.FraudReport{}, domain.ErrFraudReportNotFound
+	}
+	if err != nil {
+		return domain.FraudReport{}, fmt.Errorf("failed to get fraud report: %w", err)
+	}
+	r.Result.Decision = domain.FraudDecision(decision)
+	if contributions != "" && contributions != "[]" {
+		if err := json.Unmarshal([]byte(contributions), &r.Result.Contributions); err != nil {
+			return domain.FraudReport{}, fmt.Errorf("invalid engine contributions: %w", err)
+		}
+	}
+	// Reports written before explanations were stored get one built from the result.
+	if explanation != "" {
+		r.Result.Explanation = &domain.FraudExplanation{}
+		if err := json.Unmarshal([]byte(explanation), r.Result.Explanation); err != nil {
+			return domain.FraudReport{}, fmt.Errorf("invalid fraud explanation: %w", err)
+		}
+	} else {
+		e := r.Result.Explain()
+		r.Result.Explanation = &e
+	}
+	return r, nil
+}
@@ -0,0 +1,37 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"payment-processing-system/internal/core/domain"
+)
+
+// SnapshotStore writes feature snapshots to default.fraud_feature_snapshots.
+type SnapshotStore struct {
+	conn clickhouse.Conn
+}
+
+// NewSnapshotStore creates a store over an existing connection.
+func NewSnapshotStore(conn clickhouse.Conn) *SnapshotStore {
+	return &SnapshotStore{conn: conn}
+}
+
+// SaveFeatureSnapshots stores the snapshots. A redelivered transaction keeps its latest snapshot.
+func (s *SnapshotStore) SaveFeatureSnapshots(ctx context.Context, snapshots []domain.FeatureSnapshot) error {
+	batch, err := s.conn.PrepareBatch(ctx, `INSERT INTO default.fraud_feature_snapshots (transaction_id, decided_at, engine_version, features)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare feature snapshot batch: %w", err)
+	}
+	for _, sn := range snapshots {
+		if err := batch.Append(sn.TransactionID, sn.DecidedAt, sn.EngineVersion, map[string]float64(sn.Features)); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to append feature snapshot: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to save feature snapshots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"payment-processing-system/internal/core/domain"
+)
+
+// scanBatchSize is the number of transactions handed to the callback of ScanTransactions at once.
+const scanBatchSize = 1000
+
+// TransactionStore keeps the transaction history in default.transactions.
+// It implements the TransactionHistory port.
+type TransactionStore struct {
+	conn clickhouse.Conn
+}
+
+// NewTransactionStore creates a store over an existing connection.
+func NewTransactionStore(conn clickhouse.Conn) *TransactionStore {
+	return &TransactionStore{conn: conn}
+}
+
+// SaveTransactions appends transactions to the history. Redelivered transactions are merged by the table engine.
+func (s *TransactionStore) SaveTransactions(ctx context.Context, txs []domain.Transaction) error {
+	batch, err := s.conn.PrepareBatch(ctx, `INSERT INTO default.transactions (transaction_id, created_at, amount, currency, card_hash, merchant_id, customer_id, bin_country, ip, ip_country, device_id, email_hash)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare transaction batch: %w", err)
+	}
+	for _, tx := range txs {
+		if err := batch.Append(tx.ID, tx.CreatedAt, tx.Amount, tx.Currency, tx.CardNumberHash, tx.MerchantID, tx.CustomerID,
+			tx.BINCountry, tx.IP, tx.IPCountry, tx.DeviceID, tx.EmailHash); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("failed to append transaction: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to save transactions: %w", err)
+	}
+	return nil
+}
+
+// ScanTransactions implements the TransactionHistory interface method.
+func (s *TransactionStore) ScanTransactions(ctx context.Context, since time.Time, fn func(batch []domain.Transaction) error) error {
+	rows, err := s.conn.Query(ctx, `
+		SELECT transaction_id, created_at, amount, currency, card_hash, merchant_id, customer_id, bin_country, ip, ip_country, device_id, email_hash
+		FROM default.transactions FINAL
+		WHERE created_at >= ?
+		ORDER BY created_at, transaction_id`, since)
+	if err != nil {
+		return fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]domain.Transaction, 0, scanBatchSize)
+	for rows.Next() {
+		var tx domain.Transaction
+		if err := rows.Scan(&tx.ID, &tx.CreatedAt, &tx.Amount, &tx.Currency, &tx.CardNumberHash, &tx.MerchantID, &tx.CustomerID,
+			&tx.BINCountry, &tx.IP, &tx.IPCountry, &tx.DeviceID, &tx.EmailHash); err != nil {
+			return fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		batch = append(batch, tx)
+		if len(batch) == scanBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read transactions: %w", err)
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
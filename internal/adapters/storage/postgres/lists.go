@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"payment-processing-system/internal/core/domain"
+)
+
+const listEntryColumns = `id, list_type, kind, value, reason, created_by, created_at, expires_at`
+
+// SaveListEntries implements the ListRepository interface method.
+func (r *Repository) SaveListEntries(ctx context.Context, entries []domain.ListEntry) ([]domain.ListEntry, error) {
+	const sql = `
+		INSERT INTO fraud_list_entries
+		    (id, list_type, kind, value, reason, created_by, created_at, expires_at)
+		VALUES
+		    ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (list_type, kind, value) DO UPDATE SET
+		    reason     = EXCLUDED.reason,
+		    expires_at = EXCLUDED.expires_at
+		RETURNING id, created_by, created_at
+	`
+	saved := make([]domain.ListEntry, len(entries))
+	copy(saved, entries)
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for i := range saved {
+			e := &saved[i]
+			batch.Queue(sql, e.ID, e.List, e.Kind, e.Value, e.Reason, e.CreatedBy, e.CreatedAt, e.ExpiresAt).
+				QueryRow(func(row pgx.Row) error {
+					return row.Scan(&e.ID, &e.CreatedBy, &e.CreatedAt)
+				})
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save list entries: %w", err)
+	}
+	return saved, nil
+}
+
+// GetListEntry implements the ListRepository interface method.
+func (r *Repository) GetListEntry(ctx context.Context, id uuid.UUID) (domain.ListEntry, error) {
+	sql := `SELECT ` + listEntryColumns + ` FROM fraud_list_entries WHERE id = $1`
+	e, err := scanListEntry(r.pool.QueryRow(ctx, sql, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ListEntry{}, domain.ErrListEntryNotFound
+	}
+	if err != nil {
+		return domain.ListEntry{}, fmt.Errorf("failed to get list entry: %w", err)
+	}
+	return e, nil
+}
+
+// UpdateListEntry implements the ListRepository interface method.
+func (r *Repository) UpdateListEntry(ctx context.Context, e domain.ListEntry) error {
+	const sql = `
+		UPDATE fraud_list_entries
+		SET reason = $2, expires_at = $3
+		WHERE id = $1
+	`
+	tag, err := r.pool.Exec(ctx, sql, e.ID, e.Reason, e.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to update list entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrListEntryNotFound
+	}
+	return nil
+}
+
+// DeleteListEntry implements the ListRepository interface method.
+func (r *Repository) DeleteListEntry(ctx context.Context, id uuid.UUID) (domain.ListEntry, error) {
+	sql := `DELETE FROM fraud_list_entries WHERE id = $1 RETURNING ` + listEntryColumns
+	e, err := scanListEntry(r.pool.QueryRow(ctx, sql, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ListEntry{}, domain.ErrListEntryNotFound
+	}
+	if err != nil {
+		return domain.ListEntry{}, fmt.Errorf("failed to delete list entry: %w", err)
+	}
+	return e, nil
+}
+
+// ListListEntries implements the ListRepository interface method.
+func (r *Repository) ListListEntries(ctx context.Context, list domain.ListType, kind domain.ListKind, limit int) ([]domain.ListEntry, error) {
+	sql := `SELECT ` + listEntryColumns + `
+		FROM fraud_list_entries
+		WHERE ($1::text = '' OR list_type = $1) AND ($2::text = '' OR kind = $2)
+		ORDER BY created_at DESC
+		LIMIT $3`
+	return r.queryListEntries(ctx, sql, string(list), string(kind), limit)
+}
+
+// AllListEntries implements the ListRepository interface method.
+func (r *Repository) AllListEntries(ctx context.Context, now time.Time) ([]domain.ListEntry, error) {
+	sql := `SELECT ` + listEntryColumns + `
+		FROM fraud_list_entries
+		WHERE expires_at IS NULL OR expires_at > $1`
+	return r.queryListEntries(ctx, sql, now)
+}
+
+func (r *Repository) queryListEntries(ctx context.Context, sql string, args ...any) ([]domain.ListEntry, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list list entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.ListEntry
+	for rows.Next() {
+		e, err := scanListEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan list entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func scanListEntry(row pgx.Row) (domain.ListEntry, error) {
+	var e domain.ListEntry
+	err := row.Scan(&e.ID, &e.List, &e.Kind, &e.Value, &e.Reason, &e.CreatedBy, &e.CreatedAt, &e.ExpiresAt)
+	return e, err
+}
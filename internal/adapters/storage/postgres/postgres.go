@@ -36,21 +36,26 @@ func (r *Repository) Close() {
 }
 
 // Save implements the TransactionRepository interface method.
+// A transaction whose idempotency key is already stored is not inserted and ErrIdempotencyKeyUsed is returned.
 func (r *Repository) Save(ctx context.Context, tx domain.Transaction) error {
 	const sql = `
 		INSERT INTO transactions 
-		    (id, status, amount, currency, card_number_hash, idempotency_key, created_at, updated_at) 
+		    (id, status, amount, currency, card_number_hash, merchant_id, customer_id, idempotency_key, is_fraudulent, fraud_reason, created_at, updated_at) 
 		VALUES 
-		    ($1, $2, $3, $4, $5, $6, $7, $7)
+		    ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, NULLIF($10, ''), $11, $12)
 		ON CONFLICT (idempotency_key) DO NOTHING
 	`
-	_, err := r.pool.Exec(ctx, sql,
+	tag, err := r.pool.Exec(ctx, sql,
 		tx.ID,
 		tx.Status,
 		tx.Amount,
 		tx.Currency,
 		tx.CardNumberHash,
+		tx.MerchantID,
+		tx.CustomerID,
 		tx.IdempotencyKey,
+		tx.IsFraudulent,
+		tx.DeclineReason,
 		tx.CreatedAt,
 		tx.CreatedAt, //TODO: updated_at = created_at для новой записи
 	)
@@ -60,6 +65,9 @@ func (r *Repository) Save(ctx context.Context, tx domain.Transaction) error {
 		// например, на нарушение unique constraint по idempotency_key.
 		return fmt.Errorf("failed to save transaction: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrIdempotencyKeyUsed
+	}
 
 	return nil
 }
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"payment-processing-system/internal/core/domain"
+)
+
+// SaveReserveConfig creates or replaces the reserve configuration of a merchant.
+// The effective_from of an existing configuration is kept.
+func (r *Repository) SaveReserveConfig(ctx context.Context, cfg domain.ReserveConfig) error {
+	const sql = `
+		INSERT INTO merchant_reserve_configs
+		    (merchant_id, rolling_percent, hold_days, minimum_reserve, currency, effective_from, updated_at)
+		VALUES
+		    ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (merchant_id) DO UPDATE SET
+		    rolling_percent = EXCLUDED.rolling_percent,
+		    hold_days       = EXCLUDED.hold_days,
+		    minimum_reserve = EXCLUDED.minimum_reserve,
+		    currency        = EXCLUDED.currency,
+		    updated_at      = EXCLUDED.updated_at
+	`
+	_, err := r.pool.Exec(ctx, sql,
+		cfg.MerchantID,
+		cfg.RollingPercent,
+		cfg.HoldDays,
+		cfg.MinimumReserve,
+		cfg.Currency,
+		cfg.EffectiveFrom,
+		cfg.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save reserve config: %w", err)
+	}
+	return nil
+}
+
+// GetReserveConfig returns domain.ErrReserveConfigNotFound if the merchant has no reserve.
+func (r *Repository) GetReserveConfig(ctx context.Context, merchantID string) (domain.ReserveConfig, error) {
+	const sql = `
+		SELECT merchant_id, rolling_percent, hold_days, minimum_reserve, currency, effective_from, updated_at
+		FROM merchant_reserve_configs
+		WHERE merchant_id = $1
+	`
+	cfg, err := scanReserveConfig(r.pool.QueryRow(ctx, sql, merchantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ReserveConfig{}, domain.ErrReserveConfigNotFound
+	}
+	if err != nil {
+		return domain.ReserveConfig{}, fmt.Errorf("failed to get reserve config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ListReserveConfigs returns the configuration of every merchant with a reserve.
+func (r *Repository) ListReserveConfigs(ctx context.Context) ([]domain.ReserveConfig, error) {
+	const sql = `
+		SELECT merchant_id, rolling_percent, hold_days, minimum_reserve, currency, effective_from, updated_at
+		FROM merchant_reserve_configs
+		ORDER BY merchant_id
+	`
+	rows, err := r.pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserve configs: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []domain.ReserveConfig
+	for rows.Next() {
+		cfg, err := scanReserveConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reserve config: %w", err)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, rows.Err()
+}
+
+// ListUnreservedTransactions implements the ReserveRepository interface method.
+func (r *Repository) ListUnreservedTransactions(ctx context.Context, merchantID, currency string, since time.Time) ([]domain.Transaction, error) {
+	const sql = `
+		SELECT t.id, t.status, t.amount, t.currency, t.card_number_hash, t.created_at
+		FROM transactions t
+		LEFT JOIN reserve_entries e ON e.transaction_id = t.id
+		WHERE t.merchant_id = $1
+		  AND t.currency = $2
+		  AND t.status = ANY($3)
+		  AND t.created_at >= $4
+		  AND e.id IS NULL
+		ORDER BY t.created_at
+	`
+	statuses := make([]string, len(domain.ReserveHeldStatuses))
+	for i, s := range domain.ReserveHeldStatuses {
+		statuses[i] = string(s)
+	}
+	rows, err := r.pool.Query(ctx, sql, merchantID, currency, statuses, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unreserved transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []domain.Transaction
+	for rows.Next() {
+		tx := domain.Transaction{MerchantID: merchantID}
+		if err := rows.Scan(&tx.ID, &tx.Status, &tx.Amount, &tx.Currency, &tx.CardNumberHash, &tx.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+// SaveReserveEntries inserts all entries in one batch.
+func (r *Repository) SaveReserveEntries(ctx context.Context, entries []domain.ReserveEntry) error {
+	const sql = `
+		INSERT INTO reserve_entries
+		    (id, merchant_id, transaction_id, amount, currency, held_at, release_at)
+		VALUES
+		    ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		batch.Queue(sql, e.ID, e.MerchantID, e.TransactionID, e.Amount, e.Currency, e.HeldAt, e.ReleaseAt)
+	}
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save reserve entries: %w", err)
+	}
+	return nil
+}
+
+// ListHeldEntries implements the ReserveRepository interface method.
+func (r *Repository) ListHeldEntries(ctx context.Context, merchantID string) ([]domain.ReserveEntry, error) {
+	const sql = `
+		SELECT id, merchant_id, transaction_id, amount, currency, held_at, release_at
+		FROM reserve_entries
+		WHERE merchant_id = $1 AND released_at IS NULL
+		ORDER BY release_at, held_at
+	`
+	rows, err := r.pool.Query(ctx, sql, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserve entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.ReserveEntry
+	for rows.Next() {
+		var e domain.ReserveEntry
+		if err := rows.Scan(&e.ID, &e.MerchantID, &e.TransactionID, &e.Amount, &e.Currency, &e.HeldAt, &e.ReleaseAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reserve entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// ReleaseReserveEntries marks the entries as released. Already released entries are left untouched.
+func (r *Repository) ReleaseReserveEntries(ctx context.Context, ids []uuid.UUID, releasedAt time.Time) error {
+	const sql = `
+		UPDATE reserve_entries
+		SET released_at = $2
+		WHERE id = ANY($1) AND released_at IS NULL
+	`
+	if _, err := r.pool.Exec(ctx, sql, ids, releasedAt); err != nil {
+		return fmt.Errorf("failed to release reserve entries: %w", err)
+	}
+	return nil
+}
+
+func scanReserveConfig(row pgx.Row) (domain.ReserveConfig, error) {
+	var cfg domain.ReserveConfig
+	err := row.Scan(&cfg.MerchantID, &cfg.RollingPercent, &cfg.HoldDays, &cfg.MinimumReserve, &cfg.Currency, &cfg.EffectiveFrom, &cfg.UpdatedAt)
+	return cfg, err
+}
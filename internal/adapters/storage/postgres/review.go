@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"payment-processing-system/internal/core/domain"
+)
+
+const reviewCaseColumns = `
+	id, transaction_id, status, risk_score, reason, triggered_rules,
+	COALESCE(assigned_to, ''), COALESCE(decided_by, ''), auto_decided, note,
+	created_at, due_at, claimed_at, decided_at
+`
+
+var undecidedReviewStatuses = []string{string(domain.ReviewOpen), string(domain.ReviewClaimed)}
+
+// rejectedReviewReason is stored as the fraud reason of transactions rejected by a reviewer.
+const rejectedReviewReason = "rejected in manual review"
+
+// OpenReviewCase implements the ReviewRepository interface method.
+func (r *Repository) OpenReviewCase(ctx context.Context, c domain.ReviewCase) (bool, error) {
+	const insertSQL = `
+		INSERT INTO review_cases
+		    (id, transaction_id, status, risk_score, reason, triggered_rules, created_at, due_at)
+		VALUES
+		    ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`
+	const updateSQL = `
+		UPDATE transactions
+		SET status = $2, risk_score = $3
+		WHERE id = $1 AND status = $4
+	`
+	rules := c.TriggeredRules
+	if rules == nil {
+		rules = []string{}
+	}
+
+	opened := false
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, insertSQL, c.ID, c.TransactionID, c.Status, c.RiskScore, c.Reason, rules, c.CreatedAt, c.DueAt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		opened = true
+		_, err = tx.Exec(ctx, updateSQL, c.TransactionID, domain.StatusInReview, c.RiskScore, domain.StatusProcessing)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to open review case: %w", err)
+	}
+	return opened, nil
+}
+
+// GetReviewCase implements the ReviewRepository interface method.
+func (r *Repository) GetReviewCase(ctx context.Context, id uuid.UUID) (domain.ReviewCase, error) {
+	sql := `SELECT ` + reviewCaseColumns + ` FROM review_cases WHERE id = $1`
+	c, err := scanReviewCase(r.pool.QueryRow(ctx, sql, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ReviewCase{}, domain.ErrReviewCaseNotFound
+	}
+	if err != nil {
+		return domain.ReviewCase{}, fmt.Errorf("failed to get review case: %w", err)
+	}
+	return c, nil
+}
+
+// ListReviewCases implements the ReviewRepository interface method.
+func (r *Repository) ListReviewCases(ctx context.Context, status domain.ReviewStatus, limit int) ([]domain.ReviewCase, error) {
+	statuses := []string{string(status)}
+	if status == "" {
+		statuses = undecidedReviewStatuses
+	}
+	sql := `SELECT ` + reviewCaseColumns + `
+		FROM review_cases
+		WHERE status = ANY($1)
+		ORDER BY due_at, created_at
+		LIMIT $2`
+	return r.queryReviewCases(ctx, sql, statuses, limit)
+}
+
+// ClaimReviewCase implements the ReviewRepository interface method.
+func (r *Repository) ClaimReviewCase(ctx context.Context, expected domain.ReviewCase, analyst string, at time.Time) error {
+	const sql = `
+		UPDATE review_cases
+		SET status = $2, assigned_to = $3, claimed_at = $4
+		WHERE id = $1 AND status = $5 AND assigned_to IS NOT DISTINCT FROM NULLIF($6, '')
+	`
+	tag, err := r.pool.Exec(ctx, sql, expected.ID, domain.ReviewClaimed, analyst, at, expected.Status, expected.AssignedTo)
+	if err != nil {
+		return fmt.Errorf("failed to claim review case: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrReviewCaseConflict
+	}
+	return nil
+}
+
+// DecideReviewCase implements the ReviewRepository interface method.
+func (r *Repository) DecideReviewCase(ctx context.Context, expected domain.ReviewCase, d domain.ReviewDecision) error {
+	const caseSQL = `
+		UPDATE review_cases
+		SET status = $2, decided_by = $3, auto_decided = $4, note = $5, decided_at = $6
+		WHERE id = $1 AND status = $7 AND assigned_to IS NOT DISTINCT FROM NULLIF($8, '')
+	`
+	const txSQL = `
+		UPDATE transactions
+		SET status = $2, is_fraudulent = $3, fraud_reason = NULLIF($4, '')
+		WHERE id = $1 AND status = $5
+	`
+	newStatus, isFraud, reason := domain.StatusCompleted, false, ""
+	if !d.Approve {
+		newStatus, isFraud, reason = domain.StatusDeclined, true, rejectedReviewReason
+	}
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, caseSQL, expected.ID, d.Status(), d.DecidedBy, d.AutoDecided, d.Note, d.DecidedAt,
+			expected.Status, expected.AssignedTo)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrReviewCaseConflict
+		}
+		_, err = tx.Exec(ctx, txSQL, expected.TransactionID, newStatus, isFraud, reason, domain.StatusInReview)
+		return err
+	})
+	if errors.Is(err, domain.ErrReviewCaseConflict) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decide review case: %w", err)
+	}
+	return nil
+}
+
+// ListOverdueReviewCases implements the ReviewRepository interface method.
+func (r *Repository) ListOverdueReviewCases(ctx context.Context, now time.Time, limit int) ([]domain.ReviewCase, error) {
+	sql := `SELECT ` + reviewCaseColumns + `
+		FROM review_cases
+		WHERE status = ANY($1) AND due_at < $3
+		ORDER BY due_at
+		LIMIT $2`
+	return r.queryReviewCases(ctx, sql, undecidedReviewStatuses, limit, now)
+}
+
+// ListUnexportedReviewCases implements the ReviewRepository interface method.
+func (r *Repository) ListUnexportedReviewCases(ctx context.Context, limit int) ([]domain.ReviewCase, error) {
+	sql := `SELECT ` + reviewCaseColumns + `
+		FROM review_cases
+		WHERE status = ANY($1) AND decided_at IS NOT NULL AND label_exported_at IS NULL
+		ORDER BY decided_at
+		LIMIT $2`
+	return r.queryReviewCases(ctx, sql, []string{string(domain.ReviewApproved), string(domain.ReviewRejected)}, limit)
+}
+
+// MarkReviewLabelsExported implements the ReviewRepository interface method.
+func (r *Repository) MarkReviewLabelsExported(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	const sql = `
+		UPDATE review_cases
+		SET label_exported_at = $2
+		WHERE id = ANY($1) AND label_exported_at IS NULL
+	`
+	if _, err := r.pool.Exec(ctx, sql, ids, at); err != nil {
+		return fmt.Errorf("failed to mark review labels exported: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) queryReviewCases(ctx context.Context, sql string, args ...any) ([]domain.ReviewCase, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review cases: %w", err)
+	}
+	defer rows.Close()
+
+	var cases []domain.ReviewCase
+	for rows.Next() {
+		c, err := scanReviewCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review case: %w", err)
+		}
+		cases = append(cases, c)
+	}
+	return cases, rows.Err()
+}
+
+func scanReviewCase(row pgx.Row) (domain.ReviewCase, error) {
+	var c domain.ReviewCase
+	err := row.Scan(&c.ID, &c.TransactionID, &c.Status, &c.RiskScore, &c.Reason, &c.TriggeredRules,
+		&c.AssignedTo, &c.DecidedBy, &c.AutoDecided, &c.Note,
+		&c.CreatedAt, &c.DueAt, &c.ClaimedAt, &c.DecidedAt)
+	return c, err
+}
@@ -0,0 +1,295 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
+)
+
+// featureDedupTTL is how long an accounted transaction is remembered to skip redeliveries.
+const featureDedupTTL = 24 * time.Hour
+
+// Feature state layouts; one key "<prefix>:<feature>:<entity value>" per feature and entity.
+const (
+	featureOpTotals   = "totals"   // lifetime count and sum: hash {n, sum}
+	featureOpEvents   = "events"   // windowed count and sum: sorted set of "<tx id>|<amount>" by time
+	featureOpHLL      = "hll"      // lifetime distinct: HyperLogLog of field values
+	featureOpDistinct = "distinct" // windowed distinct: sorted set of field values by last time seen
+	featureOpFirst    = "first"    // first seen: unix milliseconds
+)
+
+// updateFeaturesScript accounts one transaction.
+//
+// KEYS[1]: deduplication key; KEYS[1 + i]: state key of feature i.
+// ARGV[1]: "1" to deduplicate; ARGV[2]: event time as unix milliseconds; ARGV[3]: amount; ARGV[4]: "<tx id>|<amount>";
+// ARGV[5]: deduplication TTL and ARGV[6]: lifetime state TTL, both in milliseconds;
+// ARGV[4 + 3i .. 6 + 3i]: operation, window in milliseconds and field value of feature i.
+// Returns 0 when the transaction was already accounted.
+var updateFeaturesScript = redis.NewScript(`
+if ARGV[1] == '1' and not redis.call('SET', KEYS[1], '1', 'NX', 'PX', ARGV[5]) then
+  return 0
+end
+local now = tonumber(ARGV[2])
+local retention = tonumber(ARGV[6])
+for i = 2, #KEYS do
+  local key = KEYS[i]
+  local base = 4 + 3 * (i - 1)
+  local op, w, value = ARGV[base], tonumber(ARGV[base + 1]), ARGV[base + 2]
+  if op == 'totals' then
+    redis.call('HINCRBY', key, 'n', 1)
+    redis.call('HINCRBYFLOAT', key, 'sum', ARGV[3])
+    redis.call('PEXPIRE', key, retention)
+  elseif op == 'events' then
+    redis.call('ZADD', key, now, ARGV[4])
+    redis.call('ZREMRANGEBYSCORE', key, '-inf', '(' .. (now - w))
+    redis.call('PEXPIRE', key, w)
+  elseif op == 'hll' then
+    redis.call('PFADD', key, value)
+    redis.call('PEXPIRE', key, retention)
+  elseif op == 'distinct' then
+    local prev = redis.call('ZSCORE', key, value)
+    if not prev or tonumber(prev) < now then
+      redis.call('ZADD', key, now, value)
+    end
+    redis.call('ZREMRANGEBYSCORE', key, '-inf', '(' .. (now - w))
+    redis.call('PEXPIRE', key, w)
+  elseif op == 'first' then
+    local prev = redis.call('GET', key)
+    if not prev or tonumber(prev) > now then
+      redis.call('SET', key, ARGV[2])
+    end
+    redis.call('PEXPIRE', key, retention)
+  end
+end
+return 1
+`)
+
+// FeatureStoreAdapter is a Redis implementation of the FeatureStore port.
+// Windowed features keep every event of their window and expire with it; lifetime features expire
+// after retention without transactions of the entity.
+type FeatureStoreAdapter struct {
+	rdb       *redis.Client
+	prefix    string
+	retention time.Duration
+}
+
+// NewFeatureStoreAdapter creates the adapter over an existing client. Keys are namespaced with prefix.
+func NewFeatureStoreAdapter(rdb *redis.Client, prefix string, retention time.Duration) *FeatureStoreAdapter {
+	return &FeatureStoreAdapter{rdb: rdb, prefix: prefix, retention: retention}
+}
+
+// GetFeatures implements the FeatureStore interface method.
+func (a *FeatureStoreAdapter) GetFeatures(ctx context.Context, defs []domain.FeatureDefinition, tx domain.Transaction) (domain.FeatureValues, error) {
+	type read struct {
+		def    domain.FeatureDefinition
+		totals *redis.SliceCmd
+		events *redis.StringSliceCmd
+		count  *redis.IntCmd
+		first  *redis.StringCmd
+	}
+
+	now := tx.CreatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	nowMs := now.UnixMilli()
+
+	var reads []read
+	pipe := a.rdb.Pipeline()
+	for _, d := range defs {
+		entity := d.Entity.Value(tx)
+		if entity == "" {
+			continue
+		}
+		key := a.key(d, entity)
+		r := read{def: d}
+		switch featureOp(d) {
+		case featureOpTotals:
+			r.totals = pipe.HMGet(ctx, key, "n", "sum")
+		case featureOpEvents:
+			r.events = pipe.ZRangeByScore(ctx, key, windowRange(nowMs, d.Window))
+		case featureOpHLL:
+			r.count = pipe.PFCount(ctx, key)
+		case featureOpDistinct:
+			rng := windowRange(nowMs, d.Window)
+			r.count = pipe.ZCount(ctx, key, rng.Min, rng.Max)
+		case featureOpFirst:
+			r.first = pipe.Get(ctx, key)
+		}
+		reads = append(reads, r)
+	}
+	values := make(domain.FeatureValues, len(reads))
+	if len(reads) == 0 {
+		return values, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read features: %w", err)
+	}
+
+	for _, r := range reads {
+		var v float64
+		switch {
+		case r.totals != nil:
+			vals := r.totals.Val()
+			v = aggregate(r.def.Aggregate, parseFloat(vals[0]), parseFloat(vals[1]))
+		case r.events != nil:
+			var n, sum float64
+			for _, m := range r.events.Val() {
+				if i := strings.LastIndexByte(m, '|'); i >= 0 {
+					amount, _ := strconv.ParseFloat(m[i+1:], 64)
+					n, sum = n+1, sum+amount
+				}
+			}
+			v = aggregate(r.def.Aggregate, n, sum)
+		case r.count != nil:
+			v = float64(r.count.Val())
+		case r.first != nil:
+			if first, err := r.first.Int64(); err == nil && first < nowMs {
+				v = float64(nowMs-first) / 1000
+			}
+		}
+		values[r.def.Name] = v
+	}
+	return values, nil
+}
+
+// UpdateFeatures implements the FeatureStore interface method.
+func (a *FeatureStoreAdapter) UpdateFeatures(ctx context.Context, defs []domain.FeatureDefinition, txs []domain.Transaction) error {
+	return a.update(ctx, defs, txs, true)
+}
+
+// BackfillFeatures implements the FeatureStore interface method.
+func (a *FeatureStoreAdapter) BackfillFeatures(ctx context.Context, defs []domain.FeatureDefinition, txs []domain.Transaction) error {
+	return a.update(ctx, defs, txs, false)
+}
+
+// ResetFeatures implements the FeatureStore interface method.
+func (a *FeatureStoreAdapter) ResetFeatures(ctx context.Context, defs []domain.FeatureDefinition) error {
+	for _, d := range defs {
+		iter := a.rdb.Scan(ctx, 0, a.prefix+":"+d.Name+":*", 1000).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) == 1000 {
+				if err := a.rdb.Unlink(ctx, keys...).Err(); err != nil {
+					return fmt.Errorf("failed to reset feature %s: %w", d.Name, err)
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to reset feature %s: %w", d.Name, err)
+		}
+		if len(keys) > 0 {
+			if err := a.rdb.Unlink(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to reset feature %s: %w", d.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *FeatureStoreAdapter) update(ctx context.Context, defs []domain.FeatureDefinition, txs []domain.Transaction, dedup bool) error {
+	pipe := a.rdb.Pipeline()
+	queued := 0
+	for _, tx := range txs {
+		amount := strconv.FormatFloat(tx.Amount, 'f', -1, 64)
+		at := tx.CreatedAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		keys := []string{a.prefix + ":_applied:" + tx.ID.String()}
+		args := []interface{}{
+			boolArg(dedup), at.UnixMilli(), amount, tx.ID.String() + "|" + amount,
+			featureDedupTTL.Milliseconds(), a.retention.Milliseconds(),
+		}
+		for _, d := range defs {
+			entity := d.Entity.Value(tx)
+			var field string
+			if d.Aggregate == domain.FeatureDistinct {
+				field = d.Field.Value(tx)
+			}
+			if entity == "" || (d.Aggregate == domain.FeatureDistinct && field == "") {
+				continue
+			}
+			keys = append(keys, a.key(d, entity))
+			args = append(args, featureOp(d), d.Window.Milliseconds(), field)
+		}
+		if len(keys) == 1 {
+			continue
+		}
+		updateFeaturesScript.Eval(ctx, pipe, keys, args...)
+		queued++
+	}
+	if queued == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update features: %w", err)
+	}
+	return nil
+}
+
+func (a *FeatureStoreAdapter) key(d domain.FeatureDefinition, entity string) string {
+	return a.prefix + ":" + d.Name + ":" + entity
+}
+
+// featureOp returns the state layout of a definition.
+func featureOp(d domain.FeatureDefinition) string {
+	switch d.Aggregate {
+	case domain.FeatureDistinct:
+		if d.Window > 0 {
+			return featureOpDistinct
+		}
+		return featureOpHLL
+	case domain.FeatureFirstSeen:
+		return featureOpFirst
+	}
+	if d.Window > 0 {
+		return featureOpEvents
+	}
+	return featureOpTotals
+}
+
+// windowRange selects the events of the trailing window ending at nowMs.
+func windowRange(nowMs int64, window time.Duration) *redis.ZRangeBy {
+	return &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(nowMs-window.Milliseconds(), 10),
+		Max: strconv.FormatInt(nowMs, 10),
+	}
+}
+
+func aggregate(agg domain.FeatureAggregate, n, sum float64) float64 {
+	switch agg {
+	case domain.FeatureCount:
+		return n
+	case domain.FeatureSum:
+		return sum
+	case domain.FeatureAvg:
+		if n == 0 {
+			return 0
+		}
+		return sum / n
+	}
+	return 0
+}
+
+// parseFloat reads a hash field; a missing field is 0.
+func parseFloat(v interface{}) float64 {
+	s, _ := v.(string)
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func boolArg(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
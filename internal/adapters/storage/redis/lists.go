@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
+)
+
+// ListCacheAdapter is a Redis implementation of the ListCache and ListMatcher ports.
+//
+// Every list and kind is one hash "<prefix>:<list>:<kind>" of lookup value -> entry JSON,
+// see domain.ListEntry.LookupValue.
+// IP ranges live in a separate hash "<prefix>:<list>:ip_range", which is read as a whole
+// on every IP lookup: range lists are expected to stay small.
+type ListCacheAdapter struct {
+	rdb    *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+// NewListCacheAdapter creates the adapter over an existing client. Keys are namespaced with prefix.
+func NewListCacheAdapter(rdb *redis.Client, prefix string) *ListCacheAdapter {
+	return &ListCacheAdapter{rdb: rdb, prefix: prefix, now: time.Now}
+}
+
+// cachedListEntry is the JSON stored per value.
+type cachedListEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PutListEntries implements the ListCache interface method.
+func (a *ListCacheAdapter) PutListEntries(ctx context.Context, entries []domain.ListEntry) error {
+	_, err := a.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		return a.queuePut(ctx, pipe, entries)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cache list entries: %w", err)
+	}
+	return nil
+}
+
+// DeleteListEntry implements the ListCache interface method.
+func (a *ListCacheAdapter) DeleteListEntry(ctx context.Context, e domain.ListEntry) error {
+	if err := a.rdb.HDel(ctx, a.key(e), e.LookupValue()).Err(); err != nil {
+		return fmt.Errorf("failed to delete cached list entry: %w", err)
+	}
+	return nil
+}
+
+// ReplaceListEntries implements the ListCache interface method.
+// Readers see either the old or the new content, never an empty cache.
+func (a *ListCacheAdapter) ReplaceListEntries(ctx context.Context, entries []domain.ListEntry) error {
+	_, err := a.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, a.allKeys()...)
+		return a.queuePut(ctx, pipe, entries)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace cached list entries: %w", err)
+	}
+	return nil
+}
+
+// MatchLists implements the ListMatcher interface method.
+func (a *ListCacheAdapter) MatchLists(ctx context.Context, subjects []domain.ListSubject) ([]domain.ListEntry, error) {
+	type lookup struct {
+		list    domain.ListType
+		subject domain.ListSubject
+		exact   *redis.StringCmd
+		ranges  *redis.MapStringStringCmd
+	}
+
+	var lookups []lookup
+	pipe := a.rdb.Pipeline()
+	for _, list := range []domain.ListType{domain.ListBlock, domain.ListAllow} {
+		for _, s := range subjects {
+			if s.Value == "" {
+				continue
+			}
+			l := lookup{list: list, subject: s}
+			l.exact = pipe.HGet(ctx, a.key(domain.ListEntry{List: list, Kind: s.Kind}), s.Value)
+			if s.Kind == domain.ListIP {
+				l.ranges = pipe.HGetAll(ctx, a.rangeKey(list))
+			}
+			lookups = append(lookups, l)
+		}
+	}
+	if len(lookups) == 0 {
+		return nil, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to match lists: %w", err)
+	}
+
+	now := a.now()
+	var matches []domain.ListEntry
+	add := func(list domain.ListType, kind domain.ListKind, value, raw string) {
+		var c cachedListEntry
+		if err := json.Unmarshal([]byte(raw), &c); err != nil {
+			return
+		}
+		if c.Value != "" {
+			value = c.Value
+		}
+		e := domain.ListEntry{
+			ID: c.ID, List: list, Kind: kind, Value: value,
+			Reason: c.Reason, CreatedBy: c.CreatedBy, CreatedAt: c.CreatedAt, ExpiresAt: c.ExpiresAt,
+		}
+		if e.Active(now) {
+			matches = append(matches, e)
+		}
+	}
+	for _, l := range lookups {
+		if raw, err := l.exact.Result(); err == nil {
+			add(l.list, l.subject.Kind, l.subject.Value, raw)
+		}
+		if l.ranges == nil {
+			continue
+		}
+		for cidr, raw := range l.ranges.Val() {
+			if (domain.ListEntry{Kind: domain.ListIP, Value: cidr}).Matches(l.subject.Value) {
+				add(l.list, domain.ListIP, cidr, raw)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func (a *ListCacheAdapter) queuePut(ctx context.Context, pipe redis.Pipeliner, entries []domain.ListEntry) error {
+	for _, e := range entries {
+		data, err := json.Marshal(cachedListEntry{
+			ID:        e.ID,
+			Value:     e.Value,
+			Reason:    e.Reason,
+			CreatedBy: e.CreatedBy,
+			CreatedAt: e.CreatedAt,
+			ExpiresAt: e.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		pipe.HSet(ctx, a.key(e), e.LookupValue(), data)
+	}
+	return nil
+}
+
+// key returns the hash holding the entry.
+func (a *ListCacheAdapter) key(e domain.ListEntry) string {
+	if e.IsRange() {
+		return a.rangeKey(e.List)
+	}
+	return fmt.Sprintf("%s:%s:%s", a.prefix, e.List, e.Kind)
+}
+
+func (a *ListCacheAdapter) rangeKey(list domain.ListType) string {
+	return fmt.Sprintf("%s:%s:ip_range", a.prefix, list)
+}
+
+func (a *ListCacheAdapter) allKeys() []string {
+	var keys []string
+	for _, list := range []domain.ListType{domain.ListBlock, domain.ListAllow} {
+		for _, kind := range domain.ListKinds {
+			keys = append(keys, a.key(domain.ListEntry{List: list, Kind: kind}))
+		}
+		keys = append(keys, a.rangeKey(list))
+	}
+	return keys
+}
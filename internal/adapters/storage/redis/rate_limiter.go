@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
 )
 
 // RateLimiterAdapter is a Redis implementation of the RateLimiterRepository port.
 type RateLimiterAdapter struct {
-	rdb *redis.Client
+	rdb      *redis.Client
+	velocity *VelocityCounterAdapter
 }
 
 // NewRateLimiterAdapter creates and tests a new connection to Redis and returns the adapter.
@@ -23,26 +26,21 @@ func NewRateLimiterAdapter(addr string) (*RateLimiterAdapter, error) {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	return &RateLimiterAdapter{rdb: rdb}, nil
+	return &RateLimiterAdapter{
+		rdb:      rdb,
+		velocity: NewVelocityCounterAdapter(rdb, "rate_limit"),
+	}, nil
 }
 
-// IsAllowed implements the rate limiting logic using a fixed-window algorithm in Redis.
+// IsAllowed implements the rate limiting logic on the sliding-window velocity counter.
+// Only allowed requests are counted, so a client that keeps retrying gets through again once the window moves on.
 func (a *RateLimiterAdapter) IsAllowed(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
-	// Atomically increment the counter for the given key.
-	count, err := a.rdb.Incr(ctx, key).Result()
-	if err != nil {
-		return false, fmt.Errorf("redis INCR failed: %w", err)
-	}
-
-	// If this is the first request in the window, set the expiration time.
-	if count == 1 {
-		if err := a.rdb.Expire(ctx, key, window).Err(); err != nil {
-			return false, fmt.Errorf("redis EXPIRE failed: %w", err)
-		}
-	}
-
-	// Check if the count exceeds the limit.
-	return count <= int64(limit), nil
+	_, allowed, err := a.velocity.RecordWithin(ctx,
+		domain.VelocityEvent{ID: uuid.NewString(), At: time.Now()},
+		[]domain.VelocityQuery{{Key: key, Window: window}},
+		int64(limit),
+	)
+	return allowed, err
 }
 
 // Close gracefully closes the Redis connection.
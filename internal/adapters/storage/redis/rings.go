@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
+)
+
+// linkCardScript adds a card and its attributes to the union-find forests of clusters of two generations.
+//
+// KEYS, per generation g = 0, 1 at base = g * (3 + n): KEYS[base + 1]: hash node -> parent;
+// KEYS[base + 2]: hash root -> number of nodes; KEYS[base + 3]: hash root -> number of cards;
+// KEYS[base + 3 + i]: set of the cards seen with attribute i.
+// ARGV[1]: hub limit; ARGV[2]: card hash; ARGV[3]: expiry of generation 0 as unix milliseconds;
+// ARGV[4]: expiry of generation 1; ARGV[4 + i]: node of attribute i, "<kind>:<value>".
+// Returns {cluster cards, cluster size, shared attributes, max cards of an attribute} of generation 0.
+//
+// An attribute seen with more than the hub limit of cards (a mobile carrier IP, a shared office device)
+// stops merging clusters and its card set stops growing; it still counts as shared.
+var linkCardScript = redis.NewScript(`
+local hub = tonumber(ARGV[1])
+local n = #ARGV - 4
+
+local function link(base, expireAt)
+  local parent, sizes, cards = KEYS[base + 1], KEYS[base + 2], KEYS[base + 3]
+
+  local function find(x, isCard)
+    local p = redis.call('HGET', parent, x)
+    if not p then
+      redis.call('HSET', parent, x, x)
+      redis.call('HSET', sizes, x, 1)
+      redis.call('HSET', cards, x, isCard and 1 or 0)
+      return x
+    end
+    local root = x
+    while p ~= root do
+      root = p
+      p = redis.call('HGET', parent, root)
+    end
+    while x ~= root do
+      local nxt = redis.call('HGET', parent, x)
+      redis.call('HSET', parent, x, root)
+      x = nxt
+    end
+    return root
+  end
+
+  local function union(a, b)
+    if a == b then
+      return a
+    end
+    if tonumber(redis.call('HGET', sizes, a)) < tonumber(redis.call('HGET', sizes, b)) then
+      a, b = b, a
+    end
+    redis.call('HSET', parent, b, a)
+    redis.call('HINCRBY', sizes, a, redis.call('HGET', sizes, b))
+    redis.call('HINCRBY', cards, a, redis.call('HGET', cards, b))
+    redis.call('HDEL', sizes, b)
+    redis.call('HDEL', cards, b)
+    return a
+  end
+
+  local root = find('card:' .. ARGV[2], true)
+  local shared, maxCards = 0, 1
+  for i = 1, n do
+    local set = KEYS[base + 3 + i]
+    local c = redis.call('SCARD', set)
+    if c <= hub and redis.call('SISMEMBER', set, ARGV[2]) == 0 then
+      redis.call('SADD', set, ARGV[2])
+      c = c + 1
+    end
+    if c > 1 then
+      shared = shared + 1
+    end
+    if c > maxCards then
+      maxCards = c
+    end
+    if c <= hub then
+      root = union(root, find(ARGV[4 + i], false))
+    end
+    redis.call('PEXPIREAT', set, expireAt)
+  end
+  for k = base + 1, base + 3 do
+    redis.call('PEXPIREAT', KEYS[k], expireAt)
+  end
+  return {tonumber(redis.call('HGET', cards, root)), tonumber(redis.call('HGET', sizes, root)), shared, maxCards}
+end
+
+link(3 + n, ARGV[4])
+return link(0, ARGV[3])
+`)
+
+// RingGraphAdapter is a Redis implementation of the RingGraph port.
+//
+// Union-find cannot forget a link, so the graph is kept in generations of one window each: a link is added
+// to the current generation and to the next one, and clusters are read from the current one. A generation
+// thus holds the links of the last one to two windows and expires when the next one takes over, so clusters
+// split again once the links joining them age out and memory is bounded by the traffic of two windows.
+type RingGraphAdapter struct {
+	rdb      *redis.Client
+	prefix   string
+	hubCards int
+	window   time.Duration
+}
+
+// NewRingGraphAdapter creates the adapter over an existing client. Keys are namespaced with prefix;
+// attributes seen with more than hubCards cards no longer merge clusters; links are kept for one to two windows.
+func NewRingGraphAdapter(rdb *redis.Client, prefix string, hubCards int, window time.Duration) *RingGraphAdapter {
+	return &RingGraphAdapter{rdb: rdb, prefix: prefix, hubCards: hubCards, window: window}
+}
+
+// LinkCard implements the RingGraph interface method.
+func (a *RingGraphAdapter) LinkCard(ctx context.Context, cardHash string, links []domain.RingLink) (domain.RingStats, error) {
+	window := a.window.Milliseconds()
+	generation := time.Now().UnixMilli() / window
+
+	keys := make([]string, 0, 2*(3+len(links)))
+	args := []interface{}{a.hubCards, cardHash, (generation + 1) * window, (generation + 2) * window}
+	for _, g := range []int64{generation, generation + 1} {
+		prefix := fmt.Sprintf("%s:%d", a.prefix, g)
+		keys = append(keys, prefix+":parent", prefix+":size", prefix+":cards")
+		for _, l := range links {
+			keys = append(keys, prefix+":attr:"+string(l.Kind)+":"+l.Value)
+		}
+	}
+	for _, l := range links {
+		args = append(args, string(l.Kind)+":"+l.Value)
+	}
+
+	res, err := linkCardScript.Run(ctx, a.rdb, keys, args...).Int64Slice()
+	if err != nil {
+		return domain.RingStats{}, fmt.Errorf("failed to link card: %w", err)
+	}
+	if len(res) != 4 {
+		return domain.RingStats{}, fmt.Errorf("failed to link card: unexpected reply %v", res)
+	}
+	return domain.RingStats{
+		ClusterCards:      int(res[0]),
+		ClusterSize:       int(res[1]),
+		SharedAttributes:  int(res[2]),
+		MaxAttributeCards: int(res[3]),
+	}, nil
+}
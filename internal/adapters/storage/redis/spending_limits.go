@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
+)
+
+// consumeLimitsScript checks every counter first and only then increments all of them,
+// so a transaction is either accounted in every limit or in none.
+//
+// KEYS: counter keys (hashes with "amount" and "count" fields).
+// ARGV[1]: transaction amount; then per key: max amount, max count, expiry as unix milliseconds.
+// Returns {0} on success or {index, kind} of the first exceeded limit (1-based index).
+var consumeLimitsScript = redis.NewScript(`
+local amount = tonumber(ARGV[1])
+for i, key in ipairs(KEYS) do
+  local base = 1 + (i - 1) * 3
+  local maxAmount = tonumber(ARGV[base + 1])
+  local maxCount = tonumber(ARGV[base + 2])
+  local current = redis.call('HMGET', key, 'amount', 'count')
+  local curAmount = tonumber(current[1]) or 0
+  local curCount = tonumber(current[2]) or 0
+  if maxAmount > 0 and curAmount + amount > maxAmount then
+    return {i, 'amount'}
+  end
+  if maxCount > 0 and curCount + 1 > maxCount then
+    return {i, 'count'}
+  end
+end
+for i, key in ipairs(KEYS) do
+  local base = 1 + (i - 1) * 3
+  redis.call('HINCRBYFLOAT', key, 'amount', amount)
+  redis.call('HINCRBY', key, 'count', 1)
+  redis.call('PEXPIREAT', key, ARGV[base + 3])
+end
+return {0}
+`)
+
+// revertLimitsScript takes a consumed amount back from every counter that still exists.
+var revertLimitsScript = redis.NewScript(`
+local amount = tonumber(ARGV[1])
+for _, key in ipairs(KEYS) do
+  if redis.call('EXISTS', key) == 1 then
+    redis.call('HINCRBYFLOAT', key, 'amount', -amount)
+    redis.call('HINCRBY', key, 'count', -1)
+  end
+end
+return 0
+`)
+
+// SpendingLimitAdapter is a Redis implementation of the SpendingLimitRepository port.
+type SpendingLimitAdapter struct {
+	rdb *redis.Client
+}
+
+// NewSpendingLimitAdapter creates and tests a new connection to Redis and returns the adapter.
+func NewSpendingLimitAdapter(addr string) (*SpendingLimitAdapter, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+
+	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &SpendingLimitAdapter{rdb: rdb}, nil
+}
+
+// Consume implements the SpendingLimitRepository interface method.
+func (a *SpendingLimitAdapter) Consume(ctx context.Context, counters []domain.LimitCounter, amount float64) error {
+	keys := make([]string, 0, len(counters))
+	args := make([]interface{}, 0, 1+len(counters)*3)
+	args = append(args, strconv.FormatFloat(amount, 'f', -1, 64))
+	for _, c := range counters {
+		keys = append(keys, c.Key)
+		args = append(args,
+			strconv.FormatFloat(c.Limit.MaxAmount, 'f', -1, 64),
+			c.Limit.MaxCount,
+			c.ExpiresAt.UnixMilli(),
+		)
+	}
+
+	res, err := consumeLimitsScript.Run(ctx, a.rdb, keys, args...).Slice()
+	if err != nil {
+		return fmt.Errorf("redis spending limit script failed: %w", err)
+	}
+
+	idx, _ := res[0].(int64)
+	if idx == 0 {
+		return nil
+	}
+	if idx < 1 || int(idx) > len(counters) || len(res) < 2 {
+		return fmt.Errorf("unexpected spending limit script result: %v", res)
+	}
+	kind, _ := res[1].(string)
+	return &domain.LimitExceededError{
+		Limit: counters[idx-1].Limit,
+		Kind:  domain.LimitKind(kind),
+	}
+}
+
+// Revert implements the SpendingLimitRepository interface method.
+func (a *SpendingLimitAdapter) Revert(ctx context.Context, counters []domain.LimitCounter, amount float64) error {
+	keys := make([]string, 0, len(counters))
+	for _, c := range counters {
+		keys = append(keys, c.Key)
+	}
+	if err := revertLimitsScript.Run(ctx, a.rdb, keys, strconv.FormatFloat(amount, 'f', -1, 64)).Err(); err != nil {
+		return fmt.Errorf("redis spending limit revert failed: %w", err)
+	}
+	return nil
+}
+
+// Close gracefully closes the Redis connection.
+func (a *SpendingLimitAdapter) Close() error {
+	return a.rdb.Close()
+}
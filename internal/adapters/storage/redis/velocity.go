@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"payment-processing-system/internal/core/domain"
+)
+
+// recordVelocityScript keeps one sorted set per key and window: members are "<event id>|<amount>"
+// scored by the event time, and the summed amount of the set is kept next to it under "<key>:sum".
+//
+// KEYS: one key per query, already suffixed with its window.
+// ARGV[1]: event time as unix milliseconds; ARGV[2]: member; ARGV[3]: amount; ARGV[4]: maximum count, 0 for none;
+// ARGV[4 + i]: window of KEYS[i] in milliseconds.
+// Members that left the window are trimmed and subtracted from the sum, so each event is read once more
+// when it expires and a call costs O(log n) amortized instead of a scan of the window.
+// Events recorded out of order may leave newer members in the set; those are not counted.
+// If a window already holds the maximum count, the event is recorded in none of them.
+// Returns 1 if the event was recorded, or 0, followed by count and summed amount per query.
+var recordVelocityScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local member = ARGV[2]
+local amount = tonumber(ARGV[3])
+local maxCount = tonumber(ARGV[4])
+local function amountOf(m)
+  return tonumber(string.match(m, '|([^|]*)$'))
+end
+local function aggregate(key, sumKey)
+  local count = redis.call('ZCARD', key)
+  local sum = tonumber(redis.call('GET', sumKey) or '0')
+  for _, m in ipairs(redis.call('ZRANGEBYSCORE', key, '(' .. now, '+inf')) do
+    count = count - 1
+    sum = sum - amountOf(m)
+  end
+  return count, sum
+end
+
+local record = 1
+for i, key in ipairs(KEYS) do
+  local w = tonumber(ARGV[i + 4])
+  local sumKey = key .. ':sum'
+  for _, m in ipairs(redis.call('ZRANGEBYSCORE', key, '-inf', now - w)) do
+    redis.call('INCRBYFLOAT', sumKey, -amountOf(m))
+  end
+  redis.call('ZREMRANGEBYSCORE', key, '-inf', now - w)
+  if redis.call('ZCARD', key) == 0 then
+    redis.call('DEL', sumKey)
+  end
+  if maxCount > 0 and aggregate(key, sumKey) >= maxCount then
+    record = 0
+  end
+end
+
+local out = {record}
+for i, key in ipairs(KEYS) do
+  local w = tonumber(ARGV[i + 4])
+  local sumKey = key .. ':sum'
+  if record == 1 then
+    if redis.call('ZADD', key, now, member) == 1 then
+      redis.call('INCRBYFLOAT', sumKey, amount)
+    end
+    redis.call('PEXPIRE', key, w)
+    redis.call('PEXPIRE', sumKey, w)
+  end
+  local count, sum = aggregate(key, sumKey)
+  out[#out + 1] = count
+  out[#out + 1] = tostring(sum)
+end
+return out
+`)
+
+// VelocityCounterAdapter is a Redis implementation of the VelocityCounter port.
+// Windows are exact: every event within the window is kept, once per distinct window of a key.
+type VelocityCounterAdapter struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+// NewVelocityCounterAdapter creates the adapter over an existing client. Keys are namespaced with prefix.
+func NewVelocityCounterAdapter(rdb *redis.Client, prefix string) *VelocityCounterAdapter {
+	return &VelocityCounterAdapter{rdb: rdb, prefix: prefix}
+}
+
+// Record implements the VelocityCounter interface method.
+func (a *VelocityCounterAdapter) Record(ctx context.Context, event domain.VelocityEvent, queries []domain.VelocityQuery) ([]domain.VelocityAggregate, error) {
+	aggregates, _, err := a.record(ctx, event, queries, 0)
+	return aggregates, err
+}
+
+// RecordWithin records the event only if none of the windows already holds maxCount events,
+// and tells whether it did. The aggregates include the event only if it was recorded.
+// Rejected events are not counted, so a key over its limit is admitted again once its window moves on.
+func (a *VelocityCounterAdapter) RecordWithin(ctx context.Context, event domain.VelocityEvent, queries []domain.VelocityQuery, maxCount int64) ([]domain.VelocityAggregate, bool, error) {
+	return a.record(ctx, event, queries, maxCount)
+}
+
+// record runs the velocity script; maxCount 0 records the event unconditionally.
+func (a *VelocityCounterAdapter) record(ctx context.Context, event domain.VelocityEvent, queries []domain.VelocityQuery, maxCount int64) ([]domain.VelocityAggregate, bool, error) {
+	if len(queries) == 0 {
+		return nil, true, nil
+	}
+
+	// A key and window asked for twice is recorded once; every query reads the result of its pair.
+	slots := make(map[string]int, len(queries))
+	index := make([]int, len(queries))
+	keys := make([]string, 0, len(queries))
+	args := make([]interface{}, 0, 4+len(queries))
+	amount := strconv.FormatFloat(event.Amount, 'f', -1, 64)
+	args = append(args, event.At.UnixMilli(), event.ID+"|"+amount, amount, maxCount)
+	for i, q := range queries {
+		key := fmt.Sprintf("%s:%s:%d", a.prefix, q.Key, q.Window.Milliseconds())
+		slot, ok := slots[key]
+		if !ok {
+			slot = len(keys)
+			slots[key] = slot
+			keys = append(keys, key)
+			args = append(args, q.Window.Milliseconds())
+		}
+		index[i] = slot
+	}
+
+	res, err := recordVelocityScript.Run(ctx, a.rdb, keys, args...).Slice()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis velocity script failed: %w", err)
+	}
+	if len(res) != 1+2*len(keys) {
+		return nil, false, fmt.Errorf("unexpected velocity script result: %v", res)
+	}
+	recorded, _ := res[0].(int64)
+
+	aggregates := make([]domain.VelocityAggregate, len(queries))
+	for i, slot := range index {
+		count, _ := res[1+2*slot].(int64)
+		sum, _ := res[2+2*slot].(string)
+		amount, err := strconv.ParseFloat(sum, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("unexpected velocity amount %q: %w", sum, err)
+		}
+		aggregates[i] = domain.VelocityAggregate{Count: count, Amount: amount}
+	}
+	return aggregates, recorded == 1, nil
+}
@@ -0,0 +1,292 @@
+// Package anomaly watches merchant-level metrics (volume, average ticket, decline rate and the share
+// of transactions from new countries) and reports the buckets that deviate from the merchant's baseline.
+//
+// Every complete bucket is compared once with a baseline built from the buckets before it:
+// an exponentially weighted moving average, or the same time of day over the previous days.
+// Only upward deviations are reported: a drop in volume is not a fraud signal.
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// Baseline selects how the expected value of a metric is computed.
+type Baseline string
+
+const (
+	// BaselineEWMA weights recent buckets more, so a gradual growth is learned instead of reported.
+	BaselineEWMA Baseline = "ewma"
+	// BaselineSeasonal compares a bucket with the same time of day over the previous days.
+	BaselineSeasonal Baseline = "seasonal"
+)
+
+// countryWarmup is the part of the history used only to learn the countries of a merchant:
+// at the start of the history every country is new.
+const countryWarmup = 24 * time.Hour
+
+// season is the period of the seasonal baseline.
+const season = 24 * time.Hour
+
+// minSeasonalSamples is how many earlier days the seasonal baseline needs.
+const minSeasonalSamples = 3
+
+// Options configures a Detector.
+type Options struct {
+	Bucket   time.Duration
+	History  time.Duration
+	Baseline Baseline
+	// Alpha is the EWMA smoothing factor within (0, 1].
+	Alpha float64
+	// MinHistory is how many earlier buckets with transactions a merchant needs to be evaluated.
+	MinHistory int
+	// MinTransactions is the smallest bucket whose average ticket and rates are meaningful.
+	MinTransactions int
+	// ZScores are the alert thresholds; a metric without a threshold is not watched.
+	ZScores map[domain.MerchantMetric]float64
+}
+
+// Detector evaluates merchant activity bucket by bucket.
+type Detector struct {
+	source     ports.MerchantActivitySource
+	publishers []ports.AnomalyPublisher
+	opts       Options
+	metrics    []domain.MerchantMetric
+	logger     *slog.Logger
+	now        func() time.Time
+
+	// evaluated is the start of the last bucket whose anomalies were delivered.
+	evaluated time.Time
+}
+
+// NewDetector creates a detector. Anomalies are delivered to every publisher.
+func NewDetector(source ports.MerchantActivitySource, opts Options, logger *slog.Logger, publishers ...ports.AnomalyPublisher) (*Detector, error) {
+	if opts.Bucket < time.Second || opts.History < opts.Bucket {
+		return nil, fmt.Errorf("the bucket must be at least a second and the history at least a bucket")
+	}
+	if opts.Baseline == BaselineSeasonal && season%opts.Bucket != 0 {
+		return nil, fmt.Errorf("the seasonal baseline needs a bucket that divides a day")
+	}
+	if opts.Alpha <= 0 || opts.Alpha > 1 {
+		return nil, fmt.Errorf("alpha must be within (0, 1]")
+	}
+	d := &Detector{source: source, publishers: publishers, opts: opts, logger: logger, now: time.Now}
+	for m := range opts.ZScores {
+		if !m.Valid() {
+			return nil, fmt.Errorf("unknown metric %q", m)
+		}
+		d.metrics = append(d.metrics, m)
+	}
+	slices.Sort(d.metrics)
+	return d, nil
+}
+
+// DetectAnomalies evaluates the last complete bucket and delivers its anomalies. A bucket is evaluated again
+// on the next call if the delivery failed, so a publisher may see the same anomaly twice; its ID stays the same.
+func (d *Detector) DetectAnomalies(ctx context.Context) error {
+	anomalies, bucket, err := d.Detect(ctx, d.now())
+	if err != nil || bucket.IsZero() {
+		return err
+	}
+	if len(anomalies) > 0 {
+		var errs []error
+		for _, p := range d.publishers {
+			if err := p.PublishAnomalies(ctx, anomalies); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("failed to publish merchant anomalies: %w", err)
+		}
+		d.logger.Warn("обнаружены аномалии мерчантов", "bucket", bucket, "count", len(anomalies))
+	}
+	d.evaluated = bucket
+	return nil
+}
+
+// Detect returns the anomalies of the last bucket complete at now, and the start of that bucket.
+// The bucket is zero if it has already been evaluated.
+func (d *Detector) Detect(ctx context.Context, now time.Time) ([]domain.MerchantAnomaly, time.Time, error) {
+	size := int64(d.opts.Bucket / time.Second)
+	end := time.Unix(now.Unix()/size*size, 0).UTC()
+	target := end.Add(-d.opts.Bucket)
+	if !d.evaluated.IsZero() && !target.After(d.evaluated) {
+		return nil, time.Time{}, nil
+	}
+	n := int(d.opts.History / d.opts.Bucket)
+	from := target.Add(-time.Duration(n) * d.opts.Bucket)
+
+	activity, err := d.source.MerchantActivity(ctx, from, end, d.opts.Bucket)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var anomalies []domain.MerchantAnomaly
+	for merchantID, series := range buildSeries(activity, from, d.opts.Bucket, n+1) {
+		anomalies = append(anomalies, d.evaluate(merchantID, series, from, now)...)
+	}
+	slices.SortFunc(anomalies, func(a, b domain.MerchantAnomaly) int {
+		if c := strings.Compare(a.MerchantID, b.MerchantID); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Metric), string(b.Metric))
+	})
+	return anomalies, target, nil
+}
+
+// buildSeries spreads the activity of every merchant over n buckets starting at from; empty buckets stay zero.
+// A transaction is from a new country if the merchant had no transaction from that country in an earlier bucket.
+func buildSeries(activity []domain.MerchantActivity, from time.Time, bucket time.Duration, n int) map[string][]domain.MerchantBucket {
+	series := make(map[string][]domain.MerchantBucket)
+	countries := make(map[string]map[string]bool)
+	for _, a := range activity {
+		i := int(a.BucketStart.Sub(from) / bucket)
+		if i < 0 || i >= n {
+			continue
+		}
+		s, ok := series[a.MerchantID]
+		if !ok {
+			s = make([]domain.MerchantBucket, n)
+			for j := range s {
+				s[j].Start = from.Add(time.Duration(j) * bucket)
+			}
+			series[a.MerchantID] = s
+			countries[a.MerchantID] = make(map[string]bool)
+		}
+		b := &s[i]
+		b.Transactions += a.Transactions
+		b.Amount += a.Amount
+		b.Declines += a.Declines
+		// Activity is ordered by bucket and a country appears once per bucket.
+		if a.Country != "" && !countries[a.MerchantID][a.Country] {
+			b.NewCountry += a.Transactions
+			countries[a.MerchantID][a.Country] = true
+		}
+	}
+	return series
+}
+
+// evaluate compares the last bucket of the series with the baseline of the buckets before it.
+func (d *Detector) evaluate(merchantID string, series []domain.MerchantBucket, from, now time.Time) []domain.MerchantAnomaly {
+	history, current := series[:len(series)-1], series[len(series)-1]
+	active := 0
+	for _, b := range history {
+		if b.Transactions > 0 {
+			active++
+		}
+	}
+	if active < d.opts.MinHistory {
+		return nil
+	}
+
+	var anomalies []domain.MerchantAnomaly
+	for _, m := range d.metrics {
+		value, ok := d.value(current, m, from)
+		if !ok {
+			continue
+		}
+		mean, std, ok := d.baseline(history, current.Start, m, from)
+		if !ok {
+			continue
+		}
+		std = math.Max(std, stdFloor(m, mean, current.Transactions))
+		z := (value - mean) / std
+		threshold := d.opts.ZScores[m]
+		if z < threshold {
+			continue
+		}
+		anomalies = append(anomalies, domain.MerchantAnomaly{
+			ID:          domain.AnomalyID(merchantID, m, current.Start),
+			MerchantID:  merchantID,
+			Metric:      m,
+			BucketStart: current.Start,
+			BucketEnd:   current.Start.Add(d.opts.Bucket),
+			Value:       value,
+			Baseline:    mean,
+			StdDev:      std,
+			ZScore:      z,
+			Threshold:   threshold,
+			DetectedAt:  now,
+		})
+	}
+	return anomalies
+}
+
+// value returns the metric of a bucket if it is meaningful: ratios need MinTransactions,
+// and the new-country share is unknown while the countries are being learned.
+func (d *Detector) value(b domain.MerchantBucket, m domain.MerchantMetric, from time.Time) (float64, bool) {
+	if m != domain.MetricVolume && b.Transactions < d.opts.MinTransactions {
+		return 0, false
+	}
+	if m == domain.MetricNewCountryShare && b.Start.Before(from.Add(countryWarmup)) {
+		return 0, false
+	}
+	return b.Value(m)
+}
+
+// baseline returns the expected value of the metric at target and its standard deviation.
+func (d *Detector) baseline(history []domain.MerchantBucket, target time.Time, m domain.MerchantMetric, from time.Time) (mean, std float64, ok bool) {
+	if d.opts.Baseline == BaselineSeasonal {
+		var values []float64
+		for _, b := range history {
+			if target.Sub(b.Start)%season != 0 {
+				continue
+			}
+			if v, ok := d.value(b, m, from); ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) < minSeasonalSamples {
+			return 0, 0, false
+		}
+		for _, v := range values {
+			mean += v
+		}
+		mean /= float64(len(values))
+		var variance float64
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		return mean, math.Sqrt(variance / float64(len(values)-1)), true
+	}
+
+	var variance float64
+	for _, b := range history {
+		v, defined := d.value(b, m, from)
+		if !defined {
+			continue
+		}
+		if !ok {
+			mean, ok = v, true
+			continue
+		}
+		diff := v - mean
+		incr := d.opts.Alpha * diff
+		mean += incr
+		variance = (1 - d.opts.Alpha) * (variance + diff*incr)
+	}
+	return mean, math.Sqrt(variance), ok
+}
+
+// stdFloor keeps a quiet merchant from alerting on noise: a flat history has no deviation at all.
+// Counts vary at least like a Poisson process and rates like a binomial one over n transactions.
+func stdFloor(m domain.MerchantMetric, mean float64, n int) float64 {
+	switch m {
+	case domain.MetricVolume:
+		return math.Max(math.Sqrt(mean), 1)
+	case domain.MetricAvgTicket:
+		return math.Max(0.05*mean, 0.01)
+	default:
+		p := math.Min(math.Max(mean, 0), 1)
+		return math.Max(math.Sqrt(p*(1-p)/float64(max(n, 1))), 0.02)
+	}
+}
@@ -0,0 +1,155 @@
+package anomaly
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSource returns the activity of the requested window, like the ClickHouse query does.
+type stubSource struct{ activity []domain.MerchantActivity }
+
+func (s *stubSource) MerchantActivity(_ context.Context, from, to time.Time, _ time.Duration) ([]domain.MerchantActivity, error) {
+	var out []domain.MerchantActivity
+	for _, a := range s.activity {
+		if !a.BucketStart.Before(from) && a.BucketStart.Before(to) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+type stubPublisher struct{ published [][]domain.MerchantAnomaly }
+
+func (p *stubPublisher) PublishAnomalies(_ context.Context, anomalies []domain.MerchantAnomaly) error {
+	p.published = append(p.published, anomalies)
+	return nil
+}
+
+var testNow = time.Date(2026, 3, 10, 13, 5, 0, 0, time.UTC)
+
+// hourly returns four days of hourly activity of m1 ending with the bucket complete at testNow;
+// perHour gives the transactions from DE in each hour, last gives the activity of the last bucket.
+func hourly(perHour func(h time.Time) int, last ...domain.MerchantActivity) *stubSource {
+	s := &stubSource{}
+	end := testNow.Truncate(time.Hour).Add(-time.Hour)
+	for h := end.Add(-4 * 24 * time.Hour); h.Before(end); h = h.Add(time.Hour) {
+		n := perHour(h)
+		s.activity = append(s.activity, domain.MerchantActivity{MerchantID: "m1", BucketStart: h, Country: "DE", Transactions: n, Amount: 50 * float64(n)})
+	}
+	for _, a := range last {
+		a.MerchantID, a.BucketStart = "m1", end
+		s.activity = append(s.activity, a)
+	}
+	return s
+}
+
+func newTestDetector(t *testing.T, source *stubSource, baseline Baseline, publishers ...ports.AnomalyPublisher) *Detector {
+	t.Helper()
+	d, err := NewDetector(source, Options{
+		Bucket:          time.Hour,
+		History:         4 * 24 * time.Hour,
+		Baseline:        baseline,
+		Alpha:           0.1,
+		MinHistory:      24,
+		MinTransactions: 5,
+		ZScores: map[domain.MerchantMetric]float64{
+			domain.MetricVolume: 4, domain.MetricAvgTicket: 4, domain.MetricDeclineRate: 3, domain.MetricNewCountryShare: 3,
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)), publishers...)
+	require.NoError(t, err)
+	d.now = func() time.Time { return testNow }
+	return d
+}
+
+func metrics(anomalies []domain.MerchantAnomaly) []domain.MerchantMetric {
+	var ms []domain.MerchantMetric
+	for _, a := range anomalies {
+		ms = append(ms, a.Metric)
+	}
+	return ms
+}
+
+func TestDetector_Spikes(t *testing.T) {
+	flat := func(time.Time) int { return 10 }
+	tests := []struct {
+		name string
+		last []domain.MerchantActivity
+		want []domain.MerchantMetric
+	}{
+		{"usual bucket", []domain.MerchantActivity{{Country: "DE", Transactions: 11, Amount: 550}}, nil},
+		{"volume spike", []domain.MerchantActivity{{Country: "DE", Transactions: 60, Amount: 3000}}, []domain.MerchantMetric{domain.MetricVolume}},
+		{"average ticket", []domain.MerchantActivity{{Country: "DE", Transactions: 10, Amount: 5000}}, []domain.MerchantMetric{domain.MetricAvgTicket}},
+		{"declines", []domain.MerchantActivity{{Country: "DE", Transactions: 10, Amount: 500, Declines: 4}}, []domain.MerchantMetric{domain.MetricDeclineRate}},
+		{"new countries", []domain.MerchantActivity{
+			{Country: "BR", Transactions: 6, Amount: 300},
+			{Country: "DE", Transactions: 5, Amount: 250},
+		}, []domain.MerchantMetric{domain.MetricNewCountryShare}},
+		{"quiet bucket is not an anomaly", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDetector(t, hourly(flat, tt.last...), BaselineEWMA)
+			anomalies, bucket, err := d.Detect(context.Background(), testNow)
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), bucket)
+			assert.Equal(t, tt.want, metrics(anomalies))
+			for _, a := range anomalies {
+				assert.Equal(t, domain.AnomalyID("m1", a.Metric, bucket), a.ID)
+				assert.GreaterOrEqual(t, a.ZScore, a.Threshold)
+			}
+		})
+	}
+}
+
+func TestDetector_SeasonalBaselineLearnsDailyPeaks(t *testing.T) {
+	// Every day at noon the merchant has ten times the usual volume.
+	peaks := func(h time.Time) int {
+		if h.Hour() == 12 {
+			return 100
+		}
+		return 10
+	}
+	last := domain.MerchantActivity{Country: "DE", Transactions: 100, Amount: 5000}
+
+	ewma := newTestDetector(t, hourly(peaks, last), BaselineEWMA)
+	anomalies, _, err := ewma.Detect(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MerchantMetric{domain.MetricVolume}, metrics(anomalies))
+
+	seasonal := newTestDetector(t, hourly(peaks, last), BaselineSeasonal)
+	anomalies, _, err = seasonal.Detect(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func TestDetector_NeedsHistory(t *testing.T) {
+	young := func(h time.Time) int {
+		if testNow.Sub(h) < 12*time.Hour {
+			return 10
+		}
+		return 0
+	}
+	d := newTestDetector(t, hourly(young, domain.MerchantActivity{Country: "DE", Transactions: 500, Amount: 25000}), BaselineEWMA)
+	anomalies, _, err := d.Detect(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func TestDetector_EvaluatesBucketOnce(t *testing.T) {
+	p := &stubPublisher{}
+	d := newTestDetector(t, hourly(func(time.Time) int { return 10 }, domain.MerchantActivity{Country: "DE", Transactions: 60, Amount: 3000}), BaselineEWMA, p)
+
+	require.NoError(t, d.DetectAnomalies(context.Background()))
+	require.NoError(t, d.DetectAnomalies(context.Background()))
+	require.Len(t, p.published, 1)
+	assert.Equal(t, "m1", p.published[0][0].MerchantID)
+}
@@ -3,56 +3,67 @@ package antifraud
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"payment-processing-system/internal/config"
 	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
 )
 
-// CachingRuleEngine implements the FraudRuleEngine interface using Redis for stateful checks.
+// cachingEngineVersion identifies the rule set in fraud reports.
+const cachingEngineVersion = "caching-rules/v2"
+
+// CachingRuleEngine implements the FraudRuleEngine interface using velocity counters for stateful checks.
 type CachingRuleEngine struct {
-	rdb *redis.Client
-	cfg config.AntiFraudConfig
+	velocity ports.VelocityCounter
+	cfg      config.AntiFraudConfig
 }
 
-// NewCachingRuleEngine creates a new engine connected to Redis.
-func NewCachingRuleEngine(rdb *redis.Client, cfg config.AntiFraudConfig) *CachingRuleEngine {
+// NewCachingRuleEngine creates a new engine over the given velocity counters.
+func NewCachingRuleEngine(velocity ports.VelocityCounter, cfg config.AntiFraudConfig) *CachingRuleEngine {
 	return &CachingRuleEngine{
-		rdb: rdb,
-		cfg: cfg,
+		velocity: velocity,
+		cfg:      cfg,
 	}
 }
 
 // CheckTransaction implements the fraud checking logic using Redis.
-func (e *CachingRuleEngine) CheckTransaction(tx domain.Transaction) domain.FraudResult {
-	ctx := context.Background()
-
+func (e *CachingRuleEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
 	// Rule 1: Transaction amount exceeds a simple threshold.  (TODO: default < 1000)
 	amountThreshold := e.cfg.AmountThreshold
+	explanation := &domain.FraudExplanation{
+		Engine: cachingEngineVersion,
+		Rules: []domain.RuleEvaluation{{
+			Name:      "amount_threshold",
+			Condition: fmt.Sprintf("amount > %g", amountThreshold),
+			Action:    "decline",
+			Inputs:    []string{"amount"},
+		}},
+		Inputs: map[string]any{"amount": tx.Amount},
+	}
 	if tx.Amount > amountThreshold {
-		return domain.FraudResult{IsFraudulent: true, Reason: "Amount exceeds threshold"}
+		return declined(explanation, "Amount exceeds threshold"), nil
 	}
 
-	// Rule 2: More than 3 transactions from a single card within a 1-minute window.
-	key := fmt.Sprintf("card_tx_count:%s", tx.CardNumberHash)
-
-	// Atomically increment the counter for this card hash.
-	count, err := e.rdb.Incr(ctx, key).Result()
+	// Rule 2: More than 3 transactions from a single card within a sliding 1-minute window.
+	// Window and threshold come from the config (TODO: default - 60 seconds, 3 transactions).
+	window := time.Duration(e.cfg.FrequencyWindowSeconds) * time.Second
+	aggregates, err := e.velocity.Record(ctx, velocityEvent(tx), []domain.VelocityQuery{
+		{Key: domain.VelocityKey(domain.VelocityCard, tx.CardNumberHash), Window: window},
+	})
 	if err != nil {
-		log.Printf("ERROR: Redis INCR failed: %v", err)
-		return domain.FraudResult{}
+		return domain.FraudResult{}, err
 	}
-
-	if count == 1 {
-		// Set the lifetime of the key from the config (TODO: default - 60 second)
-		freqWindowSec := int64(e.cfg.FrequencyWindowSeconds)
-		ttl := time.Duration(freqWindowSec) * time.Second
-		e.rdb.Expire(ctx, key, ttl)
-	}
-	// Set the lifetime of the key from the config (TODO: default Threshold - 3 transactions)
+	count := aggregates[0].Count
 	freqThreshold := int64(e.cfg.FrequencyThreshold)
+	input := fmt.Sprintf("velocity_count:card:%ds", e.cfg.FrequencyWindowSeconds)
+	explanation.Inputs[input] = count
+	explanation.Rules = append(explanation.Rules, domain.RuleEvaluation{
+		Name:      "card_frequency",
+		Condition: fmt.Sprintf("%s > %d", input, freqThreshold),
+		Action:    "decline",
+		Inputs:    []string{input},
+	})
 
 	if count > freqThreshold {
 		reason := fmt.Sprintf(
@@ -60,8 +71,25 @@ func (e *CachingRuleEngine) CheckTransaction(tx domain.Transaction) domain.Fraud
 			count,
 			e.cfg.FrequencyWindowSeconds,
 		)
-		return domain.FraudResult{IsFraudulent: true, Reason: reason}
+		return declined(explanation, reason), nil
 	}
 
-	return domain.FraudResult{}
+	explanation.Decision = domain.DecisionAllow
+	return domain.FraudResult{Decision: domain.DecisionAllow, EngineVersion: cachingEngineVersion, Explanation: explanation}, nil
+}
+
+// declined builds the result for a hit of the last rule of the explanation.
+func declined(explanation *domain.FraudExplanation, reason string) domain.FraudResult {
+	last := &explanation.Rules[len(explanation.Rules)-1]
+	last.Matched, last.Score = true, 1
+	explanation.Decision, explanation.Score = domain.DecisionDecline, 1
+	return domain.FraudResult{
+		IsFraudulent:   true,
+		Reason:         reason,
+		RiskScore:      1,
+		TriggeredRules: []string{last.Name},
+		Decision:       domain.DecisionDecline,
+		EngineVersion:  cachingEngineVersion,
+		Explanation:    explanation,
+	}
 }
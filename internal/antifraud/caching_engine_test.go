@@ -0,0 +1,45 @@
+package antifraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/config"
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubVelocity struct {
+	count   int64
+	event   domain.VelocityEvent
+	queries []domain.VelocityQuery
+}
+
+func (v *stubVelocity) Record(_ context.Context, event domain.VelocityEvent, queries []domain.VelocityQuery) ([]domain.VelocityAggregate, error) {
+	v.event, v.queries = event, queries
+	return []domain.VelocityAggregate{{Count: v.count}}, nil
+}
+
+func TestCachingRuleEngine_CardFrequency(t *testing.T) {
+	cfg := config.AntiFraudConfig{AmountThreshold: 1000, FrequencyThreshold: 3, FrequencyWindowSeconds: 60}
+	tx := domain.Transaction{ID: uuid.New(), Amount: 10, CardNumberHash: "abc", CreatedAt: time.Now()}
+
+	velocity := &stubVelocity{count: 4}
+	result, err := NewCachingRuleEngine(velocity, cfg).CheckTransaction(context.Background(), tx)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionDecline, result.Decision)
+	assert.Equal(t, []string{"card_frequency"}, result.TriggeredRules)
+	assert.Equal(t, tx.ID.String(), velocity.event.ID)
+	assert.Equal(t, []domain.VelocityQuery{{Key: "card:abc", Window: time.Minute}}, velocity.queries)
+
+	velocity.count = 3
+	result, err = NewCachingRuleEngine(velocity, cfg).CheckTransaction(context.Background(), tx)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionAllow, result.Decision)
+}
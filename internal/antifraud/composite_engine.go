@@ -0,0 +1,226 @@
+package antifraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+)
+
+// CompositeStrategy selects how the results of the member engines are combined.
+type CompositeStrategy string
+
+const (
+	// StrategyAnyFraud takes the most severe decision and the highest score of all engines.
+	StrategyAnyFraud CompositeStrategy = "any_fraud"
+	// StrategyWeightedScore averages the scores by weight and derives the decision from the thresholds.
+	StrategyWeightedScore CompositeStrategy = "weighted_score"
+	// StrategyShortCircuit stops at the first DECLINE; without one it behaves like StrategyAnyFraud.
+	StrategyShortCircuit CompositeStrategy = "short_circuit"
+)
+
+// CompositeMember is one engine of a CompositeEngine.
+type CompositeMember struct {
+	Name    string
+	Engine  domain.FraudRuleEngine
+	Timeout time.Duration
+	Weight  float64
+	// Optional members may fail without failing the check; the failure is only recorded in the contributions.
+	Optional bool
+}
+
+// CompositeOptions configures how a CompositeEngine runs and combines its members.
+type CompositeOptions struct {
+	// Parallel runs all members at once; otherwise they run one after another in the given order.
+	Parallel bool
+	Strategy CompositeStrategy
+	// ReviewScore and DeclineScore are the thresholds of StrategyWeightedScore.
+	ReviewScore  float64
+	DeclineScore float64
+}
+
+// CompositeEngine implements the FraudRuleEngine interface by combining several engines.
+// A failing optional member is recorded and ignored. The check fails if any other member fails, or if no member
+// could evaluate the transaction, so the caller applies its own failure policy instead of taking a partial verdict.
+type CompositeEngine struct {
+	members []CompositeMember
+	opts    CompositeOptions
+}
+
+// NewCompositeEngine creates an engine over members.
+func NewCompositeEngine(members []CompositeMember, opts CompositeOptions) *CompositeEngine {
+	return &CompositeEngine{
+		members: members,
+		opts:    opts,
+	}
+}
+
+// memberOutcome is the raw outcome of one member.
+type memberOutcome struct {
+	result  domain.FraudResult
+	err     error
+	skipped bool
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (e *CompositeEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	var outcomes []memberOutcome
+	if e.opts.Parallel {
+		outcomes = e.runParallel(ctx, tx)
+	} else {
+		outcomes = e.runSequential(ctx, tx)
+	}
+	return e.combine(outcomes)
+}
+
+func (e *CompositeEngine) runSequential(ctx context.Context, tx domain.Transaction) []memberOutcome {
+	outcomes := make([]memberOutcome, len(e.members))
+	declined := false
+	for i, m := range e.members {
+		if declined {
+			outcomes[i].skipped = true
+			continue
+		}
+		outcomes[i].result, outcomes[i].err = e.runMember(ctx, m, tx)
+		declined = e.stopsOn(outcomes[i])
+	}
+	return outcomes
+}
+
+func (e *CompositeEngine) runParallel(ctx context.Context, tx domain.Transaction) []memberOutcome {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outcomes := make([]memberOutcome, len(e.members))
+	var declined atomic.Bool
+	var wg sync.WaitGroup
+	for i, m := range e.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcomes[i].result, outcomes[i].err = e.runMember(ctx, m, tx)
+			if e.stopsOn(outcomes[i]) {
+				declined.Store(true)
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Members cancelled because of a decline did not fail, they were not needed.
+	if declined.Load() {
+		for i := range outcomes {
+			if outcomes[i].err != nil && errors.Is(outcomes[i].err, context.Canceled) {
+				outcomes[i] = memberOutcome{skipped: true}
+			}
+		}
+	}
+	return outcomes
+}
+
+func (e *CompositeEngine) runMember(ctx context.Context, m CompositeMember, tx domain.Transaction) (domain.FraudResult, error) {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+	return m.Engine.CheckTransaction(ctx, tx)
+}
+
+// stopsOn tells whether the remaining members can be skipped after this outcome.
+func (e *CompositeEngine) stopsOn(o memberOutcome) bool {
+	return e.opts.Strategy == StrategyShortCircuit && o.err == nil && o.result.Decision == domain.DecisionDecline
+}
+
+func (e *CompositeEngine) combine(outcomes []memberOutcome) (domain.FraudResult, error) {
+	result := domain.FraudResult{
+		Decision:      domain.DecisionAllow,
+		EngineVersion: "composite/" + string(e.opts.Strategy),
+	}
+
+	var errs, required []error
+	var reasons []string
+	var weightedSum, weights float64
+	evaluated := 0
+	for i, o := range outcomes {
+		m := e.members[i]
+		c := domain.EngineContribution{Engine: m.Name, Weight: m.Weight, Skipped: o.skipped}
+		switch {
+		case o.skipped:
+		case o.err != nil:
+			c.Error = o.err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, o.err))
+			if !m.Optional {
+				required = append(required, fmt.Errorf("%s: %w", m.Name, o.err))
+			}
+		default:
+			evaluated++
+			c.Decision = o.result.Decision
+			c.RiskScore = o.result.RiskScore
+			c.EngineVersion = o.result.EngineVersion
+			explanation := o.result.Explain()
+			c.Explanation = &explanation
+
+			if severity(o.result.Decision) > severity(result.Decision) {
+				result.Decision = o.result.Decision
+			}
+			result.RiskScore = max(result.RiskScore, o.result.RiskScore)
+			weightedSum += m.Weight * o.result.RiskScore
+			weights += m.Weight
+			for _, r := range o.result.TriggeredRules {
+				result.TriggeredRules = append(result.TriggeredRules, m.Name+":"+r)
+			}
+			if o.result.Reason != "" {
+				reasons = append(reasons, o.result.Reason)
+			}
+		}
+		result.Contributions = append(result.Contributions, c)
+	}
+	if evaluated == 0 {
+		return domain.FraudResult{}, fmt.Errorf("no fraud engine could evaluate the transaction: %w", errors.Join(errs...))
+	}
+	if len(required) > 0 {
+		return domain.FraudResult{}, fmt.Errorf("required fraud engine failed: %w", errors.Join(required...))
+	}
+
+	if e.opts.Strategy == StrategyWeightedScore {
+		result.RiskScore = 0
+		if weights > 0 {
+			result.RiskScore = weightedSum / weights
+		}
+		switch {
+		case result.RiskScore >= e.opts.DeclineScore:
+			result.Decision = domain.DecisionDecline
+		case result.RiskScore >= e.opts.ReviewScore:
+			result.Decision = domain.DecisionReview
+		default:
+			result.Decision = domain.DecisionAllow
+		}
+	}
+
+	result.IsFraudulent = result.Decision == domain.DecisionDecline
+	result.Reason = strings.Join(reasons, "; ")
+	// The member explanations are in the contributions.
+	result.Explanation = &domain.FraudExplanation{Engine: result.EngineVersion, Decision: result.Decision, Score: result.RiskScore}
+	if e.opts.Strategy == StrategyWeightedScore {
+		result.Explanation.ReviewThreshold = e.opts.ReviewScore
+		result.Explanation.DeclineThreshold = e.opts.DeclineScore
+	}
+	return result, nil
+}
+
+// severity orders decisions from the least to the most restrictive.
+func severity(d domain.FraudDecision) int {
+	switch d {
+	case domain.DecisionDecline:
+		return 2
+	case domain.DecisionReview:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package antifraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubEngine struct {
+	result domain.FraudResult
+	err    error
+	delay  time.Duration
+	calls  int
+}
+
+func (e *stubEngine) CheckTransaction(ctx context.Context, _ domain.Transaction) (domain.FraudResult, error) {
+	e.calls++
+	select {
+	case <-time.After(e.delay):
+	case <-ctx.Done():
+		return domain.FraudResult{}, ctx.Err()
+	}
+	return e.result, e.err
+}
+
+func TestCompositeEngine_AnyFraudIgnoresFailedMember(t *testing.T) {
+	engine := NewCompositeEngine([]CompositeMember{
+		{Name: "rules", Engine: &stubEngine{result: domain.FraudResult{Decision: domain.DecisionReview, RiskScore: 0.6, TriggeredRules: []string{"night"}}}},
+		{Name: "scorer", Engine: &stubEngine{err: errors.New("connection refused")}, Optional: true},
+		{Name: "slow", Engine: &stubEngine{delay: time.Second}, Timeout: 10 * time.Millisecond, Optional: true},
+	}, CompositeOptions{Parallel: true, Strategy: StrategyAnyFraud})
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionReview, result.Decision)
+	assert.Equal(t, 0.6, result.RiskScore)
+	assert.Equal(t, []string{"rules:night"}, result.TriggeredRules)
+	require.Len(t, result.Contributions, 3)
+	assert.Equal(t, "connection refused", result.Contributions[1].Error)
+	assert.Contains(t, result.Contributions[2].Error, "deadline exceeded")
+}
+
+func TestCompositeEngine_FailsWhenRequiredMemberFails(t *testing.T) {
+	engine := NewCompositeEngine([]CompositeMember{
+		{Name: "blocklist", Engine: &stubEngine{err: errors.New("redis down")}},
+		{Name: "rules", Engine: &stubEngine{result: domain.FraudResult{Decision: domain.DecisionAllow}}},
+	}, CompositeOptions{Parallel: true, Strategy: StrategyAnyFraud})
+
+	_, err := engine.CheckTransaction(context.Background(), domain.Transaction{})
+
+	assert.ErrorContains(t, err, "blocklist: redis down")
+}
+
+func TestCompositeEngine_ShortCircuitSkipsRemainingMembers(t *testing.T) {
+	decliner := &stubEngine{result: domain.FraudResult{Decision: domain.DecisionDecline, RiskScore: 1}}
+	next := &stubEngine{}
+	engine := NewCompositeEngine([]CompositeMember{
+		{Name: "blocklist", Engine: decliner},
+		{Name: "scorer", Engine: next},
+	}, CompositeOptions{Strategy: StrategyShortCircuit})
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{})
+
+	require.NoError(t, err)
+	assert.True(t, result.IsFraudulent)
+	assert.Equal(t, 0, next.calls)
+	assert.True(t, result.Contributions[1].Skipped)
+}
+
+func TestCompositeEngine_WeightedScore(t *testing.T) {
+	engine := NewCompositeEngine([]CompositeMember{
+		{Name: "rules", Engine: &stubEngine{result: domain.FraudResult{Decision: domain.DecisionDecline, RiskScore: 1}}, Weight: 1},
+		{Name: "model", Engine: &stubEngine{result: domain.FraudResult{Decision: domain.DecisionAllow, RiskScore: 0.1}}, Weight: 3},
+	}, CompositeOptions{Parallel: true, Strategy: StrategyWeightedScore, ReviewScore: 0.3, DeclineScore: 0.9})
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{})
+
+	require.NoError(t, err)
+	assert.InDelta(t, 0.325, result.RiskScore, 1e-9)
+	assert.Equal(t, domain.DecisionReview, result.Decision)
+}
+
+func TestCompositeEngine_FailsWhenNoMemberEvaluates(t *testing.T) {
+	engine := NewCompositeEngine([]CompositeMember{
+		{Name: "rules", Engine: &stubEngine{err: errors.New("redis down")}},
+	}, CompositeOptions{Strategy: StrategyAnyFraud})
+
+	_, err := engine.CheckTransaction(context.Background(), domain.Transaction{})
+
+	assert.ErrorContains(t, err, "rules: redis down")
+}
@@ -0,0 +1,13 @@
+package antifraud
+
+import "payment-processing-system/internal/core/domain"
+
+// explanationOf returns the explanation of a result a wrapping engine is about to amend,
+// building it from the result first if the wrapped engine gave none. Call it before changing the result.
+func explanationOf(result *domain.FraudResult) *domain.FraudExplanation {
+	if result.Explanation == nil {
+		e := result.Explain()
+		result.Explanation = &e
+	}
+	return result.Explanation
+}
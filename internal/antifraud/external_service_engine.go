@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -29,26 +30,23 @@ func NewExternalServiceRuleEngine(scorerURL string) *ExternalServiceRuleEngine {
 }
 
 // CheckTransaction  - implements the verification logic through an external call.
-func (e *ExternalServiceRuleEngine) CheckTransaction(tx domain.Transaction) domain.FraudResult {
+func (e *ExternalServiceRuleEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
 	// 1. Packing the transaction into JSON for sending
 	requestBody, err := json.Marshal(tx)
 	if err != nil {
-		log.Printf("ERROR: Failed to marshal transaction for external service: %v", err)
-		return domain.FraudResult{IsFraudulent: false, Reason: ""}
+		return domain.FraudResult{}, fmt.Errorf("failed to marshal transaction for external service: %w", err)
 	}
 
 	// 2. Create and send an HTTP request
-	req, err := http.NewRequestWithContext(context.Background(), "POST", e.scorerURL, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", e.scorerURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		log.Printf("ERROR: Failed to create request for external service: %v", err)
-		return domain.FraudResult{IsFraudulent: false, Reason: ""}
+		return domain.FraudResult{}, fmt.Errorf("failed to create request for external service: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		log.Printf("ERROR: External fraud scoring service call failed: %v", err)
-		return domain.FraudResult{IsFraudulent: false, Reason: ""}
+		return domain.FraudResult{}, fmt.Errorf("external fraud scoring service call failed: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -57,16 +55,25 @@ func (e *ExternalServiceRuleEngine) CheckTransaction(tx domain.Transaction) doma
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("ERROR: External fraud scoring service returned non-200 status: %s", resp.Status)
-		return domain.FraudResult{IsFraudulent: false, Reason: ""}
+		return domain.FraudResult{}, fmt.Errorf("external fraud scoring service returned non-200 status: %s", resp.Status)
 	}
 
 	// 3. Unpacking the answer
 	var result domain.FraudResult
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Printf("ERROR: Failed to decode response from external service: %v", err)
-		return domain.FraudResult{IsFraudulent: false, Reason: ""}
+		return domain.FraudResult{}, fmt.Errorf("failed to decode response from external service: %w", err)
 	}
 
-	return result
+	// Older scorers only answer is_fraudulent.
+	if result.Decision == "" {
+		result.Decision = domain.DecisionAllow
+		if result.IsFraudulent {
+			result.Decision = domain.DecisionDecline
+		}
+	}
+	if result.EngineVersion == "" {
+		result.EngineVersion = "external"
+	}
+
+	return result, nil
 }
@@ -0,0 +1,162 @@
+package antifraud
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	redisadapter "payment-processing-system/internal/adapters/storage/redis"
+	"payment-processing-system/internal/antifraud/features"
+	"payment-processing-system/internal/config"
+	"payment-processing-system/internal/core/domain"
+)
+
+// NewEngineFromConfig builds the engine described by cfg: the composite engine when it lists engines,
+// the rule engine otherwise, followed by the ring checks and behind the list checks when they are enabled.
+// Rule files are watched for changes until ctx is cancelled.
+func NewEngineFromConfig(ctx context.Context, rdb *redis.Client, cfg config.AntiFraudConfig, logger *slog.Logger) (domain.FraudRuleEngine, error) {
+	engine, err := newEngine(ctx, rdb, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Rings.Enabled {
+		graph := redisadapter.NewRingGraphAdapter(rdb, cfg.Rings.KeyPrefix, cfg.Rings.HubCards,
+			time.Duration(cfg.Rings.WindowDays)*24*time.Hour)
+		engine = NewRingEngine(graph, engine, RingOptions{
+			MaxClusterCards:   cfg.Rings.MaxClusterCards,
+			MaxAttributeCards: cfg.Rings.MaxAttributeCards,
+			Decline:           cfg.Rings.Action == "decline",
+		})
+	}
+	if !cfg.Lists.Enabled {
+		return engine, nil
+	}
+	return NewListEngine(redisadapter.NewListCacheAdapter(rdb, cfg.Lists.KeyPrefix), engine), nil
+}
+
+// NewFeatureRegistryFromConfig loads the declared features; it returns nil when no feature file is configured.
+func NewFeatureRegistryFromConfig(rdb *redis.Client, cfg config.FeaturesConfig) (*features.Registry, error) {
+	if cfg.File == "" {
+		return nil, nil
+	}
+	defs, err := features.LoadFile(cfg.File)
+	if err != nil {
+		return nil, err
+	}
+	retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+	return features.NewRegistry(defs, redisadapter.NewFeatureStoreAdapter(rdb, cfg.KeyPrefix, retention)), nil
+}
+
+func newEngine(ctx context.Context, rdb *redis.Client, cfg config.AntiFraudConfig, logger *slog.Logger) (domain.FraudRuleEngine, error) {
+	registry, err := NewFeatureRegistryFromConfig(rdb, cfg.Features)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Composite.Engines) == 0 {
+		return newRuleEngine(ctx, rdb, cfg, registry, logger)
+	}
+
+	members := make([]CompositeMember, 0, len(cfg.Composite.Engines))
+	for _, ec := range cfg.Composite.Engines {
+		var engine domain.FraudRuleEngine
+		switch ec.Type {
+		case "rules":
+			var err error
+			if engine, err = newRuleEngine(ctx, rdb, cfg, registry, logger); err != nil {
+				return nil, fmt.Errorf("engine %s: %w", ec.Name, err)
+			}
+		case "model":
+			var err error
+			if engine, err = newModelEngine(ctx, rdb, cfg, ec.ModelFile, registry, logger.With("engine", ec.Name)); err != nil {
+				return nil, fmt.Errorf("engine %s: %w", ec.Name, err)
+			}
+		case "external":
+			engine = NewExternalServiceRuleEngine(ec.URL)
+		default:
+			return nil, fmt.Errorf("engine %s: unknown type %q", ec.Name, ec.Type)
+		}
+		members = append(members, CompositeMember{
+			Name:     ec.Name,
+			Engine:   engine,
+			Timeout:  time.Duration(ec.TimeoutMs) * time.Millisecond,
+			Weight:   ec.Weight,
+			Optional: ec.Optional,
+		})
+	}
+
+	return NewCompositeEngine(members, CompositeOptions{
+		Parallel:     cfg.Composite.Mode == "parallel",
+		Strategy:     CompositeStrategy(cfg.Composite.Strategy),
+		ReviewScore:  cfg.Composite.ReviewScore,
+		DeclineScore: cfg.Composite.DeclineScore,
+	}), nil
+}
+
+// newRuleEngine returns the RulesEngine when a rule file is configured and the built-in CachingRuleEngine otherwise.
+func newRuleEngine(ctx context.Context, rdb *redis.Client, cfg config.AntiFraudConfig, registry *features.Registry, logger *slog.Logger) (domain.FraudRuleEngine, error) {
+	velocity := redisadapter.NewVelocityCounterAdapter(rdb, cfg.CounterKeyPrefix)
+	if cfg.RulesFile == "" {
+		return NewCachingRuleEngine(velocity, cfg), nil
+	}
+
+	engine, err := NewRulesEngine(cfg.RulesFile, velocity, registry, logger)
+	if err != nil {
+		return nil, err
+	}
+	go engine.Watch(ctx, time.Duration(cfg.RulesReloadSeconds)*time.Second)
+	return engine, nil
+}
+
+// newModelEngine loads the model at path and watches it for changes. The model shares the velocity counters
+// of the rules: recording the same transaction twice is a no-op.
+func newModelEngine(ctx context.Context, rdb *redis.Client, cfg config.AntiFraudConfig, path string, registry *features.Registry, logger *slog.Logger) (domain.FraudRuleEngine, error) {
+	velocity := redisadapter.NewVelocityCounterAdapter(rdb, cfg.CounterKeyPrefix)
+	engine, err := NewModelEngine(path, velocity, registry, logger)
+	if err != nil {
+		return nil, err
+	}
+	go engine.Watch(ctx, time.Duration(cfg.RulesReloadSeconds)*time.Second)
+	return engine, nil
+}
+
+// NewShadowEnginesFromConfig builds the shadow engines of cfg. Each rules or model engine keeps its own velocity
+// counters, so shadow evaluations never change what the live engine sees. Features are only read and are shared.
+func NewShadowEnginesFromConfig(ctx context.Context, rdb *redis.Client, cfg config.AntiFraudConfig, logger *slog.Logger) ([]ShadowEngine, error) {
+	registry, err := NewFeatureRegistryFromConfig(rdb, cfg.Features)
+	if err != nil {
+		return nil, err
+	}
+	engines := make([]ShadowEngine, 0, len(cfg.Shadow))
+	for _, sc := range cfg.Shadow {
+		var engine domain.FraudRuleEngine
+		switch sc.Type {
+		case "rules":
+			shadowCfg := cfg
+			shadowCfg.RulesFile = sc.RulesFile
+			shadowCfg.CounterKeyPrefix = "shadow_" + sc.Name + "_" + cfg.CounterKeyPrefix
+			var err error
+			if engine, err = newRuleEngine(ctx, rdb, shadowCfg, registry, logger.With("shadow", sc.Name)); err != nil {
+				return nil, fmt.Errorf("shadow engine %s: %w", sc.Name, err)
+			}
+		case "model":
+			shadowCfg := cfg
+			shadowCfg.CounterKeyPrefix = "shadow_" + sc.Name + "_" + cfg.CounterKeyPrefix
+			var err error
+			if engine, err = newModelEngine(ctx, rdb, shadowCfg, sc.ModelFile, registry, logger.With("shadow", sc.Name)); err != nil {
+				return nil, fmt.Errorf("shadow engine %s: %w", sc.Name, err)
+			}
+		case "external":
+			engine = NewExternalServiceRuleEngine(sc.URL)
+		default:
+			return nil, fmt.Errorf("shadow engine %s: unknown type %q", sc.Name, sc.Type)
+		}
+		engines = append(engines, ShadowEngine{
+			Name:    sc.Name,
+			Engine:  engine,
+			Timeout: time.Duration(sc.TimeoutMs) * time.Millisecond,
+		})
+	}
+	return engines, nil
+}
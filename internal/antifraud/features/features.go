@@ -0,0 +1,179 @@
+// Package features declares the aggregated fraud features and serves them to the fraud engines.
+//
+// Features are declared once in a YAML file (see configs/features.yaml), updated incrementally from the
+// transactions.created stream, read by the rules ("feature(name)") and models ("feature:<name>") in one
+// batched call per transaction, and rebuilt from ClickHouse with Backfill.
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// fileFormat is the YAML layout of a feature file.
+type fileFormat struct {
+	Features []struct {
+		Name      string `yaml:"name"`
+		Entity    string `yaml:"entity"`
+		Aggregate string `yaml:"aggregate"`
+		Field     string `yaml:"field"`
+		Window    string `yaml:"window"`
+	} `yaml:"features"`
+}
+
+// LoadFile reads and validates a feature file.
+func LoadFile(path string) ([]domain.FeatureDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature file: %w", err)
+	}
+	defs, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Parse validates feature definitions. All problems are reported at once, one per line.
+func Parse(data []byte) ([]domain.FeatureDefinition, error) {
+	var f fileFormat
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(f.Features))
+	defs := make([]domain.FeatureDefinition, 0, len(f.Features))
+	for i, ff := range f.Features {
+		d := domain.FeatureDefinition{
+			Name:      ff.Name,
+			Entity:    domain.VelocityDimension(ff.Entity),
+			Aggregate: domain.FeatureAggregate(ff.Aggregate),
+			Field:     domain.VelocityDimension(ff.Field),
+		}
+		if ff.Window != "" {
+			w, err := time.ParseDuration(ff.Window)
+			if err != nil || w <= 0 {
+				errs = append(errs, fmt.Errorf("features[%d]: invalid window %q", i, ff.Window))
+				continue
+			}
+			d.Window = w
+		}
+		if err := d.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("features[%d]: %w", i, err))
+			continue
+		}
+		if seen[d.Name] {
+			errs = append(errs, fmt.Errorf("features[%d]: duplicate name %q", i, d.Name))
+			continue
+		}
+		seen[d.Name] = true
+		defs = append(defs, d)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// Registry serves the declared features from a store. A nil *Registry declares no features.
+type Registry struct {
+	defs  map[string]domain.FeatureDefinition
+	all   []domain.FeatureDefinition
+	store ports.FeatureStore
+}
+
+// NewRegistry creates a registry of defs kept in store.
+func NewRegistry(defs []domain.FeatureDefinition, store ports.FeatureStore) *Registry {
+	r := &Registry{defs: make(map[string]domain.FeatureDefinition, len(defs)), all: defs, store: store}
+	for _, d := range defs {
+		r.defs[d.Name] = d
+	}
+	return r
+}
+
+// Definitions returns every declared feature.
+func (r *Registry) Definitions() []domain.FeatureDefinition {
+	if r == nil {
+		return nil
+	}
+	return r.all
+}
+
+// Names returns the names of every declared feature.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.Definitions()))
+	for _, d := range r.Definitions() {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+// Check reports the names that are not declared.
+func (r *Registry) Check(names []string) error {
+	var unknown []string
+	for _, name := range names {
+		if r == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, ok := r.defs[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("undeclared features: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Get returns the named features of tx in one call to the store. Names must have passed Check.
+func (r *Registry) Get(ctx context.Context, tx domain.Transaction, names []string) (domain.FeatureValues, error) {
+	if len(names) == 0 {
+		return domain.FeatureValues{}, nil
+	}
+	defs := make([]domain.FeatureDefinition, 0, len(names))
+	for _, name := range names {
+		d, ok := r.defs[name]
+		if !ok {
+			return nil, fmt.Errorf("undeclared feature %q", name)
+		}
+		defs = append(defs, d)
+	}
+	return r.store.GetFeatures(ctx, defs, tx)
+}
+
+// Update accounts streamed transactions in every declared feature.
+func (r *Registry) Update(ctx context.Context, txs []domain.Transaction) error {
+	return r.store.UpdateFeatures(ctx, r.all, txs)
+}
+
+// Backfill rebuilds the given features from the history since since. The features are reset first;
+// transactions the stream accounts while the backfill runs may be counted twice by lifetime features,
+// so run it right after declaring new features, before their values are relied upon.
+func Backfill(ctx context.Context, history ports.TransactionHistory, store ports.FeatureStore, defs []domain.FeatureDefinition, since time.Time) (int, error) {
+	if err := store.ResetFeatures(ctx, defs); err != nil {
+		return 0, err
+	}
+	total := 0
+	err := history.ScanTransactions(ctx, since, func(batch []domain.Transaction) error {
+		if err := store.BackfillFeatures(ctx, defs, batch); err != nil {
+			return err
+		}
+		total += len(batch)
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,127 @@
+package features
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStore records the definitions it is asked for.
+type stubStore struct {
+	got   [][]domain.FeatureDefinition
+	reset []domain.FeatureDefinition
+	txs   int
+}
+
+func (s *stubStore) GetFeatures(_ context.Context, defs []domain.FeatureDefinition, _ domain.Transaction) (domain.FeatureValues, error) {
+	s.got = append(s.got, defs)
+	values := make(domain.FeatureValues, len(defs))
+	for _, d := range defs {
+		values[d.Name] = 1
+	}
+	return values, nil
+}
+
+func (s *stubStore) UpdateFeatures(_ context.Context, _ []domain.FeatureDefinition, txs []domain.Transaction) error {
+	s.txs += len(txs)
+	return nil
+}
+
+func (s *stubStore) ResetFeatures(_ context.Context, defs []domain.FeatureDefinition) error {
+	s.reset = defs
+	return nil
+}
+
+func (s *stubStore) BackfillFeatures(_ context.Context, _ []domain.FeatureDefinition, txs []domain.Transaction) error {
+	s.txs += len(txs)
+	return nil
+}
+
+type stubHistory struct{ batches [][]domain.Transaction }
+
+func (h stubHistory) ScanTransactions(_ context.Context, _ time.Time, fn func(batch []domain.Transaction) error) error {
+	for _, b := range h.batches {
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const testFeatures = `
+features:
+  - name: card_tx_count
+    entity: card
+    aggregate: count
+  - name: card_distinct_merchants_24h
+    entity: card
+    aggregate: distinct
+    field: merchant
+    window: 24h
+`
+
+func TestParse(t *testing.T) {
+	defs, err := Parse([]byte(testFeatures))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FeatureDefinition{
+		{Name: "card_tx_count", Entity: domain.VelocityCard, Aggregate: domain.FeatureCount},
+		{Name: "card_distinct_merchants_24h", Entity: domain.VelocityCard, Aggregate: domain.FeatureDistinct, Field: domain.VelocityMerchant, Window: 24 * time.Hour},
+	}, defs)
+
+	_, err = Parse([]byte(`
+features:
+  - {name: a, entity: card, aggregate: count}
+  - {name: a, entity: card, aggregate: sum}
+  - {name: b, entity: planet, aggregate: count}
+  - {name: c, entity: card, aggregate: distinct, field: card}
+  - {name: d, entity: card, aggregate: first_seen, window: 1h}
+  - {name: e, entity: card, aggregate: count, window: soon}
+`))
+	require.Error(t, err)
+	for _, want := range []string{
+		`features[1]: duplicate name "a"`,
+		`features[2]: feature b: unknown entity "planet"`,
+		`features[3]: feature c: distinct needs a field other than the entity`,
+		`features[4]: feature d: first_seen has no window`,
+		`features[5]: invalid window "soon"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	defs, err := Parse([]byte(testFeatures))
+	require.NoError(t, err)
+	store := &stubStore{}
+	r := NewRegistry(defs, store)
+
+	assert.NoError(t, r.Check([]string{"card_tx_count"}))
+	assert.EqualError(t, r.Check([]string{"nope", "card_tx_count", "also_nope"}), "undeclared features: also_nope, nope")
+	var none *Registry
+	assert.EqualError(t, none.Check([]string{"card_tx_count"}), "undeclared features: card_tx_count")
+	assert.NoError(t, none.Check(nil))
+
+	values, err := r.Get(context.Background(), domain.Transaction{}, []string{"card_tx_count", "card_distinct_merchants_24h"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.FeatureValues{"card_tx_count": 1, "card_distinct_merchants_24h": 1}, values)
+	assert.Len(t, store.got, 1, "features are read in one call")
+}
+
+func TestBackfill(t *testing.T) {
+	defs, err := Parse([]byte(testFeatures))
+	require.NoError(t, err)
+	store := &stubStore{}
+	history := stubHistory{batches: [][]domain.Transaction{make([]domain.Transaction, 3), make([]domain.Transaction, 2)}}
+
+	n, err := Backfill(context.Background(), history, store, defs, time.Now().Add(-time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 5, store.txs)
+	assert.Equal(t, defs, store.reset)
+}
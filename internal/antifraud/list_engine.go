@@ -0,0 +1,152 @@
+package antifraud
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// ListEngine implements the FraudRuleEngine interface by consulting the blocklists and allowlists
+// before the wrapped engine. A blocklist hit declines the transaction without running the wrapped engine.
+// An allowlist hit on a card or an email allows it whatever the wrapped engine decides; a hit on a kind shared
+// by many buyers (IP, device, BIN) only turns a decline into a review. The hits are reported as triggered rules
+// "blocklist_<kind>" and "allowlist_<kind>".
+type ListEngine struct {
+	lists ports.ListMatcher
+	next  domain.FraudRuleEngine
+}
+
+// NewListEngine wraps next with the list checks.
+func NewListEngine(lists ports.ListMatcher, next domain.FraudRuleEngine) *ListEngine {
+	return &ListEngine{lists: lists, next: next}
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (e *ListEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	hits, err := e.lists.MatchLists(ctx, listSubjects(tx))
+	if err != nil {
+		return domain.FraudResult{}, err
+	}
+
+	var blocked, allowed []domain.ListEntry
+	for _, h := range hits {
+		if h.List == domain.ListBlock {
+			blocked = append(blocked, h)
+		} else {
+			allowed = append(allowed, h)
+		}
+	}
+
+	if len(blocked) > 0 {
+		result := domain.FraudResult{
+			IsFraudulent:   true,
+			Reason:         listReason("blocklisted", blocked),
+			RiskScore:      1,
+			TriggeredRules: listRules(blocked),
+			Decision:       domain.DecisionDecline,
+			EngineVersion:  "lists",
+		}
+		result.Explanation = &domain.FraudExplanation{Engine: result.EngineVersion, Decision: result.Decision, Score: 1}
+		explainLists(result.Explanation, blocked, "decline")
+		return result, nil
+	}
+
+	result, err := e.next.CheckTransaction(ctx, tx)
+	if err != nil || len(allowed) == 0 {
+		return result, err
+	}
+
+	// The wrapped engine still ran so its counters stay complete; only its decision is overridden.
+	decision, action := domain.DecisionAllow, "allow"
+	if !slices.ContainsFunc(allowed, func(e domain.ListEntry) bool { return e.Kind.NamesBuyer() }) {
+		decision, action = result.Decision, "review"
+		if severity(decision) > severity(domain.DecisionReview) {
+			decision = domain.DecisionReview
+		}
+	}
+	explanation := explanationOf(&result)
+	switch {
+	case result.Decision != decision:
+		result.Reason = listReason("allowlisted", allowed) + ", overrides: " + result.Reason
+	case decision == domain.DecisionAllow:
+		result.Reason = listReason("allowlisted", allowed)
+	}
+	result.TriggeredRules = append(result.TriggeredRules, listRules(allowed)...)
+	result.Decision = decision
+	result.IsFraudulent = decision == domain.DecisionDecline
+	explanation.Decision = decision
+	explainLists(explanation, allowed, action)
+	return result, nil
+}
+
+// explainLists adds the matched list entries to the explanation, one rule per list and kind.
+func explainLists(e *domain.FraudExplanation, entries []domain.ListEntry, action string) {
+	if e.Inputs == nil {
+		e.Inputs = make(map[string]any)
+	}
+	for _, rule := range listRules(entries) {
+		ev := domain.RuleEvaluation{Name: rule, Matched: true, Action: action}
+		for _, entry := range entries {
+			if string(entry.List)+"list_"+string(entry.Kind) != rule {
+				continue
+			}
+			input := "list:" + string(entry.Kind)
+			if _, ok := e.Inputs[input]; !ok {
+				e.Inputs[input] = entry.Value
+				ev.Inputs = append(ev.Inputs, input)
+			}
+			if entry.Reason != "" && ev.Description == "" {
+				ev.Description = entry.Reason
+			}
+		}
+		e.Rules = append(e.Rules, ev)
+	}
+}
+
+// listSubjects returns the transaction attributes that can be listed, skipping the ones it does not carry.
+// BIN entries may be 6 to 8 digits long, so every prefix of that length is looked up.
+func listSubjects(tx domain.Transaction) []domain.ListSubject {
+	subjects := []domain.ListSubject{{Kind: domain.ListCard, Value: tx.CardNumberHash}}
+	for _, s := range []domain.ListSubject{
+		{Kind: domain.ListIP, Value: tx.IP},
+		{Kind: domain.ListEmail, Value: tx.EmailHash},
+		{Kind: domain.ListDevice, Value: tx.DeviceID},
+	} {
+		if s.Value != "" {
+			subjects = append(subjects, s)
+		}
+	}
+	for n := 6; n <= len(tx.BIN) && n <= 8; n++ {
+		subjects = append(subjects, domain.ListSubject{Kind: domain.ListBIN, Value: tx.BIN[:n]})
+	}
+	return subjects
+}
+
+func listRules(entries []domain.ListEntry) []string {
+	seen := make(map[string]bool, len(entries))
+	var rules []string
+	for _, e := range entries {
+		rule := string(e.List) + "list_" + string(e.Kind)
+		if !seen[rule] {
+			seen[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func listReason(verb string, entries []domain.ListEntry) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		part := fmt.Sprintf("%s %s %s", e.Kind, e.Value, verb)
+		if e.Reason != "" {
+			part += " (" + e.Reason + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,98 @@
+package antifraud
+
+import (
+	"context"
+	"testing"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubListMatcher struct {
+	hits     []domain.ListEntry
+	subjects []domain.ListSubject
+}
+
+func (m *stubListMatcher) MatchLists(_ context.Context, subjects []domain.ListSubject) ([]domain.ListEntry, error) {
+	m.subjects = subjects
+	return m.hits, nil
+}
+
+func TestListEngine_BlocklistDeclinesWithoutRunningEngine(t *testing.T) {
+	lists := &stubListMatcher{hits: []domain.ListEntry{
+		{List: domain.ListBlock, Kind: domain.ListBIN, Value: "411111", Reason: "compromised issuer"},
+		{List: domain.ListAllow, Kind: domain.ListCard, Value: "abc"},
+	}}
+	next := &stubEngine{}
+	engine := NewListEngine(lists, next)
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{CardNumberHash: "abc", BIN: "41111111"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionDecline, result.Decision)
+	assert.Equal(t, []string{"blocklist_bin"}, result.TriggeredRules)
+	assert.Equal(t, "bin 411111 blocklisted (compromised issuer)", result.Reason)
+	assert.Equal(t, 0, next.calls)
+	assert.Equal(t, []domain.ListSubject{
+		{Kind: domain.ListCard, Value: "abc"},
+		{Kind: domain.ListBIN, Value: "411111"},
+		{Kind: domain.ListBIN, Value: "4111111"},
+		{Kind: domain.ListBIN, Value: "41111111"},
+	}, lists.subjects)
+}
+
+func TestListEngine_AllowlistOverridesDecision(t *testing.T) {
+	lists := &stubListMatcher{hits: []domain.ListEntry{{List: domain.ListAllow, Kind: domain.ListCard, Value: "abc"}}}
+	next := &stubEngine{result: domain.FraudResult{
+		IsFraudulent: true, Reason: "Amount exceeds threshold", RiskScore: 1,
+		TriggeredRules: []string{"amount_threshold"}, Decision: domain.DecisionDecline,
+	}}
+	engine := NewListEngine(lists, next)
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{CardNumberHash: "abc"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionAllow, result.Decision)
+	assert.False(t, result.IsFraudulent)
+	assert.Equal(t, []string{"amount_threshold", "allowlist_card"}, result.TriggeredRules)
+	assert.Equal(t, "card abc allowlisted, overrides: Amount exceeds threshold", result.Reason)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestListEngine_SharedAllowlistEntryOnlyCapsAtReview(t *testing.T) {
+	lists := &stubListMatcher{hits: []domain.ListEntry{{List: domain.ListAllow, Kind: domain.ListBIN, Value: "411111"}}}
+	next := &stubEngine{result: domain.FraudResult{
+		IsFraudulent: true, Reason: "Too many transactions", RiskScore: 1,
+		TriggeredRules: []string{"velocity_card"}, Decision: domain.DecisionDecline,
+	}}
+	engine := NewListEngine(lists, next)
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{CardNumberHash: "abc", BIN: "411111"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionReview, result.Decision)
+	assert.False(t, result.IsFraudulent)
+	assert.Equal(t, []string{"velocity_card", "allowlist_bin"}, result.TriggeredRules)
+	assert.Equal(t, "bin 411111 allowlisted, overrides: Too many transactions", result.Reason)
+}
+
+func TestListEngine_LooksUpBuyerSignals(t *testing.T) {
+	lists := &stubListMatcher{}
+	engine := NewListEngine(lists, &stubEngine{})
+
+	_, err := engine.CheckTransaction(context.Background(), domain.Transaction{
+		CardNumberHash: "abc", IP: "203.0.113.7", EmailHash: domain.HashPII("buyer@example.com"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ListSubject{
+		{Kind: domain.ListCard, Value: "abc"},
+		{Kind: domain.ListIP, Value: "203.0.113.7"},
+		{Kind: domain.ListEmail, Value: domain.HashPII("buyer@example.com")},
+	}, lists.subjects)
+	// Email entries are stored in clear text and matched by hash.
+	entry := domain.ListEntry{List: domain.ListBlock, Kind: domain.ListEmail, Value: "buyer@example.com"}
+	assert.True(t, entry.Matches(lists.subjects[2].Value))
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"payment-processing-system/internal/antifraud/rules"
+	"payment-processing-system/internal/core/domain"
+)
+
+// Feature is one model input computed from the transaction and its velocity aggregates.
+//
+// Supported names:
+//
+//	amount, log_amount, hour, weekday                 - as in the rule language
+//	has_email, has_phone, has_ip, has_device          - 1 when the buyer signal was sent, 0 otherwise
+//	ip_bin_country_mismatch, address_mismatch         - 1 or 0; unknown when a side is missing
+//	velocity_count:<dimension>:<window>               - e.g. velocity_count:card:1h
+//	velocity_amount:<dimension>:<window>
+//	feature:<name>                                    - a feature declared in the feature file
+//
+// Velocity and declared features are unknown when the transaction does not carry the dimension (entity).
+type Feature struct {
+	Name string
+
+	stored   string // declared feature name
+	velocity *rules.VelocitySpec
+	agg      rules.Aggregate
+	extract  func(tx domain.Transaction) float64
+}
+
+// unknown marks a missing feature value; trees send it down the "missing" branch.
+var unknown = math.NaN()
+
+var txFeatures = map[string]func(tx domain.Transaction) float64{
+	"amount":     func(tx domain.Transaction) float64 { return tx.Amount },
+	"log_amount": func(tx domain.Transaction) float64 { return math.Log1p(math.Max(tx.Amount, 0)) },
+	"hour":       func(tx domain.Transaction) float64 { return float64(tx.CreatedAt.UTC().Hour()) },
+	"weekday": func(tx domain.Transaction) float64 {
+		wd := tx.CreatedAt.UTC().Weekday()
+		if wd == time.Sunday {
+			return 7
+		}
+		return float64(wd)
+	},
+	"has_email":  func(tx domain.Transaction) float64 { return indicator(tx.EmailHash != "") },
+	"has_phone":  func(tx domain.Transaction) float64 { return indicator(tx.PhoneHash != "") },
+	"has_ip":     func(tx domain.Transaction) float64 { return indicator(tx.IP != "") },
+	"has_device": func(tx domain.Transaction) float64 { return indicator(tx.DeviceID != "") },
+	"ip_bin_country_mismatch": func(tx domain.Transaction) float64 {
+		if tx.IPCountry == "" || tx.BINCountry == "" {
+			return unknown
+		}
+		return indicator(tx.IPCountry != tx.BINCountry)
+	},
+	"address_mismatch": func(tx domain.Transaction) float64 {
+		b, s := tx.BillingAddress.Hash, tx.ShippingAddress.Hash
+		if b == "" || s == "" {
+			return unknown
+		}
+		return indicator(b != s)
+	},
+}
+
+// ParseFeature validates a feature name.
+func ParseFeature(name string) (Feature, error) {
+	if extract, ok := txFeatures[name]; ok {
+		return Feature{Name: name, extract: extract}, nil
+	}
+
+	fn, rest, ok := strings.Cut(name, ":")
+	var agg rules.Aggregate
+	switch fn {
+	case "feature":
+		if rest == "" {
+			return Feature{}, fmt.Errorf("feature %q: expected feature:<name>", name)
+		}
+		return Feature{Name: name, stored: rest}, nil
+	case "velocity_count":
+		agg = rules.AggregateCount
+	case "velocity_amount":
+		agg = rules.AggregateAmount
+	default:
+		return Feature{}, fmt.Errorf("unknown feature %q", name)
+	}
+	dim, window, ok2 := strings.Cut(rest, ":")
+	if !ok || !ok2 {
+		return Feature{}, fmt.Errorf("feature %q: expected %s:<dimension>:<window>", name, fn)
+	}
+	d := domain.VelocityDimension(dim)
+	if !d.Valid() {
+		return Feature{}, fmt.Errorf("feature %q: unknown dimension %q", name, dim)
+	}
+	w, err := time.ParseDuration(window)
+	if err != nil || w <= 0 {
+		return Feature{}, fmt.Errorf("feature %q: invalid window %q", name, window)
+	}
+	return Feature{Name: name, velocity: &rules.VelocitySpec{Dimension: d, Window: w}, agg: agg}, nil
+}
+
+func (f Feature) value(env *rules.Env) float64 {
+	if f.stored != "" {
+		v, ok := env.Features[f.stored]
+		if !ok {
+			return unknown
+		}
+		return v
+	}
+	if f.velocity == nil {
+		return f.extract(env.Transaction)
+	}
+	v, ok := env.Velocity[*f.velocity]
+	if !ok {
+		return unknown
+	}
+	if f.agg == rules.AggregateCount {
+		return float64(v.Count)
+	}
+	return v.Amount
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
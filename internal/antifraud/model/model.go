@@ -0,0 +1,302 @@
+// Package model implements in-process scoring with gradient-boosted tree models.
+//
+// A model file is JSON: the trees are the output of XGBoost's
+// Booster.dump_model(..., dump_format="json"), wrapped with the metadata the engine needs:
+//
+//	{
+//	  "version": "2025-06-01",
+//	  "objective": "binary:logistic",
+//	  "base_score": 0.5,
+//	  "review_score": 0.5,
+//	  "decline_score": 0.9,
+//	  "features": ["amount", "hour", "velocity_count:card:1h"],
+//	  "trees": [{"nodeid": 0, "split": "amount", "split_condition": 500, "yes": 1, "no": 2, "missing": 1,
+//	             "children": [{"nodeid": 1, "leaf": -0.2}, {"nodeid": 2, "leaf": 0.4}]}]
+//	}
+//
+// Splits name a feature either directly or as "f<index>" into features, as XGBoost does without a feature map.
+// A sample goes to "yes" when its value is below split_condition and to "missing" when the value is unknown.
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"payment-processing-system/internal/antifraud/rules"
+)
+
+// Model is a validated tree ensemble ready for scoring. It is immutable.
+type Model struct {
+	// Version is the declared model version followed by a digest of the file, e.g. "2025-06-01@3f2a9c1b",
+	// so two files with the same declared version are still told apart in fraud reports.
+	Version      string
+	ReviewScore  float64
+	DeclineScore float64
+	Features     []Feature
+
+	baseMargin float64
+	trees      [][]treeNode
+	velocity   []rules.VelocitySpec
+	stored     []string
+}
+
+// treeNode is a flattened tree node. Leaves have feature -1.
+type treeNode struct {
+	feature   int
+	threshold float64
+	yes       int
+	no        int
+	missing   int
+	value     float64
+}
+
+// fileFormat is the JSON layout of a model file.
+type fileFormat struct {
+	Version      string     `json:"version"`
+	Objective    string     `json:"objective"`
+	BaseScore    *float64   `json:"base_score"`
+	ReviewScore  *float64   `json:"review_score"`
+	DeclineScore *float64   `json:"decline_score"`
+	Features     []string   `json:"features"`
+	Trees        []dumpNode `json:"trees"`
+}
+
+// dumpNode is a node of an XGBoost JSON dump.
+type dumpNode struct {
+	NodeID         int        `json:"nodeid"`
+	Split          string     `json:"split"`
+	SplitCondition float64    `json:"split_condition"`
+	Yes            int        `json:"yes"`
+	No             int        `json:"no"`
+	Missing        int        `json:"missing"`
+	Leaf           *float64   `json:"leaf"`
+	Children       []dumpNode `json:"children"`
+}
+
+// LoadFile reads and validates a model file.
+func LoadFile(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse validates a model.
+func Parse(data []byte) (*Model, error) {
+	// Unknown fields are ignored: dumps carry statistics such as depth, gain and cover.
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if f.Version == "" {
+		return nil, errors.New("version is required")
+	}
+	if f.Objective != "" && f.Objective != "binary:logistic" {
+		return nil, fmt.Errorf("unsupported objective %q, only binary:logistic is supported", f.Objective)
+	}
+	digest := sha256.Sum256(data)
+	m := &Model{
+		Version:      fmt.Sprintf("%s@%x", f.Version, digest[:4]),
+		ReviewScore:  0.5,
+		DeclineScore: 0.9,
+	}
+	if f.ReviewScore != nil {
+		m.ReviewScore = *f.ReviewScore
+	}
+	if f.DeclineScore != nil {
+		m.DeclineScore = *f.DeclineScore
+	}
+	if m.ReviewScore <= 0 || m.ReviewScore > m.DeclineScore || m.DeclineScore > 1 {
+		return nil, fmt.Errorf("thresholds must satisfy 0 < review_score <= decline_score <= 1, got %g and %g", m.ReviewScore, m.DeclineScore)
+	}
+	base := 0.5
+	if f.BaseScore != nil {
+		base = *f.BaseScore
+	}
+	if base <= 0 || base >= 1 {
+		return nil, fmt.Errorf("base_score must be within (0, 1), got %g", base)
+	}
+	m.baseMargin = math.Log(base / (1 - base))
+
+	if len(f.Features) == 0 {
+		return nil, errors.New("features are required")
+	}
+	index := make(map[string]int, len(f.Features))
+	specs := make(map[rules.VelocitySpec]bool)
+	for i, name := range f.Features {
+		feature, err := ParseFeature(name)
+		if err != nil {
+			return nil, fmt.Errorf("features[%d]: %w", i, err)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("features[%d]: duplicate feature %q", i, name)
+		}
+		index[name] = i
+		m.Features = append(m.Features, feature)
+		if feature.velocity != nil && !specs[*feature.velocity] {
+			specs[*feature.velocity] = true
+			m.velocity = append(m.velocity, *feature.velocity)
+		}
+		if feature.stored != "" {
+			m.stored = append(m.stored, feature.stored)
+		}
+	}
+
+	if len(f.Trees) == 0 {
+		return nil, errors.New("trees are required")
+	}
+	for i, root := range f.Trees {
+		tree, err := flatten(root, index)
+		if err != nil {
+			return nil, fmt.Errorf("trees[%d]: %w", i, err)
+		}
+		m.trees = append(m.trees, tree)
+	}
+	return m, nil
+}
+
+// VelocitySpecs returns the velocity aggregates the features need.
+func (m *Model) VelocitySpecs() []rules.VelocitySpec {
+	return m.velocity
+}
+
+// FeatureNames returns the declared features the model reads.
+func (m *Model) FeatureNames() []string {
+	return m.stored
+}
+
+// Score returns the fraud probability of the transaction in env.
+func (m *Model) Score(env *rules.Env) float64 {
+	return m.Predict(m.Vector(env))
+}
+
+// Vector computes the feature values of the transaction in env, in model order. Unknown values are NaN.
+func (m *Model) Vector(env *rules.Env) []float64 {
+	x := make([]float64, len(m.Features))
+	for i, f := range m.Features {
+		x[i] = f.value(env)
+	}
+	return x
+}
+
+// Inputs names the values of a feature vector for explanations. Unknown values are nil.
+func (m *Model) Inputs(x []float64) map[string]any {
+	inputs := make(map[string]any, len(x))
+	for i, f := range m.Features {
+		if math.IsNaN(x[i]) {
+			inputs[f.Name] = nil
+		} else {
+			inputs[f.Name] = x[i]
+		}
+	}
+	return inputs
+}
+
+// Predict returns the probability for a feature vector.
+func (m *Model) Predict(x []float64) float64 {
+	margin := m.baseMargin
+	for _, tree := range m.trees {
+		margin += predictTree(tree, x)
+	}
+	return 1 / (1 + math.Exp(-margin))
+}
+
+func predictTree(tree []treeNode, x []float64) float64 {
+	i := 0
+	for {
+		n := tree[i]
+		if n.feature < 0 {
+			return n.value
+		}
+		switch v := x[n.feature]; {
+		case math.IsNaN(v):
+			i = n.missing
+		case v < n.threshold:
+			i = n.yes
+		default:
+			i = n.no
+		}
+	}
+}
+
+// flatten converts a dumped tree into a slice with the root first. Branch targets must be direct children,
+// which also rules out cycles.
+func flatten(root dumpNode, features map[string]int) ([]treeNode, error) {
+	var tree []treeNode
+	var add func(n dumpNode) (int, error)
+	add = func(n dumpNode) (int, error) {
+		pos := len(tree)
+		tree = append(tree, treeNode{feature: -1})
+		if n.Leaf != nil {
+			if len(n.Children) > 0 {
+				return 0, fmt.Errorf("node %d: a leaf has no children", n.NodeID)
+			}
+			tree[pos].value = *n.Leaf
+			return pos, nil
+		}
+
+		feature, err := splitFeature(n.Split, features)
+		if err != nil {
+			return 0, fmt.Errorf("node %d: %w", n.NodeID, err)
+		}
+		children := make(map[int]int, len(n.Children))
+		for _, c := range n.Children {
+			if _, dup := children[c.NodeID]; dup {
+				return 0, fmt.Errorf("node %d: duplicate child %d", n.NodeID, c.NodeID)
+			}
+			childPos, err := add(c)
+			if err != nil {
+				return 0, err
+			}
+			children[c.NodeID] = childPos
+		}
+		target := func(id int) (int, error) {
+			p, ok := children[id]
+			if !ok {
+				return 0, fmt.Errorf("node %d: branch to %d which is not a child", n.NodeID, id)
+			}
+			return p, nil
+		}
+		node := treeNode{feature: feature, threshold: n.SplitCondition}
+		if node.yes, err = target(n.Yes); err != nil {
+			return 0, err
+		}
+		if node.no, err = target(n.No); err != nil {
+			return 0, err
+		}
+		if node.missing, err = target(n.Missing); err != nil {
+			return 0, err
+		}
+		tree[pos] = node
+		return pos, nil
+	}
+
+	if _, err := add(root); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func splitFeature(split string, features map[string]int) (int, error) {
+	if i, ok := features[split]; ok {
+		return i, nil
+	}
+	if rest, ok := strings.CutPrefix(split, "f"); ok {
+		if i, err := strconv.Atoi(rest); err == nil && i >= 0 && i < len(features) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown split feature %q", split)
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/antifraud/rules"
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModel has one tree on the amount and one on the card velocity referenced as "f1", as in a dump without a feature map.
+const testModel = `{
+  "version": "test",
+  "features": ["amount", "velocity_count:card:1h"],
+  "trees": [
+    {"nodeid": 0, "depth": 0, "split": "amount", "split_condition": 500, "yes": 1, "no": 2, "missing": 1, "gain": 12.5,
+     "children": [{"nodeid": 1, "leaf": -1}, {"nodeid": 2, "leaf": 1}]},
+    {"nodeid": 0, "split": "f1", "split_condition": 3, "yes": 1, "no": 2, "missing": 2,
+     "children": [{"nodeid": 1, "leaf": 0}, {"nodeid": 2, "leaf": 2}]}
+  ]
+}`
+
+func sigmoid(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+
+func TestModel_Score(t *testing.T) {
+	m, err := Parse([]byte(testModel))
+	require.NoError(t, err)
+	assert.Regexp(t, `^test@[0-9a-f]{8}$`, m.Version)
+	hour := rules.VelocitySpec{Dimension: domain.VelocityCard, Window: time.Hour}
+	assert.Equal(t, []rules.VelocitySpec{hour}, m.VelocitySpecs())
+
+	tests := []struct {
+		name     string
+		amount   float64
+		velocity map[rules.VelocitySpec]domain.VelocityAggregate
+		want     float64
+	}{
+		{"low risk", 100, map[rules.VelocitySpec]domain.VelocityAggregate{hour: {Count: 1}}, sigmoid(-1)},
+		{"big amount", 1000, map[rules.VelocitySpec]domain.VelocityAggregate{hour: {Count: 1}}, sigmoid(1)},
+		{"burst", 1000, map[rules.VelocitySpec]domain.VelocityAggregate{hour: {Count: 5}}, sigmoid(3)},
+		{"velocity unknown takes the missing branch", 100, nil, sigmoid(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Score(&rules.Env{Transaction: domain.Transaction{Amount: tt.amount}, Velocity: tt.velocity})
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestParse_RejectsInvalidModels(t *testing.T) {
+	tests := map[string]string{
+		"unknown feature":   `{"version": "x", "features": ["amont"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+		"bad dimension":     `{"version": "x", "features": ["velocity_count:bank:1h"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+		"unknown split":     `{"version": "x", "features": ["amount"], "trees": [{"nodeid": 0, "split": "f3", "yes": 1, "no": 1, "missing": 1, "children": [{"nodeid": 1, "leaf": 1}]}]}`,
+		"dangling branch":   `{"version": "x", "features": ["amount"], "trees": [{"nodeid": 0, "split": "amount", "yes": 1, "no": 2, "missing": 1, "children": [{"nodeid": 1, "leaf": 1}]}]}`,
+		"no trees":          `{"version": "x", "features": ["amount"], "trees": []}`,
+		"no version":        `{"features": ["amount"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+		"other objective":   `{"version": "x", "objective": "reg:squarederror", "features": ["amount"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+		"bad thresholds":    `{"version": "x", "review_score": 0.9, "decline_score": 0.5, "features": ["amount"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+		"duplicate feature": `{"version": "x", "features": ["amount", "amount"], "trees": [{"nodeid": 0, "leaf": 1}]}`,
+	}
+	for name, src := range tests {
+		_, err := Parse([]byte(src))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestModel_DeclaredFeatures(t *testing.T) {
+	m, err := Parse([]byte(`{
+  "version": "test",
+  "features": ["amount", "feature:card_tx_count"],
+  "trees": [{"nodeid": 0, "split": "feature:card_tx_count", "split_condition": 2, "yes": 1, "no": 2, "missing": 1,
+             "children": [{"nodeid": 1, "leaf": 1}, {"nodeid": 2, "leaf": -1}]}]
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_tx_count"}, m.FeatureNames())
+
+	assert.InDelta(t, sigmoid(-1), m.Score(&rules.Env{Features: domain.FeatureValues{"card_tx_count": 10}}), 1e-9)
+	x := m.Vector(&rules.Env{})
+	assert.True(t, math.IsNaN(x[1]), "a missing feature is unknown")
+}
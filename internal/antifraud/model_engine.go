@@ -0,0 +1,126 @@
+package antifraud
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"payment-processing-system/internal/antifraud/features"
+	"payment-processing-system/internal/antifraud/model"
+	"payment-processing-system/internal/antifraud/rules"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// modelRule is the triggered rule reported when the model score reaches the review threshold.
+const modelRule = "model_score"
+
+// ModelEngine implements the FraudRuleEngine interface by scoring transactions with a gradient-boosted
+// tree model loaded from a file. The file is re-read when it changes; an invalid new version is rejected
+// and the previous model stays active. The model version is reported as "model/<version>".
+// Features read by the model must be declared in the registry.
+type ModelEngine struct {
+	path     string
+	velocity ports.VelocityCounter
+	features *features.Registry
+	logger   *slog.Logger
+
+	model atomic.Pointer[model.Model]
+}
+
+// NewModelEngine loads the model file at path. An invalid file is a startup error.
+// registry may be nil when no features are declared.
+func NewModelEngine(path string, velocity ports.VelocityCounter, registry *features.Registry, logger *slog.Logger) (*ModelEngine, error) {
+	e := &ModelEngine{
+		path:     path,
+		velocity: velocity,
+		features: registry,
+		logger:   logger,
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload reads and validates the model file and, if it is valid, makes it active.
+// Evaluations already running finish with the model they started with.
+func (e *ModelEngine) Reload() error {
+	m, err := model.LoadFile(e.path)
+	if err != nil {
+		return err
+	}
+	if err := e.features.Check(m.FeatureNames()); err != nil {
+		return fmt.Errorf("%s: %w", e.path, err)
+	}
+	e.model.Store(m)
+	return nil
+}
+
+// Watch checks the model file for changes every interval until ctx is cancelled.
+func (e *ModelEngine) Watch(ctx context.Context, interval time.Duration) {
+	watchFile(ctx, e.path, interval, e.Reload, func(err error) {
+		if err != nil {
+			e.logger.Error("не удалось перезагрузить модель, продолжаем со старой", "path", e.path, "error", err)
+			return
+		}
+		m := e.model.Load()
+		e.logger.Info("модель антифрода перезагружена", "version", m.Version, "features", len(m.Features))
+	})
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (e *ModelEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	m := e.model.Load()
+
+	env := &rules.Env{Transaction: tx}
+	if specs := m.VelocitySpecs(); len(specs) > 0 {
+		values, err := recordVelocity(ctx, e.velocity, tx, specs)
+		if err != nil {
+			return domain.FraudResult{}, err
+		}
+		env.Velocity = values
+	}
+	if names := m.FeatureNames(); len(names) > 0 {
+		values, err := e.features.Get(ctx, tx, names)
+		if err != nil {
+			return domain.FraudResult{}, err
+		}
+		env.Features = values
+	}
+
+	x := m.Vector(env)
+	score := m.Predict(x)
+	result := domain.FraudResult{
+		RiskScore:     score,
+		Decision:      domain.DecisionAllow,
+		EngineVersion: "model/" + m.Version,
+	}
+	switch {
+	case score >= m.DeclineScore:
+		result.Decision = domain.DecisionDecline
+		result.IsFraudulent = true
+	case score >= m.ReviewScore:
+		result.Decision = domain.DecisionReview
+	}
+	if result.Decision != domain.DecisionAllow {
+		result.TriggeredRules = []string{modelRule}
+		result.Reason = fmt.Sprintf("Model score %.2f", score)
+	}
+	result.Explanation = &domain.FraudExplanation{
+		Engine:           result.EngineVersion,
+		Decision:         result.Decision,
+		Score:            score,
+		ReviewThreshold:  m.ReviewScore,
+		DeclineThreshold: m.DeclineScore,
+		Rules: []domain.RuleEvaluation{{
+			Name:    modelRule,
+			Matched: result.Decision != domain.DecisionAllow,
+			Score:   score,
+		}},
+		Inputs: m.Inputs(x),
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package antifraud
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelWithLeaf is a single-leaf model whose score is sigmoid(leaf).
+func modelWithLeaf(version, leaf string) string {
+	return `{"version": "` + version + `", "features": ["amount", "velocity_count:card:1h"],
+	         "trees": [{"nodeid": 0, "leaf": ` + leaf + `}]}`
+}
+
+func TestModelEngine_ScoresAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	require.NoError(t, os.WriteFile(path, []byte(modelWithLeaf("v1", "-3")), 0o600))
+
+	velocity := &stubVelocity{count: 1}
+	engine, err := NewModelEngine(path, velocity, nil, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	tx := domain.Transaction{ID: uuid.New(), Amount: 10, CardNumberHash: "abc"}
+	result, err := engine.CheckTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionAllow, result.Decision)
+	assert.Less(t, result.RiskScore, 0.1)
+	assert.True(t, strings.HasPrefix(result.EngineVersion, "model/v1@"))
+	assert.Len(t, velocity.queries, 1, "velocity features are recorded")
+
+	// A broken file keeps the previous model.
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "v2"`), 0o600))
+	assert.Error(t, engine.Reload())
+
+	require.NoError(t, os.WriteFile(path, []byte(modelWithLeaf("v2", "3")), 0o600))
+	require.NoError(t, engine.Reload())
+	result, err = engine.CheckTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionDecline, result.Decision)
+	assert.True(t, result.IsFraudulent)
+	assert.Equal(t, []string{"model_score"}, result.TriggeredRules)
+	assert.True(t, strings.HasPrefix(result.EngineVersion, "model/v2@"))
+}
@@ -0,0 +1,116 @@
+package antifraud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// Triggered rules of the ring checks.
+const (
+	ringClusterRule   = "ring_cluster_size"
+	ringAttributeRule = "ring_shared_attribute"
+)
+
+// RingOptions are the thresholds of the ring checks. A zero limit disables its check.
+type RingOptions struct {
+	// MaxClusterCards is the largest allowed number of cards linked through shared attributes.
+	MaxClusterCards int
+	// MaxAttributeCards is the largest allowed number of cards seen with one device, email or IP.
+	MaxAttributeCards int
+	// Decline declines flagged transactions; otherwise they go to REVIEW.
+	Decline bool
+}
+
+// RingEngine implements the FraudRuleEngine interface by linking every transaction into the card graph
+// after the wrapped engine ran, and escalating the decision when the card's cluster exceeds the thresholds.
+// The ring checks only escalate: a ring never allows what the wrapped engine declined.
+type RingEngine struct {
+	graph ports.RingGraph
+	next  domain.FraudRuleEngine
+	opts  RingOptions
+}
+
+// NewRingEngine wraps next with the ring checks.
+func NewRingEngine(graph ports.RingGraph, next domain.FraudRuleEngine, opts RingOptions) *RingEngine {
+	return &RingEngine{graph: graph, next: next, opts: opts}
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (e *RingEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	result, err := e.next.CheckTransaction(ctx, tx)
+	if err != nil {
+		return result, err
+	}
+	if tx.CardNumberHash == "" {
+		return result, nil
+	}
+
+	stats, err := e.graph.LinkCard(ctx, tx.CardNumberHash, domain.RingLinks(tx))
+	if err != nil {
+		return domain.FraudResult{}, err
+	}
+
+	explanation := explanationOf(&result)
+	var rules, reasons []string
+	var evaluations []domain.RuleEvaluation
+	if e.opts.MaxClusterCards > 0 {
+		matched := stats.ClusterCards > e.opts.MaxClusterCards
+		if matched {
+			rules = append(rules, ringClusterRule)
+			reasons = append(reasons, fmt.Sprintf("card is linked to %d cards (limit %d)", stats.ClusterCards, e.opts.MaxClusterCards))
+		}
+		evaluations = append(evaluations, e.evaluation(ringClusterRule, fmt.Sprintf("ring:cluster_cards > %d", e.opts.MaxClusterCards), matched, "ring:cluster_cards"))
+	}
+	if e.opts.MaxAttributeCards > 0 {
+		matched := stats.MaxAttributeCards > e.opts.MaxAttributeCards
+		if matched {
+			rules = append(rules, ringAttributeRule)
+			reasons = append(reasons, fmt.Sprintf("an attribute is shared by %d cards (limit %d)", stats.MaxAttributeCards, e.opts.MaxAttributeCards))
+		}
+		evaluations = append(evaluations, e.evaluation(ringAttributeRule, fmt.Sprintf("ring:max_attribute_cards > %d", e.opts.MaxAttributeCards), matched, "ring:max_attribute_cards"))
+	}
+	if len(rules) == 0 {
+		explainRing(explanation, stats, evaluations)
+		return result, nil
+	}
+
+	result.TriggeredRules = append(result.TriggeredRules, rules...)
+	reason := "Fraud ring: " + strings.Join(reasons, ", ")
+	if result.Reason != "" {
+		reason = result.Reason + "; " + reason
+	}
+	result.Reason = reason
+	switch {
+	case e.opts.Decline:
+		result.Decision = domain.DecisionDecline
+		result.IsFraudulent = true
+		result.RiskScore = 1
+	case result.Decision == domain.DecisionAllow:
+		result.Decision = domain.DecisionReview
+	}
+	explanation.Decision, explanation.Score = result.Decision, result.RiskScore
+	explainRing(explanation, stats, evaluations)
+	return result, nil
+}
+
+func (e *RingEngine) evaluation(rule, condition string, matched bool, input string) domain.RuleEvaluation {
+	action := "review"
+	if e.opts.Decline {
+		action = "decline"
+	}
+	return domain.RuleEvaluation{Name: rule, Condition: condition, Matched: matched, Action: action, Inputs: []string{input}}
+}
+
+// explainRing adds the ring checks and the cluster of the card to the explanation.
+func explainRing(e *domain.FraudExplanation, stats domain.RingStats, evaluations []domain.RuleEvaluation) {
+	if e.Inputs == nil {
+		e.Inputs = make(map[string]any)
+	}
+	e.Inputs["ring:cluster_cards"] = stats.ClusterCards
+	e.Inputs["ring:max_attribute_cards"] = stats.MaxAttributeCards
+	e.Rules = append(e.Rules, evaluations...)
+}
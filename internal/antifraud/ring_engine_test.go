@@ -0,0 +1,61 @@
+package antifraud
+
+import (
+	"context"
+	"testing"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRingGraph struct {
+	stats domain.RingStats
+	card  string
+	links []domain.RingLink
+}
+
+func (g *stubRingGraph) LinkCard(_ context.Context, cardHash string, links []domain.RingLink) (domain.RingStats, error) {
+	g.card, g.links = cardHash, links
+	return g.stats, nil
+}
+
+func TestRingEngine_EscalatesLargeClusters(t *testing.T) {
+	graph := &stubRingGraph{stats: domain.RingStats{ClusterCards: 12, ClusterSize: 20, SharedAttributes: 2, MaxAttributeCards: 3}}
+	next := &stubEngine{result: domain.FraudResult{Decision: domain.DecisionAllow, RiskScore: 0.1}}
+	engine := NewRingEngine(graph, next, RingOptions{MaxClusterCards: 10, MaxAttributeCards: 5})
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{CardNumberHash: "abc", DeviceID: "dev-1", IP: "203.0.113.7"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", graph.card)
+	assert.Equal(t, []domain.RingLink{{Kind: domain.VelocityDevice, Value: "dev-1"}, {Kind: domain.VelocityIP, Value: "203.0.113.7"}}, graph.links)
+	assert.Equal(t, domain.DecisionReview, result.Decision)
+	assert.Equal(t, []string{"ring_cluster_size"}, result.TriggeredRules)
+	assert.Equal(t, "Fraud ring: card is linked to 12 cards (limit 10)", result.Reason)
+	assert.Equal(t, 0.1, result.RiskScore)
+
+	require.NotNil(t, result.Explanation)
+	assert.Equal(t, domain.DecisionReview, result.Explanation.Decision)
+	require.Len(t, result.Explanation.Rules, 2)
+	assert.True(t, result.Explanation.Rules[0].Matched)
+	assert.False(t, result.Explanation.Rules[1].Matched)
+	assert.Equal(t, 12, result.Explanation.Inputs["ring:cluster_cards"])
+}
+
+func TestRingEngine_NeverRelaxesDecision(t *testing.T) {
+	graph := &stubRingGraph{stats: domain.RingStats{ClusterCards: 2, MaxAttributeCards: 8}}
+	next := &stubEngine{result: domain.FraudResult{
+		IsFraudulent: true, Reason: "Amount exceeds threshold", RiskScore: 1,
+		TriggeredRules: []string{"amount_threshold"}, Decision: domain.DecisionDecline,
+	}}
+	engine := NewRingEngine(graph, next, RingOptions{MaxClusterCards: 10, MaxAttributeCards: 5})
+
+	result, err := engine.CheckTransaction(context.Background(), domain.Transaction{CardNumberHash: "abc", EmailHash: "e"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DecisionDecline, result.Decision)
+	assert.Equal(t, []string{"amount_threshold", "ring_shared_attribute"}, result.TriggeredRules)
+	assert.Equal(t, "Amount exceeds threshold; Fraud ring: an attribute is shared by 8 cards (limit 5)", result.Reason)
+}
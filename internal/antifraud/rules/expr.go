@@ -0,0 +1,629 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"payment-processing-system/internal/core/domain"
+)
+
+// The condition language is a small expression syntax, e.g.
+//
+//	amount > 500 && currency in ["USD", "EUR"] && velocity_count("card", "1h") >= 5
+//
+// Supported: number, string ('..' or "..") and bool literals, list literals of numbers or strings,
+// the fields listed in fieldTypes, the functions listed in funcs, feature("name") for a declared
+// feature (0 when the transaction does not carry the feature's entity),
+// comparison operators == != < <= > >=, the "in" operator, && || ! and parentheses.
+
+type valueType int
+
+const (
+	typeNumber valueType = iota
+	typeString
+	typeBool
+	typeNumberList
+	typeStringList
+)
+
+func (t valueType) String() string {
+	switch t {
+	case typeNumber:
+		return "number"
+	case typeString:
+		return "string"
+	case typeBool:
+		return "bool"
+	case typeNumberList:
+		return "list of numbers"
+	case typeStringList:
+		return "list of strings"
+	}
+	return "unknown"
+}
+
+// fieldTypes lists the transaction attributes available in conditions.
+var fieldTypes = map[string]valueType{
+	"amount":      typeNumber,
+	"currency":    typeString,
+	"card_hash":   typeString, // card fingerprint
+	"merchant_id": typeString,
+	"customer_id": typeString,
+	"bin_country": typeString, // empty when the issuer country is unknown
+	"hour":        typeNumber, // 0-23, UTC
+	"weekday":     typeNumber, // 1 (Monday) - 7 (Sunday), UTC
+	// Buyer signals; empty when the merchant did not send them.
+	"email_domain":     typeString,
+	"ip":               typeString,
+	"ip_country":       typeString, // empty when the IP is unknown to the GeoIP database
+	"device_id":        typeString,
+	"user_agent":       typeString,
+	"billing_country":  typeString,
+	"shipping_country": typeString,
+	"has_phone":        typeBool,
+	"address_mismatch": typeBool, // both addresses given and different
+}
+
+// funcs lists the aggregate functions available in conditions. Both take a dimension and a window literal.
+var funcs = map[string]Aggregate{
+	"velocity_count":  AggregateCount,
+	"velocity_amount": AggregateAmount,
+}
+
+// featureFunc reads a declared feature; the names are checked against the feature registry by the engine.
+const featureFunc = "feature"
+
+// dimensions lists the velocity dimensions a transaction carries.
+var dimensions = map[domain.VelocityDimension]bool{
+	domain.VelocityCard:     true,
+	domain.VelocityMerchant: true,
+	domain.VelocityCustomer: true,
+	domain.VelocityIP:       true,
+	domain.VelocityDevice:   true,
+	domain.VelocityEmail:    true,
+}
+
+// node is a compiled, type-checked expression.
+type node interface {
+	typ() valueType
+	eval(env *Env) any
+}
+
+// input is a value a condition reads from the environment, named as in model feature lists,
+// e.g. "amount", "velocity_count:card:1h" or "feature:card_tx_count".
+type input struct {
+	name string
+	n    node
+}
+
+// condition is a compiled rule condition with everything it references.
+type condition struct {
+	root     node
+	velocity []VelocitySpec
+	features []string
+	inputs   []input
+}
+
+// compileCondition parses and type-checks a rule condition. The result is always boolean.
+func compileCondition(src string) (condition, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return condition{}, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return condition{}, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return condition{}, fmt.Errorf("position %d: unexpected %q", tok.pos, tok.text)
+	}
+	if n.typ() != typeBool {
+		return condition{}, fmt.Errorf("condition must be boolean, got %s", n.typ())
+	}
+	return condition{root: n, velocity: p.velocity, features: p.features, inputs: p.inputs}, nil
+}
+
+// --- lexer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			end := strings.IndexByte(src[i+1:], byte(c))
+			if end < 0 {
+				return nil, fmt.Errorf("position %d: unterminated string", start)
+			}
+			toks = append(toks, token{kind: tokString, text: src[i+1 : i+1+end], pos: start})
+			i += end + 2
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					toks = append(toks, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("position %d: unexpected character %q", i, c)
+			}
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// --- parser ---
+
+type parser struct {
+	toks     []token
+	pos      int
+	velocity []VelocitySpec
+	features []string
+	inputs   []input
+}
+
+// addInput records a value read by the condition; a value read twice is recorded once.
+func (p *parser) addInput(name string, n node) {
+	for _, in := range p.inputs {
+		if in.name == name {
+			return
+		}
+	}
+	p.inputs = append(p.inputs, input{name: name, n: n})
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(tokOp, text) {
+		t := p.peek()
+		return fmt.Errorf("position %d: expected %q, got %q", t.pos, text, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept(tokOp, "||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left.typ() != typeBool || right.typ() != typeBool {
+			return nil, fmt.Errorf("position %d: || expects booleans", pos)
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept(tokOp, "&&") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left.typ() != typeBool || right.typ() != typeBool {
+			return nil, fmt.Errorf("position %d: && expects booleans", pos)
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	pos := p.peek().pos
+	if p.accept(tokOp, "!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if operand.typ() != typeBool {
+			return nil, fmt.Errorf("position %d: ! expects a boolean", pos)
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind == tokIdent && t.text == "in" {
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		if !(left.typ() == typeNumber && right.typ() == typeNumberList) && !(left.typ() == typeString && right.typ() == typeStringList) {
+			return nil, fmt.Errorf("position %d: cannot check %s in %s", t.pos, left.typ(), right.typ())
+		}
+		return &inNode{needle: left, list: right}, nil
+	}
+	if t.kind != tokOp {
+		return left, nil
+	}
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if left.typ() != right.typ() {
+		return nil, fmt.Errorf("position %d: cannot compare %s with %s", t.pos, left.typ(), right.typ())
+	}
+	if t.text != "==" && t.text != "!=" && left.typ() != typeNumber {
+		return nil, fmt.Errorf("position %d: %s is only defined for numbers", t.pos, t.text)
+	}
+	if left.typ() == typeNumberList || left.typ() == typeStringList {
+		return nil, fmt.Errorf("position %d: lists cannot be compared", t.pos)
+	}
+	return &compareNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("position %d: invalid number %q", t.pos, t.text)
+		}
+		return &literalNode{t: typeNumber, v: v}, nil
+
+	case tokString:
+		return &literalNode{t: typeString, v: t.text}, nil
+
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return &literalNode{t: typeBool, v: t.text == "true"}, nil
+		}
+		if p.accept(tokOp, "(") {
+			return p.parseCall(t)
+		}
+		ft, ok := fieldTypes[t.text]
+		if !ok {
+			return nil, fmt.Errorf("position %d: unknown field %q", t.pos, t.text)
+		}
+		n := &fieldNode{name: t.text, t: ft}
+		p.addInput(t.text, n)
+		return n, nil
+
+	case tokOp:
+		switch t.text {
+		case "(":
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			return p.parseList(t)
+		}
+	}
+	return nil, fmt.Errorf("position %d: unexpected %q", t.pos, t.text)
+}
+
+func (p *parser) parseList(open token) (node, error) {
+	list := &literalNode{}
+	var nums []float64
+	var strs []string
+	for !p.accept(tokOp, "]") {
+		if len(nums)+len(strs) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.next()
+		switch t.kind {
+		case tokNumber:
+			v, err := strconv.ParseFloat(t.text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("position %d: invalid number %q", t.pos, t.text)
+			}
+			nums = append(nums, v)
+		case tokString:
+			strs = append(strs, t.text)
+		default:
+			return nil, fmt.Errorf("position %d: lists may only contain number or string literals", t.pos)
+		}
+	}
+	switch {
+	case len(nums) > 0 && len(strs) > 0:
+		return nil, fmt.Errorf("position %d: list mixes numbers and strings", open.pos)
+	case len(nums) > 0:
+		list.t, list.v = typeNumberList, nums
+	default:
+		list.t, list.v = typeStringList, strs
+	}
+	return list, nil
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	agg, ok := funcs[name.text]
+	if !ok && name.text != featureFunc {
+		return nil, fmt.Errorf("position %d: unknown function %q", name.pos, name.text)
+	}
+
+	var args []string
+	for !p.accept(tokOp, ")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.next()
+		if t.kind != tokString {
+			return nil, fmt.Errorf("position %d: %s arguments must be string literals", t.pos, name.text)
+		}
+		args = append(args, t.text)
+	}
+	if name.text == featureFunc {
+		if len(args) != 1 || args[0] == "" {
+			return nil, fmt.Errorf("position %d: %s expects (name)", name.pos, name.text)
+		}
+		p.features = append(p.features, args[0])
+		n := &featureNode{name: args[0]}
+		p.addInput(featureFunc+":"+args[0], n)
+		return n, nil
+	}
+	if len(args) != 2 {
+		return nil, fmt.Errorf("position %d: %s expects (dimension, window)", name.pos, name.text)
+	}
+
+	dim := domain.VelocityDimension(args[0])
+	if !dimensions[dim] {
+		return nil, fmt.Errorf("position %d: unknown dimension %q", name.pos, args[0])
+	}
+	window, err := time.ParseDuration(args[1])
+	if err != nil || window <= 0 {
+		return nil, fmt.Errorf("position %d: invalid window %q", name.pos, args[1])
+	}
+
+	spec := VelocitySpec{Dimension: dim, Window: window}
+	p.velocity = append(p.velocity, spec)
+	n := &velocityNode{spec: spec, agg: agg}
+	p.addInput(name.text+":"+args[0]+":"+args[1], n)
+	return n, nil
+}
+
+// --- nodes ---
+
+type literalNode struct {
+	t valueType
+	v any
+}
+
+func (n *literalNode) typ() valueType  { return n.t }
+func (n *literalNode) eval(_ *Env) any { return n.v }
+
+type fieldNode struct {
+	name string
+	t    valueType
+}
+
+func (n *fieldNode) typ() valueType { return n.t }
+
+func (n *fieldNode) eval(env *Env) any {
+	tx := env.Transaction
+	switch n.name {
+	case "amount":
+		return tx.Amount
+	case "currency":
+		return tx.Currency
+	case "card_hash":
+		return tx.CardNumberHash
+	case "merchant_id":
+		return tx.MerchantID
+	case "customer_id":
+		return tx.CustomerID
+	case "bin_country":
+		return tx.BINCountry
+	case "hour":
+		return float64(tx.CreatedAt.UTC().Hour())
+	case "weekday":
+		wd := tx.CreatedAt.UTC().Weekday()
+		if wd == time.Sunday {
+			return float64(7)
+		}
+		return float64(wd)
+	case "email_domain":
+		return tx.EmailDomain
+	case "ip":
+		return tx.IP
+	case "ip_country":
+		return tx.IPCountry
+	case "device_id":
+		return tx.DeviceID
+	case "user_agent":
+		return tx.UserAgent
+	case "billing_country":
+		return tx.BillingAddress.Country
+	case "shipping_country":
+		return tx.ShippingAddress.Country
+	case "has_phone":
+		return tx.PhoneHash != ""
+	case "address_mismatch":
+		b, s := tx.BillingAddress.Hash, tx.ShippingAddress.Hash
+		return b != "" && s != "" && b != s
+	}
+	panic("rules: unknown field " + n.name) // rejected at compile time
+}
+
+type velocityNode struct {
+	spec VelocitySpec
+	agg  Aggregate
+}
+
+func (n *velocityNode) typ() valueType { return typeNumber }
+
+func (n *velocityNode) eval(env *Env) any {
+	v := env.Velocity[n.spec]
+	if n.agg == AggregateCount {
+		return float64(v.Count)
+	}
+	return v.Amount
+}
+
+type featureNode struct {
+	name string
+}
+
+func (n *featureNode) typ() valueType { return typeNumber }
+
+func (n *featureNode) eval(env *Env) any {
+	return env.Features[n.name]
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) typ() valueType { return typeBool }
+
+func (n *compareNode) eval(env *Env) any {
+	l, r := n.left.eval(env), n.right.eval(env)
+	switch n.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	}
+	lf, rf := l.(float64), r.(float64)
+	switch n.op {
+	case "<":
+		return lf < rf
+	case "<=":
+		return lf <= rf
+	case ">":
+		return lf > rf
+	default:
+		return lf >= rf
+	}
+}
+
+type inNode struct {
+	needle, list node
+}
+
+func (n *inNode) typ() valueType { return typeBool }
+
+func (n *inNode) eval(env *Env) any {
+	switch needle := n.needle.eval(env).(type) {
+	case float64:
+		for _, v := range n.list.eval(env).([]float64) {
+			if v == needle {
+				return true
+			}
+		}
+	case string:
+		for _, v := range n.list.eval(env).([]string) {
+			if v == needle {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) typ() valueType { return typeBool }
+
+func (n *logicalNode) eval(env *Env) any {
+	l := n.left.eval(env).(bool)
+	if n.or {
+		return l || n.right.eval(env).(bool)
+	}
+	return l && n.right.eval(env).(bool)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) typ() valueType    { return typeBool }
+func (n *notNode) eval(env *Env) any { return !n.operand.eval(env).(bool) }
@@ -0,0 +1,285 @@
+// Package rules implements the declarative fraud rule language: a YAML rule set
+// whose conditions are written in a small expression syntax (see expr.go).
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"payment-processing-system/internal/core/domain"
+)
+
+// Aggregate selects what a velocity function returns.
+type Aggregate int
+
+const (
+	AggregateCount Aggregate = iota
+	AggregateAmount
+)
+
+// VelocitySpec identifies one velocity aggregate referenced by the rules.
+type VelocitySpec struct {
+	Dimension domain.VelocityDimension
+	Window    time.Duration
+}
+
+// Env is the data the conditions are evaluated against.
+// Velocity aggregates include the transaction being evaluated; features do not.
+type Env struct {
+	Transaction domain.Transaction
+	Velocity    map[VelocitySpec]domain.VelocityAggregate
+	Features    domain.FeatureValues
+}
+
+// Action is what a matching rule does to the decision.
+type Action string
+
+const (
+	// ActionScore only adds the rule score; the decision follows from the thresholds.
+	ActionScore   Action = "score"
+	ActionReview  Action = "review"
+	ActionDecline Action = "decline"
+)
+
+// Rule is one compiled rule.
+type Rule struct {
+	Name        string
+	Description string
+	Condition   string
+	Score       float64
+	Action      Action
+
+	cond   node
+	inputs []input
+}
+
+// RuleSet is a validated set of rules ready for evaluation. It is immutable.
+type RuleSet struct {
+	Version      string
+	ReviewScore  float64
+	DeclineScore float64
+	Rules        []Rule
+
+	velocity []VelocitySpec
+	features []string
+}
+
+// Match is the outcome of evaluating a rule set.
+type Match struct {
+	Rules    []Rule
+	Score    float64
+	Decision domain.FraudDecision
+}
+
+// fileFormat is the YAML layout of a rule file.
+type fileFormat struct {
+	Version      string   `yaml:"version"`
+	ReviewScore  *float64 `yaml:"review_score"`
+	DeclineScore *float64 `yaml:"decline_score"`
+	Rules        []struct {
+		Name        string  `yaml:"name"`
+		Description string  `yaml:"description"`
+		When        string  `yaml:"when"`
+		Score       float64 `yaml:"score"`
+		Action      Action  `yaml:"action"`
+		Disabled    bool    `yaml:"disabled"`
+	} `yaml:"rules"`
+}
+
+var ruleNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LoadFile reads and validates a rule file.
+func LoadFile(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+	rs, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// Parse validates a rule set. All problems are reported at once, one per line.
+func Parse(data []byte) (*RuleSet, error) {
+	var f fileFormat
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	rs := &RuleSet{Version: f.Version, ReviewScore: 0.5, DeclineScore: 1}
+	if f.ReviewScore != nil {
+		rs.ReviewScore = *f.ReviewScore
+	}
+	if f.DeclineScore != nil {
+		rs.DeclineScore = *f.DeclineScore
+	}
+
+	var errs []error
+	if rs.Version == "" {
+		errs = append(errs, errors.New("version is required"))
+	}
+	if rs.ReviewScore <= 0 || rs.ReviewScore > rs.DeclineScore || rs.DeclineScore > 1 {
+		errs = append(errs, fmt.Errorf("thresholds must satisfy 0 < review_score <= decline_score <= 1, got %g and %g", rs.ReviewScore, rs.DeclineScore))
+	}
+
+	seen := make(map[string]bool, len(f.Rules))
+	specs := make(map[VelocitySpec]bool)
+	features := make(map[string]bool)
+	for i, fr := range f.Rules {
+		label := fmt.Sprintf("rules[%d]", i)
+		if fr.Name != "" {
+			label = fmt.Sprintf("rule %q", fr.Name)
+		}
+
+		switch {
+		case fr.Name == "":
+			errs = append(errs, fmt.Errorf("%s: name is required", label))
+		case !ruleNameRe.MatchString(fr.Name):
+			errs = append(errs, fmt.Errorf("%s: name must be snake_case", label))
+		case seen[fr.Name]:
+			errs = append(errs, fmt.Errorf("%s: duplicate name", label))
+		}
+		seen[fr.Name] = true
+
+		if fr.Action == "" {
+			fr.Action = ActionScore
+		}
+		switch fr.Action {
+		case ActionScore, ActionReview, ActionDecline:
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown action %q, expected score, review or decline", label, fr.Action))
+		}
+		if fr.Score < 0 || fr.Score > 1 {
+			errs = append(errs, fmt.Errorf("%s: score must be within [0, 1]", label))
+		}
+		if strings.TrimSpace(fr.When) == "" {
+			errs = append(errs, fmt.Errorf("%s: when is required", label))
+			continue
+		}
+
+		cond, err := compileCondition(fr.When)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: when: %w", label, err))
+			continue
+		}
+		if fr.Disabled {
+			continue
+		}
+		for _, s := range cond.velocity {
+			specs[s] = true
+		}
+		for _, name := range cond.features {
+			features[name] = true
+		}
+		rs.Rules = append(rs.Rules, Rule{
+			Name:        fr.Name,
+			Description: fr.Description,
+			Condition:   fr.When,
+			Score:       fr.Score,
+			Action:      fr.Action,
+			cond:        cond.root,
+			inputs:      cond.inputs,
+		})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	for s := range specs {
+		rs.velocity = append(rs.velocity, s)
+	}
+	for name := range features {
+		rs.features = append(rs.features, name)
+	}
+	slices.Sort(rs.features)
+	return rs, nil
+}
+
+// VelocitySpecs returns the aggregates the rules need. Each is needed once, whatever the number of rules using it.
+func (rs *RuleSet) VelocitySpecs() []VelocitySpec {
+	return rs.velocity
+}
+
+// FeatureNames returns the features the rules read, sorted.
+func (rs *RuleSet) FeatureNames() []string {
+	return rs.features
+}
+
+// Evaluate runs every rule against env. The score is the sum of the matched rule scores, capped at 1.
+// A matched decline (review) rule forces at least a DECLINE (REVIEW) decision; otherwise the score is
+// compared with the thresholds.
+func (rs *RuleSet) Evaluate(env *Env) Match {
+	var m Match
+	forced := domain.DecisionAllow
+	for _, r := range rs.Rules {
+		if !r.cond.eval(env).(bool) {
+			continue
+		}
+		m.Rules = append(m.Rules, r)
+		m.Score += r.Score
+		switch {
+		case r.Action == ActionDecline:
+			forced = domain.DecisionDecline
+		case r.Action == ActionReview && forced == domain.DecisionAllow:
+			forced = domain.DecisionReview
+		}
+	}
+	m.Score = math.Min(m.Score, 1)
+
+	switch {
+	case forced == domain.DecisionDecline || m.Score >= rs.DeclineScore:
+		m.Decision = domain.DecisionDecline
+	case forced == domain.DecisionReview || m.Score >= rs.ReviewScore:
+		m.Decision = domain.DecisionReview
+	default:
+		m.Decision = domain.DecisionAllow
+	}
+	return m
+}
+
+// Explain describes how the match was reached: every rule with its outcome and the values the rules read.
+func (rs *RuleSet) Explain(env *Env, m Match) domain.FraudExplanation {
+	e := domain.FraudExplanation{
+		Engine:           "rules/" + rs.Version,
+		Decision:         m.Decision,
+		Score:            m.Score,
+		ReviewThreshold:  rs.ReviewScore,
+		DeclineThreshold: rs.DeclineScore,
+		Inputs:           make(map[string]any),
+	}
+	matched := make(map[string]bool, len(m.Rules))
+	for _, r := range m.Rules {
+		matched[r.Name] = true
+	}
+	for _, r := range rs.Rules {
+		ev := domain.RuleEvaluation{
+			Name:        r.Name,
+			Description: r.Description,
+			Condition:   r.Condition,
+			Matched:     matched[r.Name],
+			Action:      string(r.Action),
+		}
+		if ev.Matched {
+			ev.Score = r.Score
+		}
+		for _, in := range r.inputs {
+			ev.Inputs = append(ev.Inputs, in.name)
+			if _, ok := e.Inputs[in.name]; !ok {
+				e.Inputs[in.name] = in.n.eval(env)
+			}
+		}
+		e.Rules = append(e.Rules, ev)
+	}
+	return e
+}
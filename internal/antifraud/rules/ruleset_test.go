@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `
+version: "test"
+review_score: 0.5
+decline_score: 0.9
+rules:
+  - name: big_amount
+    description: Big amount
+    when: amount > 1000 && currency in ["USD", "EUR"]
+    score: 0.3
+  - name: card_burst
+    description: Card burst
+    when: velocity_count("card", "1m") > 3
+    score: 0.3
+    action: review
+  - name: blocked_country
+    when: bin_country == "KP"
+    score: 0
+    action: decline
+  - name: night
+    when: hour < 5 && !(weekday in [6, 7])
+    score: 0.4
+  - name: geo_mismatch
+    when: ip_country != "" && bin_country != "" && ip_country != bin_country
+    score: 0.2
+`
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	rs, err := Parse([]byte(testRules))
+	require.NoError(t, err)
+	assert.Equal(t, []VelocitySpec{{Dimension: domain.VelocityCard, Window: time.Minute}}, rs.VelocitySpecs())
+
+	noon := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC) // Monday
+	tests := []struct {
+		name     string
+		tx       domain.Transaction
+		count    int64
+		rules    []string
+		decision domain.FraudDecision
+	}{
+		{"clean", domain.Transaction{Amount: 10, Currency: "USD", CreatedAt: noon}, 1, nil, domain.DecisionAllow},
+		{"score below review", domain.Transaction{Amount: 5000, Currency: "USD", CreatedAt: noon}, 1, []string{"big_amount"}, domain.DecisionAllow},
+		{"review action", domain.Transaction{Amount: 10, Currency: "USD", CreatedAt: noon}, 4, []string{"card_burst"}, domain.DecisionReview},
+		{"score reaches decline", domain.Transaction{Amount: 5000, Currency: "EUR", CreatedAt: noon.Add(-10 * time.Hour)}, 4,
+			[]string{"big_amount", "card_burst", "night"}, domain.DecisionDecline},
+		{"decline action", domain.Transaction{Amount: 10, Currency: "USD", BINCountry: "KP", CreatedAt: noon}, 1, []string{"blocked_country"}, domain.DecisionDecline},
+		{"ip country matches", domain.Transaction{Amount: 10, Currency: "USD", BINCountry: "US", IPCountry: "US", CreatedAt: noon}, 1, nil, domain.DecisionAllow},
+		{"ip country unknown", domain.Transaction{Amount: 10, Currency: "USD", BINCountry: "US", CreatedAt: noon}, 1, nil, domain.DecisionAllow},
+		{"ip country mismatch", domain.Transaction{Amount: 10, Currency: "USD", BINCountry: "US", IPCountry: "RU", CreatedAt: noon}, 1, []string{"geo_mismatch"}, domain.DecisionAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := rs.Evaluate(&Env{
+				Transaction: tt.tx,
+				Velocity:    map[VelocitySpec]domain.VelocityAggregate{rs.VelocitySpecs()[0]: {Count: tt.count}},
+			})
+
+			var names []string
+			for _, r := range m.Rules {
+				names = append(names, r.Name)
+			}
+			assert.Equal(t, tt.rules, names)
+			assert.Equal(t, tt.decision, m.Decision)
+		})
+	}
+}
+
+func TestRuleSet_Explain(t *testing.T) {
+	rs, err := Parse([]byte(testRules))
+	require.NoError(t, err)
+	env := &Env{
+		Transaction: domain.Transaction{Amount: 5000, Currency: "USD", BINCountry: "US", IPCountry: "RU", CreatedAt: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)},
+		Velocity:    map[VelocitySpec]domain.VelocityAggregate{rs.VelocitySpecs()[0]: {Count: 4}},
+	}
+
+	e := rs.Explain(env, rs.Evaluate(env))
+
+	assert.Equal(t, "rules/test", e.Engine)
+	assert.Equal(t, domain.DecisionReview, e.Decision)
+	assert.InDelta(t, 0.8, e.Score, 1e-9)
+	assert.Equal(t, 0.5, e.ReviewThreshold)
+	assert.Equal(t, 0.9, e.DeclineThreshold)
+	require.Len(t, e.Rules, 5)
+	assert.Equal(t, domain.RuleEvaluation{
+		Name:        "card_burst",
+		Description: "Card burst",
+		Condition:   `velocity_count("card", "1m") > 3`,
+		Matched:     true,
+		Action:      "review",
+		Score:       0.3,
+		Inputs:      []string{"velocity_count:card:1m"},
+	}, e.Rules[1])
+	assert.False(t, e.Rules[2].Matched, "blocked_country")
+	assert.Zero(t, e.Rules[2].Score)
+	assert.Equal(t, []string{"ip_country", "bin_country"}, e.Rules[4].Inputs)
+	assert.Equal(t, map[string]any{
+		"amount":                 5000.0,
+		"currency":               "USD",
+		"velocity_count:card:1m": 4.0,
+		"bin_country":            "US",
+		"hour":                   12.0,
+		"weekday":                1.0,
+		"ip_country":             "RU",
+	}, e.Inputs)
+}
+
+func TestParse_ReportsAllErrors(t *testing.T) {
+	_, err := Parse([]byte(`
+version: "bad"
+rules:
+  - name: unknown_field
+    when: amont > 10
+  - name: type_mismatch
+    when: currency > 10
+  - name: bad_window
+    when: velocity_count("card", "soon") > 1
+  - name: not_bool
+    when: amount
+  - name: unknown_field
+    when: amount > 1
+    action: block
+`))
+
+	require.Error(t, err)
+	for _, want := range []string{
+		`rule "unknown_field": when: position 0: unknown field "amont"`,
+		`rule "type_mismatch": when: position 9: cannot compare string with number`,
+		`rule "bad_window": when: position 0: invalid window "soon"`,
+		`rule "not_bool": when: condition must be boolean, got number`,
+		`rule "unknown_field": duplicate name`,
+		`rule "unknown_field": unknown action "block"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestParse_RejectsUnknownKeys(t *testing.T) {
+	_, err := Parse([]byte("version: x\nrules:\n  - name: a\n    wen: amount > 1\n"))
+
+	assert.ErrorContains(t, err, "field wen not found")
+}
+
+func TestRuleSet_Features(t *testing.T) {
+	rs, err := Parse([]byte(`
+version: "test"
+rules:
+  - name: merchant_spread
+    when: feature("card_distinct_merchants_24h") >= 5
+    score: 0.5
+  - name: new_card
+    when: feature("card_first_seen_seconds") < 3600 && feature("card_tx_count") < 3
+    score: 0.5
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"card_distinct_merchants_24h", "card_first_seen_seconds", "card_tx_count"}, rs.FeatureNames())
+
+	m := rs.Evaluate(&Env{Features: domain.FeatureValues{"card_distinct_merchants_24h": 6, "card_first_seen_seconds": 86400}})
+	require.Len(t, m.Rules, 1)
+	assert.Equal(t, "merchant_spread", m.Rules[0].Name)
+
+	// Missing features read as 0.
+	m = rs.Evaluate(&Env{})
+	require.Len(t, m.Rules, 1)
+	assert.Equal(t, "new_card", m.Rules[0].Name)
+
+	_, err = Parse([]byte("version: x\nrules:\n  - name: a\n    when: feature(\"a\", \"b\") > 1\n"))
+	assert.ErrorContains(t, err, "feature expects (name)")
+}
@@ -0,0 +1,107 @@
+package antifraud
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"payment-processing-system/internal/antifraud/features"
+	"payment-processing-system/internal/antifraud/rules"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// RulesEngine implements the FraudRuleEngine interface with the declarative rules of a rule file.
+// The file is re-read when it changes; an invalid new version is rejected and the previous rules stay active.
+// Features read by the rules must be declared in the registry.
+type RulesEngine struct {
+	path     string
+	velocity ports.VelocityCounter
+	features *features.Registry
+	logger   *slog.Logger
+
+	rules atomic.Pointer[rules.RuleSet]
+}
+
+// NewRulesEngine loads the rule file at path. An invalid file is a startup error.
+// registry may be nil when no features are declared.
+func NewRulesEngine(path string, velocity ports.VelocityCounter, registry *features.Registry, logger *slog.Logger) (*RulesEngine, error) {
+	e := &RulesEngine{
+		path:     path,
+		velocity: velocity,
+		features: registry,
+		logger:   logger,
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload reads and validates the rule file and, if it is valid, makes it active.
+func (e *RulesEngine) Reload() error {
+	rs, err := rules.LoadFile(e.path)
+	if err != nil {
+		return err
+	}
+	if err := e.features.Check(rs.FeatureNames()); err != nil {
+		return fmt.Errorf("%s: %w", e.path, err)
+	}
+	e.rules.Store(rs)
+	return nil
+}
+
+// Watch checks the rule file for changes every interval until ctx is cancelled.
+func (e *RulesEngine) Watch(ctx context.Context, interval time.Duration) {
+	watchFile(ctx, e.path, interval, e.Reload, func(err error) {
+		if err != nil {
+			e.logger.Error("не удалось перезагрузить правила, продолжаем со старыми", "path", e.path, "error", err)
+			return
+		}
+		rs := e.rules.Load()
+		e.logger.Info("правила антифрода перезагружены", "version", rs.Version, "rules", len(rs.Rules))
+	})
+}
+
+// CheckTransaction implements the FraudRuleEngine interface.
+func (e *RulesEngine) CheckTransaction(ctx context.Context, tx domain.Transaction) (domain.FraudResult, error) {
+	rs := e.rules.Load()
+
+	env := &rules.Env{Transaction: tx}
+	if specs := rs.VelocitySpecs(); len(specs) > 0 {
+		values, err := recordVelocity(ctx, e.velocity, tx, specs)
+		if err != nil {
+			return domain.FraudResult{}, err
+		}
+		env.Velocity = values
+	}
+	if names := rs.FeatureNames(); len(names) > 0 {
+		values, err := e.features.Get(ctx, tx, names)
+		if err != nil {
+			return domain.FraudResult{}, err
+		}
+		env.Features = values
+	}
+
+	match := rs.Evaluate(env)
+	result := domain.FraudResult{
+		IsFraudulent:  match.Decision == domain.DecisionDecline,
+		RiskScore:     match.Score,
+		Decision:      match.Decision,
+		EngineVersion: "rules/" + rs.Version,
+	}
+	reasons := make([]string, 0, len(match.Rules))
+	for _, r := range match.Rules {
+		result.TriggeredRules = append(result.TriggeredRules, r.Name)
+		if r.Description != "" {
+			reasons = append(reasons, r.Description)
+		}
+	}
+	result.Reason = strings.Join(reasons, "; ")
+	explanation := rs.Explain(env, match)
+	result.Explanation = &explanation
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package antifraud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+)
+
+// ShadowEngine is an engine whose verdicts are recorded for comparison but never acted upon.
+type ShadowEngine struct {
+	Name    string
+	Engine  domain.FraudRuleEngine
+	Timeout time.Duration
+}
+
+// ShadowResult is the verdict of one shadow engine. Err is set when the engine could not evaluate the transaction.
+type ShadowResult struct {
+	Engine string
+	Result domain.FraudResult
+	Err    error
+}
+
+// EvaluateShadows runs all shadow engines concurrently, each within its own timeout.
+// A failing shadow engine never fails the caller; its error is reported in its result.
+func EvaluateShadows(ctx context.Context, engines []ShadowEngine, tx domain.Transaction) []ShadowResult {
+	results := make([]ShadowResult, len(engines))
+	var wg sync.WaitGroup
+	for i, se := range engines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, se.Timeout)
+			defer cancel()
+
+			result, err := se.Engine.CheckTransaction(ctx, tx)
+			results[i] = ShadowResult{Engine: se.Name, Result: result, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,48 @@
+package antifraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateShadows_ReportsFailedAndSlowEngines(t *testing.T) {
+	engines := []ShadowEngine{
+		{Name: "rules-v2", Engine: &stubEngine{result: domain.FraudResult{Decision: domain.DecisionDecline, RiskScore: 0.9}}, Timeout: time.Second},
+		{Name: "scorer", Engine: &stubEngine{err: errors.New("connection refused")}, Timeout: time.Second},
+		{Name: "slow", Engine: &stubEngine{delay: time.Minute}, Timeout: 10 * time.Millisecond},
+	}
+
+	start := time.Now()
+	results := EvaluateShadows(context.Background(), engines, domain.Transaction{})
+
+	assert.Less(t, time.Since(start), time.Second, "a slow shadow engine must not hold the caller")
+	require.Len(t, results, 3)
+	assert.Equal(t, "rules-v2", results[0].Engine)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, domain.DecisionDecline, results[0].Result.Decision)
+	assert.Equal(t, "scorer", results[1].Engine)
+	assert.EqualError(t, results[1].Err, "connection refused")
+	assert.Equal(t, "slow", results[2].Engine)
+	assert.ErrorIs(t, results[2].Err, context.DeadlineExceeded)
+}
+
+func TestEvaluateShadows_EachEngineHasItsOwnTimeout(t *testing.T) {
+	engines := []ShadowEngine{
+		{Name: "tight", Engine: &stubEngine{delay: 50 * time.Millisecond}, Timeout: 10 * time.Millisecond},
+		{Name: "loose", Engine: &stubEngine{delay: 50 * time.Millisecond, result: domain.FraudResult{Decision: domain.DecisionAllow}}, Timeout: time.Second},
+	}
+
+	results := EvaluateShadows(context.Background(), engines, domain.Transaction{})
+
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, domain.DecisionAllow, results[1].Result.Decision)
+}
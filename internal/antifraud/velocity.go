@@ -0,0 +1,48 @@
+package antifraud
+
+import (
+	"context"
+	"time"
+
+	"payment-processing-system/internal/antifraud/rules"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// velocityEvent is the transaction as accounted in velocity counters.
+// The transaction ID makes re-evaluations of the same transaction idempotent.
+func velocityEvent(tx domain.Transaction) domain.VelocityEvent {
+	at := tx.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return domain.VelocityEvent{ID: tx.ID.String(), Amount: tx.Amount, At: at}
+}
+
+// recordVelocity accounts the transaction in the windows the specs need.
+// Dimensions the transaction does not carry (e.g. no merchant) are left out of the result.
+func recordVelocity(ctx context.Context, counter ports.VelocityCounter, tx domain.Transaction, specs []rules.VelocitySpec) (map[rules.VelocitySpec]domain.VelocityAggregate, error) {
+	queries := make([]domain.VelocityQuery, 0, len(specs))
+	recorded := make([]rules.VelocitySpec, 0, len(specs))
+	for _, spec := range specs {
+		value := spec.Dimension.Value(tx)
+		if value == "" {
+			continue
+		}
+		queries = append(queries, domain.VelocityQuery{Key: domain.VelocityKey(spec.Dimension, value), Window: spec.Window})
+		recorded = append(recorded, spec)
+	}
+
+	values := make(map[rules.VelocitySpec]domain.VelocityAggregate, len(specs))
+	if len(queries) == 0 {
+		return values, nil
+	}
+	aggregates, err := counter.Record(ctx, velocityEvent(tx), queries)
+	if err != nil {
+		return nil, err
+	}
+	for i, spec := range recorded {
+		values[spec] = aggregates[i]
+	}
+	return values, nil
+}
@@ -0,0 +1,38 @@
+package antifraud
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// watchFile calls reload every time the modification time of path changes, checking every interval
+// until ctx is cancelled. done receives the outcome of every reload attempt.
+func watchFile(ctx context.Context, path string, interval time.Duration, reload func() error, done func(error)) {
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				done(err)
+				continue
+			}
+			if info.ModTime().Equal(modTime) {
+				continue
+			}
+			// Remembered before reloading, so the same broken file is not retried on every tick.
+			modTime = info.ModTime()
+			done(reload())
+		}
+	}
+}
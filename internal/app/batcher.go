@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatcherOptions configures a Batcher.
+type BatcherOptions struct {
+	// BatchSize flushes the batch once it holds that many items.
+	BatchSize int
+	// FlushInterval flushes a batch that did not fill up in time.
+	FlushInterval time.Duration
+}
+
+// Batcher queues items and hands them to flush in batches of at most BatchSize, one batch at a time
+// on the goroutine of Run. flush handles the failures of its batch itself.
+type Batcher[T any] struct {
+	flush func(context.Context, []T)
+	opts  BatcherOptions
+
+	mu      sync.Mutex
+	pending []T
+	full    chan struct{}
+}
+
+// NewBatcher creates a batcher; Run must be running for the batches to be flushed.
+func NewBatcher[T any](flush func(context.Context, []T), opts BatcherOptions) *Batcher[T] {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	return &Batcher[T]{flush: flush, opts: opts, full: make(chan struct{}, 1)}
+}
+
+// Add queues the item for the next batch.
+func (b *Batcher[T]) Add(item T) {
+	b.mu.Lock()
+	b.pending = append(b.pending, item)
+	full := len(b.pending) >= b.opts.BatchSize
+	b.mu.Unlock()
+	if full {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run flushes the batches until ctx is cancelled, then flushes what is left with a context that is not cancelled.
+func (b *Batcher[T]) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			b.flushPending(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+		case <-b.full:
+		}
+		b.flushPending(ctx)
+	}
+}
+
+// flushPending flushes the queued items in batches of at most BatchSize.
+func (b *Batcher[T]) flushPending(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		n := min(len(b.pending), b.opts.BatchSize)
+		batch := b.pending[:n:n]
+		b.pending = b.pending[n:]
+		b.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		b.flush(ctx, batch)
+	}
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatcher_FlushesFullBatchesAndTheRestOnShutdown(t *testing.T) {
+	var batches [][]int
+	b := NewBatcher(func(_ context.Context, batch []int) {
+		batches = append(batches, batch)
+	}, BatcherOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	for i := range 5 {
+		b.Add(i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Run(ctx)
+
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}, {4}}, batches)
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// maxLabelBatch bounds the number of labels recorded at once.
+const maxLabelBatch = 10000
+
+// labelService is the implementation of the LabelService port.
+type labelService struct {
+	sink ports.FraudLabelSink
+	now  func() time.Time
+}
+
+// NewLabelService creates the service recording reported fraud labels.
+func NewLabelService(sink ports.FraudLabelSink) ports.LabelService {
+	return &labelService{
+		sink: sink,
+		now:  time.Now,
+	}
+}
+
+func (s *labelService) RecordLabels(ctx context.Context, labels []domain.FraudLabel) error {
+	if len(labels) == 0 {
+		return fmt.Errorf("%w: no labels", domain.ErrInvalidLabel)
+	}
+	if len(labels) > maxLabelBatch {
+		return fmt.Errorf("%w: at most %d labels can be recorded at once", domain.ErrInvalidLabel, maxLabelBatch)
+	}
+
+	now := s.now()
+	var errs []error
+	for i := range labels {
+		l := &labels[i]
+		if !domain.ValidReportedLabelSource(l.Source) {
+			errs = append(errs, fmt.Errorf("label %d: %w: unknown source %q, expected chargeback or merchant_report", i+1, domain.ErrInvalidLabel, l.Source))
+			continue
+		}
+		// The label is known from now on, unless the caller knows better.
+		if l.LabeledAt.IsZero() {
+			l.LabeledAt = now
+		}
+		if err := l.Validate(now); err != nil {
+			errs = append(errs, fmt.Errorf("label %d: %w", i+1, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := s.sink.SaveFraudLabels(ctx, labels); err != nil {
+		return domain.ErrStorageUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLabelService(sink *MockLabelSink, now time.Time) *labelService {
+	s := NewLabelService(sink).(*labelService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestLabelService_RecordLabels_DefaultsLabeledAt(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	received := now.Add(-48 * time.Hour)
+	sink := new(MockLabelSink)
+	s := newTestLabelService(sink, now)
+	labels := []domain.FraudLabel{
+		{TransactionID: uuid.New(), IsFraud: true, Source: domain.LabelSourceChargeback, LabeledAt: received},
+		{TransactionID: uuid.New(), Source: domain.LabelSourceMerchantReport},
+	}
+	sink.On("SaveFraudLabels", mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, s.RecordLabels(context.Background(), labels))
+
+	saved := sink.Calls[0].Arguments.Get(1).([]domain.FraudLabel)
+	assert.Equal(t, received, saved[0].LabeledAt)
+	assert.Equal(t, now, saved[1].LabeledAt)
+}
+
+func TestLabelService_RecordLabels_RejectsInvalidBatch(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	sink := new(MockLabelSink)
+	s := newTestLabelService(sink, now)
+
+	err := s.RecordLabels(context.Background(), []domain.FraudLabel{
+		{TransactionID: uuid.New(), Source: domain.LabelSourceChargeback},
+		{TransactionID: uuid.New(), Source: domain.LabelSourceManualReview},
+		{Source: domain.LabelSourceChargeback},
+		{TransactionID: uuid.New(), Source: domain.LabelSourceChargeback, LabeledAt: now.Add(time.Hour)},
+	})
+
+	require.ErrorIs(t, err, domain.ErrInvalidLabel)
+	assert.ErrorContains(t, err, `label 2: invalid fraud label: unknown source "manual_review"`)
+	assert.ErrorContains(t, err, "label 3: invalid fraud label: transaction_id is required")
+	assert.ErrorContains(t, err, "label 4: invalid fraud label: labeled_at is in the future")
+	sink.AssertNotCalled(t, "SaveFraudLabels", mock.Anything, mock.Anything)
+}
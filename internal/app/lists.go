@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+// maxListImport bounds the size of one bulk import.
+const maxListImport = 10000
+
+// listService is the implementation of the ListService port.
+// Postgres is the source of truth; every change is written through to the cache.
+type listService struct {
+	repo  ports.ListRepository
+	cache ports.ListCache
+	now   func() time.Time
+}
+
+// NewListService creates the blocklist and allowlist management service.
+func NewListService(repo ports.ListRepository, cache ports.ListCache) ports.ListService {
+	return &listService{
+		repo:  repo,
+		cache: cache,
+		now:   time.Now,
+	}
+}
+
+func (s *listService) AddEntry(ctx context.Context, e domain.ListEntry) (*domain.ListEntry, error) {
+	saved, err := s.ImportEntries(ctx, []domain.ListEntry{e})
+	if err != nil {
+		return nil, err
+	}
+	return &saved[0], nil
+}
+
+func (s *listService) ImportEntries(ctx context.Context, entries []domain.ListEntry) ([]domain.ListEntry, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no entries", domain.ErrInvalidListEntry)
+	}
+	if len(entries) > maxListImport {
+		return nil, fmt.Errorf("%w: at most %d entries can be imported at once", domain.ErrInvalidListEntry, maxListImport)
+	}
+
+	now := s.now()
+	var errs []error
+	for i := range entries {
+		if err := s.prepare(&entries[i], now); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i+1, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	saved, err := s.repo.SaveListEntries(ctx, entries)
+	if err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	// Saving again is idempotent, so a failed cache write can simply be retried by the caller.
+	if err := s.cache.PutListEntries(ctx, saved); err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return saved, nil
+}
+
+func (s *listService) GetEntry(ctx context.Context, id uuid.UUID) (*domain.ListEntry, error) {
+	e, err := s.repo.GetListEntry(ctx, id)
+	if err != nil {
+		return nil, storageError(err, domain.ErrListEntryNotFound)
+	}
+	return &e, nil
+}
+
+func (s *listService) UpdateEntry(ctx context.Context, id uuid.UUID, reason string, expiresAt *time.Time) (*domain.ListEntry, error) {
+	e, err := s.repo.GetListEntry(ctx, id)
+	if err != nil {
+		return nil, storageError(err, domain.ErrListEntryNotFound)
+	}
+	e.Reason = reason
+	e.ExpiresAt = expiresAt
+	if !e.Active(s.now()) {
+		return nil, fmt.Errorf("%w: expires_at is in the past", domain.ErrInvalidListEntry)
+	}
+
+	if err := s.repo.UpdateListEntry(ctx, e); err != nil {
+		return nil, storageError(err, domain.ErrListEntryNotFound)
+	}
+	if err := s.cache.PutListEntries(ctx, []domain.ListEntry{e}); err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return &e, nil
+}
+
+func (s *listService) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	e, err := s.repo.DeleteListEntry(ctx, id)
+	if err != nil {
+		return storageError(err, domain.ErrListEntryNotFound)
+	}
+	// The entry is gone from Postgres; if the cache keeps it, the next SyncCache removes it.
+	if err := s.cache.DeleteListEntry(ctx, e); err != nil {
+		return domain.ErrStorageUnavailable
+	}
+	return nil
+}
+
+func (s *listService) ListEntries(ctx context.Context, list domain.ListType, kind domain.ListKind, limit int) ([]domain.ListEntry, error) {
+	entries, err := s.repo.ListListEntries(ctx, list, kind, limit)
+	if err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return entries, nil
+}
+
+func (s *listService) SyncCache(ctx context.Context) error {
+	entries, err := s.repo.AllListEntries(ctx, s.now())
+	if err != nil {
+		return fmt.Errorf("failed to load list entries: %w", err)
+	}
+	if err := s.cache.ReplaceListEntries(ctx, entries); err != nil {
+		return fmt.Errorf("failed to replace cached list entries: %w", err)
+	}
+	return nil
+}
+
+// prepare validates and normalizes a new entry and fills in its ID and creation time.
+func (s *listService) prepare(e *domain.ListEntry, now time.Time) error {
+	if !e.List.Valid() {
+		return fmt.Errorf("%w: unknown list %q, expected block or allow", domain.ErrInvalidListEntry, e.List)
+	}
+	value, err := domain.NormalizeListValue(e.Kind, e.Value)
+	if err != nil {
+		return err
+	}
+	if !e.Active(now) {
+		return fmt.Errorf("%w: expires_at is in the past", domain.ErrInvalidListEntry)
+	}
+	e.Value = value
+	e.ID = uuid.New()
+	e.CreatedAt = now
+	return nil
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// ReportWriterOptions configures a ReportWriter.
+type ReportWriterOptions struct {
+	// BatchSize flushes the batch once it holds that many reports.
+	BatchSize int
+	// FlushInterval flushes a batch that did not fill up in time.
+	FlushInterval time.Duration
+	// Attempts is how many times a batch is written before its reports are reported as failed.
+	Attempts int
+	// Backoff is the pause after the first failed attempt; it doubles with every attempt.
+	Backoff time.Duration
+}
+
+// ReportWriter saves fraud reports in batches. The caller learns the outcome of every report
+// once its batch is durably written or the attempts are exhausted, so a consumer can commit
+// the offset of a record only after its report is stored.
+type ReportWriter struct {
+	repo    ports.FraudReportRepository
+	opts    ReportWriterOptions
+	logger  *slog.Logger
+	batcher *Batcher[pendingReport]
+	// callbacks tracks the goroutines reporting the outcomes of the written batches.
+	callbacks sync.WaitGroup
+}
+
+type pendingReport struct {
+	report domain.FraudReport
+	source string
+	done   func(error)
+}
+
+// NewReportWriter creates a writer; Run must be running for the reports to be written.
+func NewReportWriter(repo ports.FraudReportRepository, opts ReportWriterOptions, logger *slog.Logger) *ReportWriter {
+	if opts.Attempts < 1 {
+		opts.Attempts = 1
+	}
+	w := &ReportWriter{repo: repo, opts: opts, logger: logger}
+	w.batcher = NewBatcher(w.flush, BatcherOptions{BatchSize: opts.BatchSize, FlushInterval: opts.FlushInterval})
+	return w
+}
+
+// Write queues the report. source identifies the event the report was made for, e.g. its Kafka position;
+// the sources of a batch make its deduplication token, so a retried batch is not written twice.
+// done is called once, with nil after the report is written or with the last error after every attempt failed.
+// The reports of a batch learn their outcome in order, on a goroutine of their own: a slow done delays
+// the rest of its batch but not the next batches.
+func (w *ReportWriter) Write(report domain.FraudReport, source string, done func(error)) {
+	w.batcher.Add(pendingReport{report: report, source: source, done: done})
+}
+
+// Run flushes the batches until ctx is cancelled, then flushes what is left and waits for every done.
+// The retries of the last flush are not interrupted by ctx.
+func (w *ReportWriter) Run(ctx context.Context) {
+	w.batcher.Run(ctx)
+	w.callbacks.Wait()
+}
+
+// flush writes a batch and hands its outcome to the callbacks.
+func (w *ReportWriter) flush(ctx context.Context, batch []pendingReport) {
+	err := w.save(ctx, batch)
+	w.callbacks.Add(1)
+	go func() {
+		defer w.callbacks.Done()
+		for _, p := range batch {
+			p.done(err)
+		}
+	}()
+}
+
+// save writes a batch, retrying with exponential backoff.
+func (w *ReportWriter) save(ctx context.Context, batch []pendingReport) error {
+	reports := make([]domain.FraudReport, len(batch))
+	for i, p := range batch {
+		reports[i] = p.report
+	}
+	token := dedupToken(batch)
+
+	backoff := w.opts.Backoff
+	var err error
+	for attempt := 1; attempt <= w.opts.Attempts; attempt++ {
+		if err = w.repo.SaveFraudReports(ctx, reports, token); err == nil {
+			return nil
+		}
+		if attempt == w.opts.Attempts {
+			break
+		}
+		w.logger.Warn("не удалось записать пачку отчётов, повторяем", "error", err, "reports", len(reports), "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("failed to save %d fraud reports after %d attempts: %w", len(reports), w.opts.Attempts, err)
+}
+
+// dedupToken hashes the sources of the batch; it is empty if a report has no source.
+func dedupToken(batch []pendingReport) string {
+	sources := make([]string, len(batch))
+	for i, p := range batch {
+		if p.source == "" {
+			return ""
+		}
+		sources[i] = p.source
+	}
+	slices.Sort(sources)
+	sum := sha256.Sum256([]byte(strings.Join(sources, ",")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) SaveFraudReports(ctx context.Context, reports []domain.FraudReport, dedupToken string) error {
+	return m.Called(ctx, reports, dedupToken).Error(0)
+}
+
+func (m *MockReportRepository) GetFraudReport(ctx context.Context, id uuid.UUID) (domain.FraudReport, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.FraudReport), args.Error(1)
+}
+
+func newTestReportWriter(repo *MockReportRepository, batchSize int) *ReportWriter {
+	return NewReportWriter(repo, ReportWriterOptions{
+		BatchSize:     batchSize,
+		FlushInterval: time.Hour,
+		Attempts:      3,
+		Backoff:       time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// flushAndWait writes every queued report and waits until their outcome is reported.
+func flushAndWait(w *ReportWriter) {
+	w.batcher.flushPending(context.Background())
+	w.callbacks.Wait()
+}
+
+func TestReportWriter_FlushesInBatchesAndReportsOutcome(t *testing.T) {
+	repo := new(MockReportRepository)
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	w := newTestReportWriter(repo, 2)
+
+	var mu sync.Mutex
+	var outcomes []error
+	for i := range 5 {
+		w.Write(domain.FraudReport{TransactionID: uuid.New()}, fmt.Sprintf("tx:0:%d", i), func(err error) {
+			mu.Lock()
+			outcomes = append(outcomes, err)
+			mu.Unlock()
+		})
+	}
+	flushAndWait(w)
+
+	require.Len(t, repo.Calls, 3)
+	assert.Len(t, repo.Calls[0].Arguments.Get(1), 2)
+	assert.Len(t, repo.Calls[2].Arguments.Get(1), 1)
+	assert.NotEqual(t, repo.Calls[0].Arguments.String(2), repo.Calls[1].Arguments.String(2))
+	assert.Equal(t, []error{nil, nil, nil, nil, nil}, outcomes)
+}
+
+func TestReportWriter_RetriesBeforeFailing(t *testing.T) {
+	unavailable := errors.New("clickhouse unavailable")
+
+	repo := new(MockReportRepository)
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(unavailable).Twice()
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	w := newTestReportWriter(repo, 10)
+	var outcome error
+	w.Write(domain.FraudReport{TransactionID: uuid.New()}, "tx:0:7", func(err error) { outcome = err })
+	flushAndWait(w)
+	assert.NoError(t, outcome)
+	repo.AssertNumberOfCalls(t, "SaveFraudReports", 3)
+	// Every attempt carries the same token, so an attempt that failed after writing is not written twice.
+	token := repo.Calls[0].Arguments.String(2)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, token, repo.Calls[2].Arguments.String(2))
+
+	repo = new(MockReportRepository)
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(unavailable)
+	w = newTestReportWriter(repo, 10)
+	w.Write(domain.FraudReport{TransactionID: uuid.New()}, "tx:0:7", func(err error) { outcome = err })
+	flushAndWait(w)
+	assert.ErrorIs(t, outcome, unavailable)
+	repo.AssertNumberOfCalls(t, "SaveFraudReports", 3)
+}
+
+func TestReportWriter_FlushesOnShutdown(t *testing.T) {
+	repo := new(MockReportRepository)
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	w := newTestReportWriter(repo, 10)
+	written := false
+	w.Write(domain.FraudReport{TransactionID: uuid.New()}, "", func(err error) { written = err == nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(ctx)
+	assert.True(t, written)
+}
+
+func TestReportWriter_SlowCallbackDoesNotBlockNextFlush(t *testing.T) {
+	repo := new(MockReportRepository)
+	repo.On("SaveFraudReports", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	w := newTestReportWriter(repo, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		w.Run(ctx)
+	}()
+
+	release := make(chan struct{})
+	w.Write(domain.FraudReport{TransactionID: uuid.New()}, "tx:0:1", func(error) { <-release })
+	written := make(chan error, 1)
+	w.Write(domain.FraudReport{TransactionID: uuid.New()}, "tx:0:2", func(err error) { written <- err })
+
+	select {
+	case err := <-written:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the second batch waited for the callback of the first one")
+	}
+	repo.AssertNumberOfCalls(t, "SaveFraudReports", 2)
+
+	close(release)
+	cancel()
+	<-stopped
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+)
+
+// fraudReportService is the implementation of the FraudReportService port.
+type fraudReportService struct {
+	repo ports.FraudReportRepository
+}
+
+// NewFraudReportService creates the service reading fraud decisions and their explanations.
+func NewFraudReportService(repo ports.FraudReportRepository) ports.FraudReportService {
+	return &fraudReportService{repo: repo}
+}
+
+func (s *fraudReportService) GetFraudReport(ctx context.Context, transactionID uuid.UUID) (*domain.FraudReport, error) {
+	report, err := s.repo.GetFraudReport(ctx, transactionID)
+	if errors.Is(err, domain.ErrFraudReportNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return &report, nil
+}
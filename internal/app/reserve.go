@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+// reserveService is the implementation of the ReserveService port.
+type reserveService struct {
+	repo ports.ReserveRepository
+	now  func() time.Time
+}
+
+// NewReserveService creates a service that manages merchant rolling reserves.
+func NewReserveService(repo ports.ReserveRepository) ports.ReserveService {
+	return &reserveService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *reserveService) ConfigureReserve(ctx context.Context, cfg domain.ReserveConfig) (*domain.ReserveConfig, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.UpdatedAt = s.now()
+	cfg.EffectiveFrom = cfg.UpdatedAt
+
+	// Reconfiguring a reserve does not hold the volume taken before it was first configured.
+	existing, err := s.repo.GetReserveConfig(ctx, cfg.MerchantID)
+	switch {
+	case err == nil:
+		cfg.EffectiveFrom = existing.EffectiveFrom
+	case !errors.Is(err, domain.ErrReserveConfigNotFound):
+		return nil, domain.ErrStorageUnavailable
+	}
+
+	if err := s.repo.SaveReserveConfig(ctx, cfg); err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return &cfg, nil
+}
+
+func (s *reserveService) GetReserveBalance(ctx context.Context, merchantID string) (*domain.ReserveBalance, error) {
+	cfg, err := s.repo.GetReserveConfig(ctx, merchantID)
+	if err != nil {
+		if errors.Is(err, domain.ErrReserveConfigNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrStorageUnavailable
+	}
+
+	entries, err := s.repo.ListHeldEntries(ctx, merchantID)
+	if err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+
+	balance := &domain.ReserveBalance{
+		MerchantID:     merchantID,
+		Currency:       cfg.Currency,
+		MinimumReserve: cfg.MinimumReserve,
+		Schedule:       releaseSchedule(entries),
+	}
+	for _, e := range entries {
+		balance.Held += e.Amount
+	}
+	balance.Held = roundCents(balance.Held)
+
+	return balance, nil
+}
+
+func (s *reserveService) ProcessReserves(ctx context.Context) error {
+	configs, err := s.repo.ListReserveConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list reserve configs: %w", err)
+	}
+
+	var errs []error
+	for _, cfg := range configs {
+		if err := s.processMerchant(ctx, cfg); err != nil {
+			errs = append(errs, fmt.Errorf("merchant %s: %w", cfg.MerchantID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// processMerchant holds a share of new volume and then releases entries that have aged out.
+func (s *reserveService) processMerchant(ctx context.Context, cfg domain.ReserveConfig) error {
+	now := s.now()
+
+	txs, err := s.repo.ListUnreservedTransactions(ctx, cfg.MerchantID, cfg.Currency, holdSince(cfg, now))
+	if err != nil {
+		return fmt.Errorf("failed to list unreserved transactions: %w", err)
+	}
+	if entries := holdEntries(cfg, txs); len(entries) > 0 {
+		if err := s.repo.SaveReserveEntries(ctx, entries); err != nil {
+			return fmt.Errorf("failed to save reserve entries: %w", err)
+		}
+	}
+
+	held, err := s.repo.ListHeldEntries(ctx, cfg.MerchantID)
+	if err != nil {
+		return fmt.Errorf("failed to list held entries: %w", err)
+	}
+	if ids := releasableEntries(held, cfg.MinimumReserve, now); len(ids) > 0 {
+		if err := s.repo.ReleaseReserveEntries(ctx, ids, now); err != nil {
+			return fmt.Errorf("failed to release reserve entries: %w", err)
+		}
+	}
+	return nil
+}
+
+// holdSince is the creation time of the oldest transaction still to be held: the reserve does not
+// apply before it took effect, and a transaction older than HoldDays would be released right away.
+func holdSince(cfg domain.ReserveConfig, now time.Time) time.Time {
+	window := now.AddDate(0, 0, -cfg.HoldDays)
+	if cfg.EffectiveFrom.After(window) {
+		return cfg.EffectiveFrom
+	}
+	return window
+}
+
+// holdEntries builds one reserve entry per transaction, holding RollingPercent of its amount
+// for HoldDays from the time the transaction was created. A transaction whose share rounds
+// to zero still gets an entry, so it is not listed as unreserved again on the next run.
+func holdEntries(cfg domain.ReserveConfig, txs []domain.Transaction) []domain.ReserveEntry {
+	entries := make([]domain.ReserveEntry, 0, len(txs))
+	for _, tx := range txs {
+		amount := max(roundCents(tx.Amount*cfg.RollingPercent/100), 0)
+		entries = append(entries, domain.ReserveEntry{
+			ID:            uuid.New(),
+			MerchantID:    cfg.MerchantID,
+			TransactionID: tx.ID,
+			Amount:        amount,
+			Currency:      tx.Currency,
+			HeldAt:        tx.CreatedAt,
+			ReleaseAt:     tx.CreatedAt.AddDate(0, 0, cfg.HoldDays),
+		})
+	}
+	return entries
+}
+
+// releasableEntries picks the due entries, oldest first, that can be released
+// without the held balance dropping below the minimum reserve.
+// Entries are expected to be ordered by ReleaseAt.
+func releasableEntries(entries []domain.ReserveEntry, minimum float64, now time.Time) []uuid.UUID {
+	var held float64
+	for _, e := range entries {
+		held += e.Amount
+	}
+
+	var ids []uuid.UUID
+	for _, e := range entries {
+		if e.ReleaseAt.After(now) {
+			break
+		}
+		if roundCents(held-e.Amount) < minimum {
+			break
+		}
+		held -= e.Amount
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// releaseSchedule groups held entries by the day they become eligible for release.
+// Zero entries release nothing and are left out.
+func releaseSchedule(entries []domain.ReserveEntry) []domain.ReserveRelease {
+	var schedule []domain.ReserveRelease
+	for _, e := range entries {
+		if e.Amount == 0 {
+			continue
+		}
+		day := e.ReleaseAt.UTC().Truncate(24 * time.Hour)
+		if n := len(schedule); n > 0 && schedule[n-1].Date.Equal(day) {
+			schedule[n-1].Amount = roundCents(schedule[n-1].Amount + e.Amount)
+			continue
+		}
+		schedule = append(schedule, domain.ReserveRelease{Date: day, Amount: e.Amount})
+	}
+	return schedule
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReserveRepository keeps transactions and entries in memory and selects
+// the unreserved transactions the way the Postgres adapter does.
+type fakeReserveRepository struct {
+	cfg     domain.ReserveConfig
+	txs     []domain.Transaction
+	entries []domain.ReserveEntry
+}
+
+func (r *fakeReserveRepository) SaveReserveConfig(ctx context.Context, cfg domain.ReserveConfig) error {
+	r.cfg = cfg
+	return nil
+}
+
+func (r *fakeReserveRepository) GetReserveConfig(ctx context.Context, merchantID string) (domain.ReserveConfig, error) {
+	return r.cfg, nil
+}
+
+func (r *fakeReserveRepository) ListReserveConfigs(ctx context.Context) ([]domain.ReserveConfig, error) {
+	return []domain.ReserveConfig{r.cfg}, nil
+}
+
+func (r *fakeReserveRepository) ListUnreservedTransactions(ctx context.Context, merchantID, currency string, since time.Time) ([]domain.Transaction, error) {
+	var txs []domain.Transaction
+	for _, tx := range r.txs {
+		held := slices.ContainsFunc(r.entries, func(e domain.ReserveEntry) bool { return e.TransactionID == tx.ID })
+		if tx.MerchantID == merchantID && tx.Currency == currency && !tx.CreatedAt.Before(since) &&
+			slices.Contains(domain.ReserveHeldStatuses, tx.Status) && !held {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (r *fakeReserveRepository) SaveReserveEntries(ctx context.Context, entries []domain.ReserveEntry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *fakeReserveRepository) ListHeldEntries(ctx context.Context, merchantID string) ([]domain.ReserveEntry, error) {
+	return nil, nil
+}
+
+func (r *fakeReserveRepository) ReleaseReserveEntries(ctx context.Context, ids []uuid.UUID, releasedAt time.Time) error {
+	return nil
+}
+
+func TestReserveService_HoldsAcceptedTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeReserveRepository{cfg: domain.ReserveConfig{
+		MerchantID: "m-1", RollingPercent: 10, HoldDays: 90, Currency: "USD",
+		EffectiveFrom: time.Now().Add(-time.Hour),
+	}}
+
+	// A payment the fraud checks accept is never moved out of the status it was created in.
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil).Run(func(args mock.Arguments) {
+		repo.txs = append(repo.txs, args.Get(1).(domain.Transaction))
+	})
+	mockBroker.On("PublishTransactionCreated", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil)
+	tx, err := NewTransactionService(mockRepo, mockBroker).CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         200,
+		Currency:       "USD",
+		CardNumber:     "4532015112830366",
+		MerchantID:     "m-1",
+		IdempotencyKey: uuid.New(),
+	})
+	require.NoError(t, err)
+	// A declined payment is not held.
+	repo.txs = append(repo.txs, domain.Transaction{
+		ID: uuid.New(), MerchantID: "m-1", Amount: 500, Currency: "USD", Status: domain.StatusDeclined, CreatedAt: time.Now(),
+	})
+
+	require.NoError(t, NewReserveService(repo).ProcessReserves(ctx))
+
+	require.Len(t, repo.entries, 1)
+	assert.Equal(t, tx.ID, repo.entries[0].TransactionID)
+	assert.Equal(t, 20.0, repo.entries[0].Amount)
+}
+
+func TestHoldEntries_HoldsRollingPercent(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := domain.ReserveConfig{MerchantID: "m-1", RollingPercent: 10, HoldDays: 90, Currency: "USD"}
+	txs := []domain.Transaction{
+		{ID: uuid.New(), Amount: 123.45, Currency: "USD", CreatedAt: created},
+		{ID: uuid.New(), Amount: 0.01, Currency: "USD", CreatedAt: created}, // 10% rounds to zero
+	}
+
+	entries := holdEntries(cfg, txs)
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, txs[0].ID, entries[0].TransactionID)
+	assert.Equal(t, 12.35, entries[0].Amount)
+	assert.Equal(t, created, entries[0].HeldAt)
+	assert.Equal(t, created.AddDate(0, 0, 90), entries[0].ReleaseAt)
+	// The zero entry marks the transaction as processed.
+	assert.Equal(t, txs[1].ID, entries[1].TransactionID)
+	assert.Zero(t, entries[1].Amount)
+}
+
+func TestHoldSince_StartsWhenReserveTookEffect(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := domain.ReserveConfig{HoldDays: 90, EffectiveFrom: now.AddDate(0, 0, -7)}
+
+	assert.Equal(t, cfg.EffectiveFrom, holdSince(cfg, now))
+
+	// A reserve configured long ago does not hold transactions whose hold has already run out.
+	cfg.EffectiveFrom = now.AddDate(-1, 0, 0)
+	assert.Equal(t, now.AddDate(0, 0, -90), holdSince(cfg, now))
+}
+
+func TestReleasableEntries_KeepsMinimumReserve(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Hour)
+	entries := []domain.ReserveEntry{
+		{ID: uuid.New(), Amount: 40, ReleaseAt: due},
+		{ID: uuid.New(), Amount: 30, ReleaseAt: due},
+		{ID: uuid.New(), Amount: 50, ReleaseAt: now.Add(24 * time.Hour)},
+	}
+
+	// Held is 120; releasing the first entry leaves 80, the second would leave 50 < 60.
+	ids := releasableEntries(entries, 60, now)
+
+	assert.Equal(t, []uuid.UUID{entries[0].ID}, ids)
+}
+
+func TestReleasableEntries_StopsAtFirstFutureEntry(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []domain.ReserveEntry{
+		{ID: uuid.New(), Amount: 10, ReleaseAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), Amount: 10, ReleaseAt: now.Add(time.Minute)},
+	}
+
+	ids := releasableEntries(entries, 0, now)
+
+	assert.Equal(t, []uuid.UUID{entries[0].ID}, ids)
+}
+
+func TestReleaseSchedule_GroupsByDay(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []domain.ReserveEntry{
+		{Amount: 1.10, ReleaseAt: day.Add(2 * time.Hour)},
+		{Amount: 2.20, ReleaseAt: day.Add(20 * time.Hour)},
+		{Amount: 5, ReleaseAt: day.Add(26 * time.Hour)},
+		{Amount: 0, ReleaseAt: day.Add(50 * time.Hour)},
+	}
+
+	schedule := releaseSchedule(entries)
+
+	assert.Equal(t, []domain.ReserveRelease{
+		{Date: day, Amount: 3.30},
+		{Date: day.AddDate(0, 0, 1), Amount: 5},
+	}, schedule)
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+	"payment-processing-system/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// slaAnalyst is recorded as the decider of cases closed automatically.
+	slaAnalyst = "sla"
+	// reviewBatchSize bounds the work of one ProcessReviewQueue run.
+	reviewBatchSize = 100
+)
+
+// reviewService is the implementation of the ReviewService port.
+type reviewService struct {
+	repo            ports.ReviewRepository
+	labels          ports.FraudLabelSink
+	sla             time.Duration
+	approveOnExpiry bool
+	now             func() time.Time
+}
+
+// NewReviewService creates the manual review queue. Cases not decided within sla are approved
+// when approveOnExpiry is true and rejected otherwise. labels may be nil if this instance only opens cases.
+func NewReviewService(repo ports.ReviewRepository, labels ports.FraudLabelSink, sla time.Duration, approveOnExpiry bool) ports.ReviewService {
+	return &reviewService{
+		repo:            repo,
+		labels:          labels,
+		sla:             sla,
+		approveOnExpiry: approveOnExpiry,
+		now:             time.Now,
+	}
+}
+
+func (s *reviewService) OpenCase(ctx context.Context, tx domain.Transaction, result domain.FraudResult) error {
+	now := s.now()
+	c := domain.ReviewCase{
+		ID:             uuid.New(),
+		TransactionID:  tx.ID,
+		Status:         domain.ReviewOpen,
+		RiskScore:      result.RiskScore,
+		Reason:         result.Reason,
+		TriggeredRules: result.TriggeredRules,
+		CreatedAt:      now,
+		DueAt:          now.Add(s.sla),
+	}
+	if _, err := s.repo.OpenReviewCase(ctx, c); err != nil {
+		return fmt.Errorf("failed to open review case: %w", err)
+	}
+	return nil
+}
+
+func (s *reviewService) ListCases(ctx context.Context, status domain.ReviewStatus, limit int) ([]domain.ReviewCase, error) {
+	cases, err := s.repo.ListReviewCases(ctx, status, limit)
+	if err != nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	return cases, nil
+}
+
+func (s *reviewService) GetCase(ctx context.Context, id uuid.UUID) (*domain.ReviewCase, error) {
+	c, err := s.getCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *reviewService) ClaimCase(ctx context.Context, id uuid.UUID, analyst string) (*domain.ReviewCase, error) {
+	c, err := s.getCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.Status.Closed():
+		return nil, domain.ErrReviewCaseClosed
+	case c.Status == domain.ReviewClaimed && c.AssignedTo == analyst:
+		return &c, nil
+	case c.Status == domain.ReviewClaimed:
+		return nil, domain.ErrReviewCaseClaimed
+	}
+
+	now := s.now()
+	if err := s.repo.ClaimReviewCase(ctx, c, analyst, now); err != nil {
+		return nil, storageError(err, domain.ErrReviewCaseConflict)
+	}
+	c.Status = domain.ReviewClaimed
+	c.AssignedTo = analyst
+	c.ClaimedAt = &now
+	return &c, nil
+}
+
+func (s *reviewService) DecideCase(ctx context.Context, id uuid.UUID, analyst string, approve bool, note string) (*domain.ReviewCase, error) {
+	c, err := s.getCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.Status.Closed():
+		return nil, domain.ErrReviewCaseClosed
+	case c.Status == domain.ReviewClaimed && c.AssignedTo != analyst:
+		return nil, domain.ErrReviewCaseClaimed
+	}
+
+	c, err = s.decide(ctx, c, domain.ReviewDecision{
+		CaseID:    c.ID,
+		Approve:   approve,
+		DecidedBy: analyst,
+		Note:      note,
+		DecidedAt: s.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A failed export is retried by ProcessReviewQueue, the decision itself is already stored.
+	_ = s.exportLabels(ctx, []domain.ReviewCase{c})
+	return &c, nil
+}
+
+func (s *reviewService) ProcessReviewQueue(ctx context.Context) error {
+	now := s.now()
+	var errs []error
+
+	overdue, err := s.repo.ListOverdueReviewCases(ctx, now, reviewBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list overdue review cases: %w", err)
+	}
+	for _, c := range overdue {
+		_, err := s.decide(ctx, c, domain.ReviewDecision{
+			CaseID:      c.ID,
+			Approve:     s.approveOnExpiry,
+			DecidedBy:   slaAnalyst,
+			AutoDecided: true,
+			Note:        "review SLA expired",
+			DecidedAt:   now,
+		})
+		// A conflict means an analyst decided the case in the meantime.
+		if err != nil && !errors.Is(err, domain.ErrReviewCaseConflict) {
+			errs = append(errs, fmt.Errorf("case %s: %w", c.ID, err))
+		}
+	}
+
+	if s.labels != nil {
+		pending, err := s.repo.ListUnexportedReviewCases(ctx, reviewBatchSize)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list unexported review cases: %w", err))
+		} else if err := s.exportLabels(ctx, pending); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *reviewService) getCase(ctx context.Context, id uuid.UUID) (domain.ReviewCase, error) {
+	c, err := s.repo.GetReviewCase(ctx, id)
+	if err != nil {
+		return domain.ReviewCase{}, storageError(err, domain.ErrReviewCaseNotFound)
+	}
+	return c, nil
+}
+
+func (s *reviewService) decide(ctx context.Context, c domain.ReviewCase, d domain.ReviewDecision) (domain.ReviewCase, error) {
+	if err := s.repo.DecideReviewCase(ctx, c, d); err != nil {
+		return domain.ReviewCase{}, storageError(err, domain.ErrReviewCaseConflict)
+	}
+	c.Status = d.Status()
+	c.DecidedBy = d.DecidedBy
+	c.AutoDecided = d.AutoDecided
+	c.Note = d.Note
+	c.DecidedAt = &d.DecidedAt
+	return c, nil
+}
+
+// exportLabels sends the outcome of decided cases to the label sink and remembers which were sent.
+func (s *reviewService) exportLabels(ctx context.Context, cases []domain.ReviewCase) error {
+	if s.labels == nil || len(cases) == 0 {
+		return nil
+	}
+
+	labels := make([]domain.FraudLabel, 0, len(cases))
+	ids := make([]uuid.UUID, 0, len(cases))
+	for _, c := range cases {
+		source := domain.LabelSourceManualReview
+		if c.AutoDecided {
+			source = domain.LabelSourceReviewSLA
+		}
+		label := domain.FraudLabel{
+			TransactionID: c.TransactionID,
+			IsFraud:       c.Status == domain.ReviewRejected,
+			Source:        source,
+			LabeledBy:     c.DecidedBy,
+			Note:          c.Note,
+		}
+		if c.DecidedAt != nil {
+			label.LabeledAt = *c.DecidedAt
+		}
+		labels = append(labels, label)
+		ids = append(ids, c.ID)
+	}
+
+	if err := s.labels.SaveFraudLabels(ctx, labels); err != nil {
+		return fmt.Errorf("failed to export review labels: %w", err)
+	}
+	if err := s.repo.MarkReviewLabelsExported(ctx, ids, s.now()); err != nil {
+		return fmt.Errorf("failed to mark review labels exported: %w", err)
+	}
+	return nil
+}
+
+// storageError keeps the expected domain error and hides every other failure behind ErrStorageUnavailable.
+func storageError(err, expected error) error {
+	if errors.Is(err, expected) {
+		return expected
+	}
+	return domain.ErrStorageUnavailable
+}
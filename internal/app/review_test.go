@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) OpenReviewCase(ctx context.Context, c domain.ReviewCase) (bool, error) {
+	args := m.Called(ctx, c)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetReviewCase(ctx context.Context, id uuid.UUID) (domain.ReviewCase, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.ReviewCase), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListReviewCases(ctx context.Context, status domain.ReviewStatus, limit int) ([]domain.ReviewCase, error) {
+	args := m.Called(ctx, status, limit)
+	return args.Get(0).([]domain.ReviewCase), args.Error(1)
+}
+
+func (m *MockReviewRepository) ClaimReviewCase(ctx context.Context, expected domain.ReviewCase, analyst string, at time.Time) error {
+	return m.Called(ctx, expected, analyst, at).Error(0)
+}
+
+func (m *MockReviewRepository) DecideReviewCase(ctx context.Context, expected domain.ReviewCase, d domain.ReviewDecision) error {
+	return m.Called(ctx, expected, d).Error(0)
+}
+
+func (m *MockReviewRepository) ListOverdueReviewCases(ctx context.Context, now time.Time, limit int) ([]domain.ReviewCase, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.ReviewCase), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListUnexportedReviewCases(ctx context.Context, limit int) ([]domain.ReviewCase, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.ReviewCase), args.Error(1)
+}
+
+func (m *MockReviewRepository) MarkReviewLabelsExported(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	return m.Called(ctx, ids, at).Error(0)
+}
+
+type MockLabelSink struct {
+	mock.Mock
+}
+
+func (m *MockLabelSink) SaveFraudLabels(ctx context.Context, labels []domain.FraudLabel) error {
+	return m.Called(ctx, labels).Error(0)
+}
+
+func newTestReviewService(repo *MockReviewRepository, sink *MockLabelSink, now time.Time) *reviewService {
+	s := NewReviewService(repo, sink, time.Hour, false).(*reviewService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestReviewService_ClaimCase_ClaimedByAnotherAnalyst(t *testing.T) {
+	repo := new(MockReviewRepository)
+	s := newTestReviewService(repo, new(MockLabelSink), time.Now())
+	c := domain.ReviewCase{ID: uuid.New(), Status: domain.ReviewClaimed, AssignedTo: "alice"}
+	repo.On("GetReviewCase", mock.Anything, c.ID).Return(c, nil)
+
+	_, err := s.ClaimCase(context.Background(), c.ID, "bob")
+	assert.ErrorIs(t, err, domain.ErrReviewCaseClaimed)
+
+	// Claiming again by the same analyst is a no-op.
+	claimed, err := s.ClaimCase(context.Background(), c.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claimed.AssignedTo)
+	repo.AssertNotCalled(t, "ClaimReviewCase", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReviewService_DecideCase_ExportsLabel(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	repo := new(MockReviewRepository)
+	sink := new(MockLabelSink)
+	s := newTestReviewService(repo, sink, now)
+	c := domain.ReviewCase{ID: uuid.New(), TransactionID: uuid.New(), Status: domain.ReviewClaimed, AssignedTo: "alice"}
+
+	repo.On("GetReviewCase", mock.Anything, c.ID).Return(c, nil)
+	repo.On("DecideReviewCase", mock.Anything, c, domain.ReviewDecision{
+		CaseID: c.ID, Approve: false, DecidedBy: "alice", Note: "stolen card", DecidedAt: now,
+	}).Return(nil)
+	sink.On("SaveFraudLabels", mock.Anything, []domain.FraudLabel{{
+		TransactionID: c.TransactionID, IsFraud: true, Source: domain.LabelSourceManualReview,
+		LabeledBy: "alice", Note: "stolen card", LabeledAt: now,
+	}}).Return(nil)
+	repo.On("MarkReviewLabelsExported", mock.Anything, []uuid.UUID{c.ID}, now).Return(nil)
+
+	decided, err := s.DecideCase(context.Background(), c.ID, "alice", false, "stolen card")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewRejected, decided.Status)
+	repo.AssertExpectations(t)
+	sink.AssertExpectations(t)
+}
+
+func TestReviewService_ProcessReviewQueue_AutoDecidesOverdueCases(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	repo := new(MockReviewRepository)
+	sink := new(MockLabelSink)
+	s := newTestReviewService(repo, sink, now)
+	overdue := domain.ReviewCase{ID: uuid.New(), TransactionID: uuid.New(), Status: domain.ReviewOpen}
+	raced := domain.ReviewCase{ID: uuid.New(), TransactionID: uuid.New(), Status: domain.ReviewClaimed, AssignedTo: "alice"}
+	decided := overdue
+	decided.Status, decided.DecidedBy, decided.AutoDecided, decided.DecidedAt = domain.ReviewRejected, slaAnalyst, true, &now
+
+	repo.On("ListOverdueReviewCases", mock.Anything, now, reviewBatchSize).Return([]domain.ReviewCase{overdue, raced}, nil)
+	repo.On("DecideReviewCase", mock.Anything, overdue, mock.MatchedBy(func(d domain.ReviewDecision) bool {
+		return !d.Approve && d.AutoDecided && d.DecidedBy == slaAnalyst
+	})).Return(nil)
+	// The analyst decided this one after it was listed.
+	repo.On("DecideReviewCase", mock.Anything, raced, mock.Anything).Return(domain.ErrReviewCaseConflict)
+	repo.On("ListUnexportedReviewCases", mock.Anything, reviewBatchSize).Return([]domain.ReviewCase{decided}, nil)
+	sink.On("SaveFraudLabels", mock.Anything, mock.MatchedBy(func(labels []domain.FraudLabel) bool {
+		return len(labels) == 1 && labels[0].IsFraud && labels[0].Source == domain.LabelSourceReviewSLA
+	})).Return(nil)
+	repo.On("MarkReviewLabelsExported", mock.Anything, []uuid.UUID{overdue.ID}, now).Return(nil)
+
+	err := s.ProcessReviewQueue(context.Background())
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	sink.AssertExpectations(t)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
@@ -17,17 +18,70 @@ import (
 
 // service is the implementation of the TransactionService port
 type service struct {
-	repo   ports.TransactionRepository
-	broker ports.MessageBroker
+	repo    ports.TransactionRepository
+	broker  ports.MessageBroker
+	limiter ports.SpendingLimitRepository
+	limits  []domain.SpendingLimit
+
+	fraudEngine   domain.FraudRuleEngine
+	fraudTimeout  time.Duration
+	fraudFailOpen bool
+
+	reports ports.FraudReportRepository
+	logger  *slog.Logger
+
+	geo ports.GeoLookup
+}
+
+// Option configures optional behaviour of the transaction service.
+type Option func(*service)
+
+// WithSpendingLimits enables synchronous enforcement of the given limits before a transaction is saved.
+func WithSpendingLimits(limiter ports.SpendingLimitRepository, limits []domain.SpendingLimit) Option {
+	return func(s *service) {
+		s.limiter = limiter
+		s.limits = limits
+	}
+}
+
+// WithFraudCheck enables a synchronous pre-authorization fraud check.
+// The check must answer within timeout; otherwise the transaction is accepted
+// when failOpen is true and declined when it is false.
+func WithFraudCheck(engine domain.FraudRuleEngine, timeout time.Duration, failOpen bool) Option {
+	return func(s *service) {
+		s.fraudEngine = engine
+		s.fraudTimeout = timeout
+		s.fraudFailOpen = failOpen
+	}
+}
+
+// WithFraudReports stores the result of every pre-authorization decline in the fraud report store,
+// so the explanation of a transaction the analyzer never sees can still be served to support staff.
+func WithFraudReports(reports ports.FraudReportRepository, logger *slog.Logger) Option {
+	return func(s *service) {
+		s.reports = reports
+		s.logger = logger
+	}
+}
+
+// WithGeoLookup fills in the issuer country of the card and the country of the buyer IP.
+func WithGeoLookup(geo ports.GeoLookup) Option {
+	return func(s *service) {
+		s.geo = geo
+	}
 }
 
 // NewTransactionService is the constructor of our service.
 // TODO: Он принимает зависимости через интерфейсы (Dependency Injection).
-func NewTransactionService(repo ports.TransactionRepository, broker ports.MessageBroker) ports.TransactionService {
-	return &service{
+func NewTransactionService(repo ports.TransactionRepository, broker ports.MessageBroker, opts ...Option) ports.TransactionService {
+	s := &service{
 		repo:   repo,
 		broker: broker,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // isValidCard validates a card number using the Luhn algorithm
@@ -64,30 +118,61 @@ func isValidCard(cardNum string) bool {
 	return sum%10 == 0
 }
 
-func (s *service) CreateTransaction(ctx context.Context, amount float64, currency, cardNum string, idemKey uuid.UUID) (*domain.Transaction, error) {
+// cardBIN returns the first 8 digits of the card number, which identify the issuer.
+func cardBIN(cardNum string) string {
+	cardNum = strings.ReplaceAll(cardNum, " ", "")
+	if len(cardNum) < 8 {
+		return ""
+	}
+	return cardNum[:8]
+}
+
+func (s *service) CreateTransaction(ctx context.Context, in ports.CreateTransactionInput) (*domain.Transaction, error) {
 	// Hashing the card number
-	hash := sha256.Sum256([]byte(cardNum))
+	hash := sha256.Sum256([]byte(in.CardNumber))
 	cardHash := fmt.Sprintf("%x", hash)
 
 	tx := domain.Transaction{
 		ID:             uuid.New(),
 		Status:         domain.StatusProcessing,
-		Amount:         amount,
-		Currency:       currency,
+		Amount:         in.Amount,
+		Currency:       in.Currency,
 		CardNumberHash: cardHash,
-		IdempotencyKey: idemKey,
+		MerchantID:     in.MerchantID,
+		CustomerID:     in.CustomerID,
+		BIN:            cardBIN(in.CardNumber),
+		IdempotencyKey: in.IdempotencyKey,
 		CreatedAt:      time.Now(),
 	}
+	s.addSignals(&tx, in)
 
-	if amount <= 0 {
+	if in.Amount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
 
-	if !isValidCard(cardNum) {
+	if !isValidCard(in.CardNumber) {
 		return nil, domain.ErrInvalidCard
 	}
 
+	if result, declined := s.preAuthorize(ctx, tx); declined {
+		return nil, s.recordDeclined(ctx, tx, result)
+	}
+
+	counters := s.limitCounters(tx)
+	if len(counters) > 0 {
+		if err := s.limiter.Consume(ctx, counters, tx.Amount); err != nil {
+			if errors.Is(err, domain.ErrLimitExceeded) {
+				return nil, err
+			}
+			return nil, domain.ErrStorageUnavailable
+		}
+	}
+
 	if err := s.repo.Save(ctx, tx); err != nil {
+		// The transaction was not accepted, so it must not count towards the limits.
+		if len(counters) > 0 {
+			_ = s.limiter.Revert(ctx, counters, tx.Amount)
+		}
 		if errors.Is(err, domain.ErrIdempotencyKeyUsed) {
 			return nil, err
 		}
@@ -100,3 +185,119 @@ func (s *service) CreateTransaction(ctx context.Context, amount float64, currenc
 
 	return &tx, nil
 }
+
+// addSignals copies the buyer signals to the transaction, hashing personal data, and resolves the countries.
+func (s *service) addSignals(tx *domain.Transaction, in ports.CreateTransactionInput) {
+	if in.Email != "" {
+		tx.EmailHash = domain.HashPII(in.Email)
+		tx.EmailDomain = domain.EmailDomain(in.Email)
+	}
+	if in.Phone != "" {
+		tx.PhoneHash = domain.HashPII(in.Phone)
+	}
+	tx.IP = in.IP
+	tx.UserAgent = in.UserAgent
+	tx.DeviceID = in.DeviceID
+	tx.BillingAddress = in.BillingAddress.Fingerprint()
+	tx.ShippingAddress = in.ShippingAddress.Fingerprint()
+
+	if s.geo != nil {
+		tx.BINCountry = s.geo.BINCountry(tx.BIN)
+		if tx.IP != "" {
+			tx.IPCountry = s.geo.IPCountry(tx.IP)
+		}
+	}
+}
+
+// reasonFraudCheckUnavailable is the decline reason used when the check cannot be evaluated and the policy is fail-closed.
+const reasonFraudCheckUnavailable = "fraud check unavailable"
+
+// preAuthorize runs the fraud check within the latency budget and tells whether the transaction must be declined.
+// Only a DECLINE decision stops the transaction; REVIEW is accepted and left to the asynchronous analysis.
+func (s *service) preAuthorize(ctx context.Context, tx domain.Transaction) (domain.FraudResult, bool) {
+	if s.fraudEngine == nil {
+		return domain.FraudResult{}, false
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, s.fraudTimeout)
+	defer cancel()
+
+	result, err := s.fraudEngine.CheckTransaction(checkCtx, tx)
+	if err == nil {
+		return result, result.Decision == domain.DecisionDecline
+	}
+
+	// Timeouts and engine failures are handled the same way.
+	if s.fraudFailOpen {
+		return domain.FraudResult{}, false
+	}
+	return domain.FraudResult{Reason: reasonFraudCheckUnavailable, Decision: domain.DecisionDecline}, true
+}
+
+// recordDeclined stores the declined attempt and returns the error for the caller.
+func (s *service) recordDeclined(ctx context.Context, tx domain.Transaction, result domain.FraudResult) error {
+	tx.Status = domain.StatusDeclined
+	tx.IsFraudulent = result.IsFraudulent
+	tx.DeclineReason = result.Reason
+
+	if err := s.repo.Save(ctx, tx); err != nil {
+		if errors.Is(err, domain.ErrIdempotencyKeyUsed) {
+			return err
+		}
+		return domain.ErrStorageUnavailable
+	}
+	s.saveDeclineReport(ctx, tx, result)
+	return &domain.DeclinedError{TransactionID: tx.ID, Reason: tx.DeclineReason}
+}
+
+// saveDeclineReport stores the fraud report of a declined attempt. The decline itself is already recorded,
+// so a failure only costs the explanation and does not fail the request.
+func (s *service) saveDeclineReport(ctx context.Context, tx domain.Transaction, result domain.FraudResult) {
+	if s.reports == nil {
+		return
+	}
+	report := domain.FraudReport{
+		TransactionID: tx.ID,
+		ProcessedAt:   time.Now().UTC(),
+		CardHash:      tx.CardNumberHash,
+		MerchantID:    tx.MerchantID,
+		Country:       tx.Country(),
+		Amount:        tx.Amount,
+		Result:        result,
+	}
+	if err := s.reports.SaveFraudReports(ctx, []domain.FraudReport{report}, tx.ID.String()); err != nil {
+		s.logger.Warn("failed to save fraud report of declined transaction", "transaction_id", tx.ID, "ERROR", err)
+	}
+}
+
+// limitCounters returns the counters the transaction is accounted in, one per configured limit.
+// Amounts are counted per currency. Limits whose scope the transaction does not carry (e.g. no merchant) are skipped.
+func (s *service) limitCounters(tx domain.Transaction) []domain.LimitCounter {
+	if s.limiter == nil {
+		return nil
+	}
+
+	counters := make([]domain.LimitCounter, 0, len(s.limits))
+	for _, l := range s.limits {
+		var id string
+		switch l.Scope {
+		case domain.LimitScopeCard:
+			id = tx.CardNumberHash
+		case domain.LimitScopeMerchant:
+			id = tx.MerchantID
+		case domain.LimitScopeCustomer:
+			id = tx.CustomerID
+		}
+		if id == "" {
+			continue
+		}
+
+		start, end := l.Period.Window(tx.CreatedAt)
+		counters = append(counters, domain.LimitCounter{
+			Limit:     l,
+			Key:       fmt.Sprintf("spend_limit:%s:%s:%s:%s:%d", l.Scope, id, tx.Currency, l.Period, start.Unix()),
+			ExpiresAt: end,
+		})
+	}
+	return counters
+}
@@ -2,11 +2,15 @@ package app
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
 
 	"testing"
+	"time"
 
 	"payment-processing-system/internal/core/domain"
-	_ "payment-processing-system/internal/core/ports"
+	"payment-processing-system/internal/core/ports"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -53,7 +57,12 @@ func TestTransactionService_CreateTransaction_Success(t *testing.T) {
 	mockBroker.On("PublishTransactionCreated", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil)
 
 	// --- Act ---
-	result, err := service.CreateTransaction(ctx, 100.0, "RUB", cardNum, idemKey)
+	result, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         100.0,
+		Currency:       "RUB",
+		CardNumber:     cardNum,
+		IdempotencyKey: idemKey,
+	})
 
 	// --- Assert ---
 	assert.NoError(t, err)
@@ -68,6 +77,53 @@ func TestTransactionService_CreateTransaction_Success(t *testing.T) {
 	mockBroker.AssertExpectations(t)
 }
 
+type stubGeo struct{}
+
+func (stubGeo) IPCountry(ip string) string {
+	if ip == "203.0.113.7" {
+		return "AU"
+	}
+	return ""
+}
+
+func (stubGeo) BINCountry(bin string) string {
+	if bin == "45320151" {
+		return "US"
+	}
+	return ""
+}
+
+func TestTransactionService_CreateTransaction_BuyerSignals(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	service := NewTransactionService(mockRepo, mockBroker, WithGeoLookup(stubGeo{}))
+
+	ctx := context.Background()
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil)
+	mockBroker.On("PublishTransactionCreated", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil)
+
+	result, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         100.0,
+		Currency:       "USD",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+		Email:          "buyer@example.com",
+		Phone:          "+14155550100",
+		IP:             "203.0.113.7",
+		BillingAddress: domain.Address{Line1: "1 Main St", Country: "US"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HashPII("buyer@example.com"), result.EmailHash)
+	assert.Equal(t, "example.com", result.EmailDomain)
+	assert.Equal(t, domain.HashPII("+14155550100"), result.PhoneHash)
+	assert.Equal(t, "AU", result.IPCountry)
+	assert.Equal(t, "US", result.BINCountry)
+	assert.Equal(t, "US", result.BillingAddress.Country)
+	assert.NotEmpty(t, result.BillingAddress.Hash)
+	assert.Empty(t, result.ShippingAddress)
+}
+
 // second test
 func TestTransactionService_CreateTransaction_InvalidAmount(t *testing.T) {
 	// --- Arrange ---
@@ -77,7 +133,12 @@ func TestTransactionService_CreateTransaction_InvalidAmount(t *testing.T) {
 	ctx := context.Background()
 
 	// --- Act ---
-	_, err := service.CreateTransaction(ctx, -50.0, "RUB", "1234", uuid.New())
+	_, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         -50.0,
+		Currency:       "RUB",
+		CardNumber:     "1234",
+		IdempotencyKey: uuid.New(),
+	})
 
 	// --- Assert ---
 	assert.Error(t, err) // We are expecting an error
@@ -85,3 +146,231 @@ func TestTransactionService_CreateTransaction_InvalidAmount(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 	mockBroker.AssertNotCalled(t, "PublishTransactionCreated", mock.Anything, mock.Anything)
 }
+
+// Mock - implementation of the spending limit storage
+type MockSpendingLimiter struct {
+	mock.Mock
+}
+
+func (m *MockSpendingLimiter) Consume(ctx context.Context, counters []domain.LimitCounter, amount float64) error {
+	args := m.Called(ctx, counters, amount)
+	return args.Error(0)
+}
+
+func (m *MockSpendingLimiter) Revert(ctx context.Context, counters []domain.LimitCounter, amount float64) error {
+	args := m.Called(ctx, counters, amount)
+	return args.Error(0)
+}
+
+func TestTransactionService_CreateTransaction_LimitExceeded(t *testing.T) {
+	// --- Arrange ---
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	mockLimiter := new(MockSpendingLimiter)
+	limit := domain.SpendingLimit{Scope: domain.LimitScopeCard, Period: domain.LimitPeriodDaily, MaxAmount: 500}
+	service := NewTransactionService(mockRepo, mockBroker, WithSpendingLimits(mockLimiter, []domain.SpendingLimit{limit}))
+	ctx := context.Background()
+
+	mockLimiter.On("Consume", ctx, mock.MatchedBy(func(c []domain.LimitCounter) bool {
+		return len(c) == 1 && c[0].Limit == limit
+	}), 600.0).Return(&domain.LimitExceededError{Limit: limit, Kind: domain.LimitKindAmount})
+
+	// --- Act ---
+	_, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         600.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	})
+
+	// --- Assert ---
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	var limitErr *domain.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domain.LimitKindAmount, limitErr.Kind)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockBroker.AssertNotCalled(t, "PublishTransactionCreated", mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_CreateTransaction_SaveFailureRevertsLimits(t *testing.T) {
+	// --- Arrange ---
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	mockLimiter := new(MockSpendingLimiter)
+	limits := []domain.SpendingLimit{
+		{Scope: domain.LimitScopeCard, Period: domain.LimitPeriodDaily, MaxCount: 10},
+		{Scope: domain.LimitScopeMerchant, Period: domain.LimitPeriodMonthly, MaxAmount: 1000},
+	}
+	service := NewTransactionService(mockRepo, mockBroker, WithSpendingLimits(mockLimiter, limits))
+	ctx := context.Background()
+
+	// The transaction has no merchant, so only the card limit applies.
+	oneCounter := mock.MatchedBy(func(c []domain.LimitCounter) bool { return len(c) == 1 })
+	mockLimiter.On("Consume", ctx, oneCounter, 100.0).Return(nil)
+	mockLimiter.On("Revert", ctx, oneCounter, 100.0).Return(nil)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Return(errors.New("connection refused"))
+
+	// --- Act ---
+	_, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         100.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	})
+
+	// --- Assert ---
+	assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+	mockLimiter.AssertExpectations(t)
+	mockBroker.AssertNotCalled(t, "PublishTransactionCreated", mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_CreateTransaction_ReplayedKeyRevertsLimits(t *testing.T) {
+	// --- Arrange ---
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	mockLimiter := new(MockSpendingLimiter)
+	limits := []domain.SpendingLimit{{Scope: domain.LimitScopeCard, Period: domain.LimitPeriodDaily, MaxCount: 10}}
+	service := NewTransactionService(mockRepo, mockBroker, WithSpendingLimits(mockLimiter, limits))
+	ctx := context.Background()
+
+	mockLimiter.On("Consume", ctx, mock.Anything, 100.0).Return(nil).Twice()
+	mockLimiter.On("Revert", ctx, mock.Anything, 100.0).Return(nil).Once()
+	// The repository keeps the first transaction and rejects the second one with the same key.
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil).Once()
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Return(domain.ErrIdempotencyKeyUsed).Once()
+	mockBroker.On("PublishTransactionCreated", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil).Once()
+	in := ports.CreateTransactionInput{
+		Amount:         100.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	}
+
+	// --- Act ---
+	_, err := service.CreateTransaction(ctx, in)
+	assert.NoError(t, err)
+	_, err = service.CreateTransaction(ctx, in)
+
+	// --- Assert ---
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyUsed)
+	mockLimiter.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockBroker.AssertNumberOfCalls(t, "PublishTransactionCreated", 1)
+}
+
+// Stub - fraud engine with a fixed answer and an optional delay
+type stubFraudEngine struct {
+	result domain.FraudResult
+	delay  time.Duration
+}
+
+func (e stubFraudEngine) CheckTransaction(ctx context.Context, _ domain.Transaction) (domain.FraudResult, error) {
+	select {
+	case <-time.After(e.delay):
+		return e.result, nil
+	case <-ctx.Done():
+		return domain.FraudResult{}, ctx.Err()
+	}
+}
+
+func TestTransactionService_CreateTransaction_DeclinedByFraudCheck(t *testing.T) {
+	// --- Arrange ---
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	engine := stubFraudEngine{result: domain.FraudResult{IsFraudulent: true, Reason: "Amount exceeds threshold", Decision: domain.DecisionDecline}}
+	service := NewTransactionService(mockRepo, mockBroker, WithFraudCheck(engine, time.Second, true))
+	ctx := context.Background()
+
+	// The declined attempt is still recorded.
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Status == domain.StatusDeclined && tx.IsFraudulent && tx.DeclineReason == "Amount exceeds threshold"
+	})).Return(nil)
+
+	// --- Act ---
+	_, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         5000.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	})
+
+	// --- Assert ---
+	assert.ErrorIs(t, err, domain.ErrTransactionDeclined)
+	var declinedErr *domain.DeclinedError
+	assert.ErrorAs(t, err, &declinedErr)
+	assert.Equal(t, "Amount exceeds threshold", declinedErr.Reason)
+	mockRepo.AssertExpectations(t)
+	mockBroker.AssertNotCalled(t, "PublishTransactionCreated", mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_CreateTransaction_DeclineReportIsStored(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockBroker := new(MockBroker)
+	reports := new(MockReportRepository)
+	explanation := &domain.FraudExplanation{Engine: "rules/v1", Decision: domain.DecisionDecline, Score: 1}
+	engine := stubFraudEngine{result: domain.FraudResult{IsFraudulent: true, Reason: "Amount exceeds threshold", Decision: domain.DecisionDecline, Explanation: explanation}}
+	service := NewTransactionService(mockRepo, mockBroker,
+		WithFraudCheck(engine, time.Second, true),
+		WithFraudReports(reports, slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	ctx := context.Background()
+
+	var saved domain.Transaction
+	mockRepo.On("Save", ctx, mock.AnythingOfType("domain.Transaction")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(domain.Transaction)
+	}).Return(nil)
+	reports.On("SaveFraudReports", ctx, mock.MatchedBy(func(r []domain.FraudReport) bool {
+		return len(r) == 1 && r[0].TransactionID == saved.ID && r[0].Result.Explanation == explanation
+	}), mock.AnythingOfType("string")).Return(nil)
+
+	_, err := service.CreateTransaction(ctx, ports.CreateTransactionInput{
+		Amount:         5000.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	})
+
+	assert.ErrorIs(t, err, domain.ErrTransactionDeclined)
+	reports.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransaction_FraudCheckTimeout(t *testing.T) {
+	slow := stubFraudEngine{result: domain.FraudResult{IsFraudulent: true, Decision: domain.DecisionDecline}, delay: 200 * time.Millisecond}
+	input := ports.CreateTransactionInput{
+		Amount:         100.0,
+		Currency:       "RUB",
+		CardNumber:     "4532015112830366",
+		IdempotencyKey: uuid.New(),
+	}
+
+	t.Run("fail-open accepts", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockBroker := new(MockBroker)
+		service := NewTransactionService(mockRepo, mockBroker, WithFraudCheck(slow, 10*time.Millisecond, true))
+		ctx := context.Background()
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.Status == domain.StatusProcessing
+		})).Return(nil)
+		mockBroker.On("PublishTransactionCreated", ctx, mock.AnythingOfType("domain.Transaction")).Return(nil)
+
+		result, err := service.CreateTransaction(ctx, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusProcessing, result.Status)
+	})
+
+	t.Run("fail-closed declines", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockBroker := new(MockBroker)
+		service := NewTransactionService(mockRepo, mockBroker, WithFraudCheck(slow, 10*time.Millisecond, false))
+		ctx := context.Background()
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.Status == domain.StatusDeclined && !tx.IsFraudulent
+		})).Return(nil)
+
+		_, err := service.CreateTransaction(ctx, input)
+
+		assert.ErrorIs(t, err, domain.ErrTransactionDeclined)
+		mockBroker.AssertNotCalled(t, "PublishTransactionCreated", mock.Anything, mock.Anything)
+	})
+}
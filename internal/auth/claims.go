@@ -0,0 +1,25 @@
+package auth
+
+import "context"
+
+type contextKey string
+
+// claimsContextKey — ключ для хранения claims (JWT или OIDC) в контексте
+const claimsContextKey contextKey = "claims"
+
+// ContextWithClaims stores the verified token claims for the authorization middleware and the handlers.
+func ContextWithClaims(ctx context.Context, claims map[string]interface{}) context.Context {
+	return context.WithValue(ctx, claimsContextKey, claims)
+}
+
+// ClaimsFromContext returns the claims stored by ContextWithClaims.
+func ClaimsFromContext(ctx context.Context) (map[string]interface{}, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(map[string]interface{})
+	return claims, ok
+}
+
+// Subject returns the "sub" claim, or "" if the token has none.
+func Subject(claims map[string]interface{}) string {
+	sub, _ := claims["sub"].(string)
+	return sub
+}
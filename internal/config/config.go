@@ -11,6 +11,134 @@ type AntiFraudConfig struct {
 	AmountThreshold        float64 `yaml:"amount_threshold"`
 	FrequencyThreshold     int     `yaml:"frequency_threshold"`
 	FrequencyWindowSeconds int     `yaml:"frequency_window_seconds"`
+	// CounterKeyPrefix namespaces the Redis velocity counters, so services
+	// running the same rules do not count each other's transactions.
+	CounterKeyPrefix string `yaml:"counter_key_prefix"`
+	// RulesFile is the declarative rule set (see configs/fraud_rules.yaml).
+	// When empty, the built-in threshold and frequency rules above are used.
+	RulesFile          string `yaml:"rules_file"`
+	RulesReloadSeconds int    `yaml:"rules_reload_seconds"`
+	// Composite combines several engines; when it lists no engines, the rules above are used alone.
+	Composite CompositeConfig `yaml:"composite"`
+	// Shadow engines are evaluated by the analyzer next to the live engine;
+	// their verdicts are written to ClickHouse for comparison and never acted upon.
+	Shadow []ShadowEngineConfig `yaml:"shadow"`
+	// Lists are the blocklists and allowlists consulted before the engines above.
+	Lists ListsConfig `yaml:"lists"`
+	// Features are the aggregated features available to the rules and models.
+	Features FeaturesConfig `yaml:"features"`
+	// Rings link cards through shared devices, emails and IPs and flag large clusters.
+	Rings RingsConfig `yaml:"rings"`
+}
+
+// RingsConfig stores parameters of the fraud ring detection. The link graph is kept in Redis under KeyPrefix.
+type RingsConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	KeyPrefix string `yaml:"key_prefix"`
+	// HubCards is the number of cards after which an attribute (a carrier IP, a shared device) stops linking cards.
+	HubCards int `yaml:"hub_cards"`
+	// MaxClusterCards and MaxAttributeCards flag a transaction when exceeded; 0 disables the check.
+	MaxClusterCards   int `yaml:"max_cluster_cards"`
+	MaxAttributeCards int `yaml:"max_attribute_cards"`
+	// WindowDays bounds the retention of the graph: a link is kept for one to two windows after it was last seen,
+	// so clusters split again once the links joining them age out. Redis holds the links of two windows at most.
+	WindowDays int `yaml:"window_days"`
+	// Action is what happens to flagged transactions: review or decline.
+	Action string `yaml:"action"`
+}
+
+// FeaturesConfig stores parameters of the feature store.
+// The features are declared in File (see configs/features.yaml) and kept in Redis under KeyPrefix;
+// without a file no features are declared.
+type FeaturesConfig struct {
+	File      string `yaml:"file"`
+	KeyPrefix string `yaml:"key_prefix"`
+	// RetentionDays is how long lifetime features of an entity are kept after its last transaction.
+	RetentionDays int `yaml:"retention_days"`
+}
+
+// ListsConfig stores parameters of the blocklists and allowlists.
+// The entries are stored in Postgres and cached in Redis under KeyPrefix.
+type ListsConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	KeyPrefix string `yaml:"key_prefix"`
+	// SyncIntervalSeconds is how often the payment gateway rebuilds the cache from Postgres.
+	SyncIntervalSeconds int `yaml:"sync_interval_seconds"`
+}
+
+// ShadowEngineConfig describes one shadow engine.
+// Type "rules" evaluates RulesFile, "model" scores with ModelFile, "external" calls the scoring service at URL.
+type ShadowEngineConfig struct {
+	Name      string `yaml:"name"`
+	Type      string `yaml:"type"`
+	RulesFile string `yaml:"rules_file"`
+	ModelFile string `yaml:"model_file"`
+	URL       string `yaml:"url"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+}
+
+// CompositeConfig describes how the anti-fraud engines are combined.
+type CompositeConfig struct {
+	Mode     string `yaml:"mode"`     // parallel | sequential
+	Strategy string `yaml:"strategy"` // any_fraud | weighted_score | short_circuit
+	// Thresholds of the weighted_score strategy.
+	ReviewScore  float64                 `yaml:"review_score"`
+	DeclineScore float64                 `yaml:"decline_score"`
+	Engines      []CompositeEngineConfig `yaml:"engines"`
+}
+
+// CompositeEngineConfig describes one engine of the composite.
+// Type "rules" is the rule engine configured above, "model" scores with the gradient-boosted tree model
+// in ModelFile (reloaded like the rule file), "external" calls the scoring service at URL.
+type CompositeEngineConfig struct {
+	Name      string  `yaml:"name"`
+	Type      string  `yaml:"type"`
+	URL       string  `yaml:"url"`
+	ModelFile string  `yaml:"model_file"`
+	TimeoutMs int     `yaml:"timeout_ms"`
+	Weight    float64 `yaml:"weight"`
+	// Optional lets the check go on without this engine when it fails; by default its failure fails the check.
+	Optional bool `yaml:"optional"`
+}
+
+// PreAuthConfig stores parameters of the synchronous fraud check in the payment gateway.
+type PreAuthConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+	FailMode  string `yaml:"fail_mode"` // open | closed
+	// Engine selects where the check runs: "rules" evaluates the rules in-process,
+	// "grpc" calls the anti-fraud analyzer at GrpcAddr.
+	Engine   string `yaml:"engine"`
+	GrpcAddr string `yaml:"grpc_addr"`
+}
+
+// SpendingLimitConfig describes one spending limit enforced by the payment gateway.
+// Scope is one of card, merchant, customer; Period is one of daily, weekly, monthly.
+type SpendingLimitConfig struct {
+	Scope     string  `yaml:"scope"`
+	Period    string  `yaml:"period"`
+	MaxAmount float64 `yaml:"max_amount"`
+	MaxCount  int     `yaml:"max_count"`
+}
+
+// ReserveConfig stores parameters of the merchant reserve job.
+type ReserveConfig struct {
+	ProcessIntervalSeconds int `yaml:"process_interval_seconds"`
+}
+
+// ReviewConfig stores parameters of the manual review queue.
+type ReviewConfig struct {
+	// SLAMinutes is how long a case may wait for an analyst before it is decided automatically.
+	SLAMinutes int `yaml:"sla_minutes"`
+	// ExpiryDecision is the automatic decision: approve | reject.
+	ExpiryDecision         string `yaml:"expiry_decision"`
+	ProcessIntervalSeconds int    `yaml:"process_interval_seconds"`
+}
+
+// GeoIPConfig points to the local country databases, see the geoip package. An empty path disables that lookup.
+type GeoIPConfig struct {
+	IPDatabase  string `yaml:"ip_database"`
+	BINDatabase string `yaml:"bin_database"`
 }
 
 type ClickHouseConfig struct {
@@ -20,6 +148,51 @@ type ClickHouseConfig struct {
 	Password string `yaml:"password"`
 }
 
+// AnomalyConfig stores parameters of the merchant anomaly detection job, see the anomaly package.
+type AnomalyConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	IntervalSeconds int  `yaml:"interval_seconds"`
+	// BucketMinutes is the aggregation step; every complete bucket is compared with the baseline once.
+	BucketMinutes int `yaml:"bucket_minutes"`
+	HistoryDays   int `yaml:"history_days"`
+	// Baseline is ewma or seasonal (the same time of day over the previous days).
+	Baseline  string  `yaml:"baseline"`
+	EWMAAlpha float64 `yaml:"ewma_alpha"`
+	// MinHistoryBuckets is how many buckets with transactions a merchant needs before it is evaluated.
+	MinHistoryBuckets int `yaml:"min_history_buckets"`
+	// MinTransactions keeps the ratio metrics of buckets with too few transactions from alerting.
+	MinTransactions int `yaml:"min_transactions"`
+	// ZScores are the alert thresholds by metric: volume, avg_ticket, decline_rate, new_country_share.
+	// A metric without a threshold is not watched.
+	ZScores    map[string]float64 `yaml:"z_scores"`
+	KafkaTopic string             `yaml:"kafka_topic"`
+	// AlerterURL is the alerter-service webhook; empty disables the webhook.
+	AlerterURL string `yaml:"alerter_url"`
+}
+
+// AnalyzerConfig stores the concurrency of the anti-fraud analyzer consumer.
+type AnalyzerConfig struct {
+	// Workers is how many transactions are checked concurrently; the transactions of a card are checked in order.
+	Workers int `yaml:"workers"`
+	// MaxInFlight bounds the transactions read from Kafka and not checked yet.
+	MaxInFlight int `yaml:"max_in_flight"`
+	// MaxPartitionInFlight pauses reading a partition with that many transactions in flight.
+	MaxPartitionInFlight int `yaml:"max_partition_in_flight"`
+	CommitIntervalMs     int `yaml:"commit_interval_ms"`
+	// BatchSize and FlushIntervalMs trigger a flush of the fraud reports to ClickHouse, whichever comes first;
+	// the history, feature snapshots and shadow verdicts of the written reports are flushed the same way.
+	BatchSize       int `yaml:"batch_size"`
+	FlushIntervalMs int `yaml:"flush_interval_ms"`
+	// WriteAttempts is how many times a batch is written, with a backoff doubling from WriteBackoffMs,
+	// before its transactions go to the DLQ.
+	WriteAttempts  int `yaml:"write_attempts"`
+	WriteBackoffMs int `yaml:"write_backoff_ms"`
+	// RetryDelaysSeconds are the tiers of the retry ladder: a transaction that failed with a transient error
+	// is checked again after each delay in turn and goes to the DLQ after the last one.
+	RetryDelaysSeconds []int `yaml:"retry_delays_seconds"`
+}
+
+
 type Config struct {
 	App struct {
 		Env string `yaml:"env"`
@@ -27,6 +200,9 @@ type Config struct {
 	Server struct {
 		Port string `yaml:"port"`
 		PortAlerter string `yaml:"port_alerter"`
+		PortAntiFraudGrpc string `yaml:"port_antifraud_grpc"`
+		PortAntiFraudHTTP string `yaml:"port_antifraud_http"`
+		PortAntiFraudAdmin string `yaml:"port_antifraud_admin"`
 	} `yaml:"server"`
 	Postgres struct {
 		DSN string `yaml:"dsn"`
@@ -34,6 +210,10 @@ type Config struct {
 	Kafka struct {
 		BootstrapServers string `yaml:"bootstrap_servers"`
 		Topic            string `yaml:"topic"`
+		// Serialization is the wire format of the events: json, protobuf or avro.
+		// protobuf and avro keep their schemas in the schema registry.
+		Serialization     string `yaml:"serialization"`
+		SchemaRegistryURL string `yaml:"schema_registry_url"`
 	} `yaml:"kafka"`
 	ClickHouse ClickHouseConfig `yaml:"clickhouse"`
 	Redis struct {
@@ -54,6 +234,13 @@ type Config struct {
 		JWTSecret string `yaml:"jwt_secret"`
 	} `yaml:"jwt"`
 	AntiFraud AntiFraudConfig `yaml:"anti_fraud"`
+	Reserve   ReserveConfig   `yaml:"reserve"`
+	Review    ReviewConfig    `yaml:"review"`
+	GeoIP     GeoIPConfig     `yaml:"geoip"`
+	SpendingLimits []SpendingLimitConfig `yaml:"spending_limits"`
+	PreAuth        PreAuthConfig         `yaml:"pre_auth"`
+	Anomaly        AnomalyConfig         `yaml:"anomaly"`
+	Analyzer       AnalyzerConfig        `yaml:"analyzer"`
 }
 
 func Load(configPath string) (*Config, error) {
@@ -80,6 +267,377 @@ func Load(configPath string) (*Config, error) {
 	if config.AntiFraud.FrequencyWindowSeconds == 0 {
 		config.AntiFraud.FrequencyWindowSeconds = 60
 	}
+	if config.AntiFraud.CounterKeyPrefix == "" {
+		config.AntiFraud.CounterKeyPrefix = "card_tx_count"
+	}
+	if config.AntiFraud.RulesReloadSeconds < 0 {
+		return nil, fmt.Errorf("invalid anti_fraud.rules_reload_seconds %d: must not be negative", config.AntiFraud.RulesReloadSeconds)
+	}
+	if config.AntiFraud.RulesReloadSeconds == 0 {
+		config.AntiFraud.RulesReloadSeconds = 10
+	}
+	if config.AntiFraud.Lists.KeyPrefix == "" {
+		config.AntiFraud.Lists.KeyPrefix = "fraud_lists"
+	}
+	if config.AntiFraud.Lists.SyncIntervalSeconds < 0 {
+		return nil, fmt.Errorf("invalid anti_fraud.lists.sync_interval_seconds %d: must not be negative", config.AntiFraud.Lists.SyncIntervalSeconds)
+	}
+	if config.AntiFraud.Lists.SyncIntervalSeconds == 0 {
+		config.AntiFraud.Lists.SyncIntervalSeconds = 300
+	}
+	if config.AntiFraud.Features.KeyPrefix == "" {
+		config.AntiFraud.Features.KeyPrefix = "fraud_features"
+	}
+	if config.AntiFraud.Features.RetentionDays < 0 {
+		return nil, fmt.Errorf("invalid anti_fraud.features.retention_days %d: must not be negative", config.AntiFraud.Features.RetentionDays)
+	}
+	if config.AntiFraud.Features.RetentionDays == 0 {
+		config.AntiFraud.Features.RetentionDays = 180
+	}
+	if err := config.AntiFraud.Rings.applyDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid anti_fraud.rings: %w", err)
+	}
+	if err := config.AntiFraud.Composite.applyDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid anti_fraud.composite: %w", err)
+	}
+	shadowNames := make(map[string]bool, len(config.AntiFraud.Shadow))
+	for i := range config.AntiFraud.Shadow {
+		sc := &config.AntiFraud.Shadow[i]
+		if err := sc.applyDefaults(); err != nil {
+			return nil, fmt.Errorf("invalid anti_fraud.shadow[%d]: %w", i, err)
+		}
+		if shadowNames[sc.Name] {
+			return nil, fmt.Errorf("invalid anti_fraud.shadow[%d]: duplicate name %q", i, sc.Name)
+		}
+		shadowNames[sc.Name] = true
+	}
+	if config.PreAuth.TimeoutMs < 0 {
+		return nil, fmt.Errorf("invalid pre_auth.timeout_ms %d: must not be negative", config.PreAuth.TimeoutMs)
+	}
+	if config.PreAuth.TimeoutMs == 0 {
+		config.PreAuth.TimeoutMs = 150
+	}
+	switch config.PreAuth.Engine {
+	case "":
+		config.PreAuth.Engine = "rules"
+	case "rules":
+	case "grpc":
+		if config.PreAuth.GrpcAddr == "" {
+			return nil, fmt.Errorf("pre_auth.grpc_addr is required for the grpc engine")
+		}
+	default:
+		return nil, fmt.Errorf("invalid pre_auth.engine %q: expected rules or grpc", config.PreAuth.Engine)
+	}
+	if config.Server.PortAntiFraudGrpc == "" {
+		config.Server.PortAntiFraudGrpc = ":9091"
+	}
+	if config.Server.PortAntiFraudHTTP == "" {
+		config.Server.PortAntiFraudHTTP = ":8082"
+	}
+	// Admin routes have no authentication: reachable only from the host of the analyzer unless configured otherwise.
+	if config.Server.PortAntiFraudAdmin == "" {
+		config.Server.PortAntiFraudAdmin = "127.0.0.1:8083"
+	}
+	switch config.PreAuth.FailMode {
+	case "":
+		config.PreAuth.FailMode = "open"
+	case "open", "closed":
+	default:
+		return nil, fmt.Errorf("invalid pre_auth.fail_mode %q: expected open or closed", config.PreAuth.FailMode)
+	}
+	if config.Reserve.ProcessIntervalSeconds < 0 {
+		return nil, fmt.Errorf("invalid reserve.process_interval_seconds %d: must not be negative", config.Reserve.ProcessIntervalSeconds)
+	}
+	if config.Reserve.ProcessIntervalSeconds == 0 {
+		config.Reserve.ProcessIntervalSeconds = 300
+	}
+	if config.Review.SLAMinutes < 0 {
+		return nil, fmt.Errorf("invalid review.sla_minutes %d: must not be negative", config.Review.SLAMinutes)
+	}
+	if config.Review.SLAMinutes == 0 {
+		config.Review.SLAMinutes = 240
+	}
+	switch config.Review.ExpiryDecision {
+	case "":
+		config.Review.ExpiryDecision = "reject"
+	case "approve", "reject":
+	default:
+		return nil, fmt.Errorf("invalid review.expiry_decision %q: expected approve or reject", config.Review.ExpiryDecision)
+	}
+	if config.Review.ProcessIntervalSeconds < 0 {
+		return nil, fmt.Errorf("invalid review.process_interval_seconds %d: must not be negative", config.Review.ProcessIntervalSeconds)
+	}
+	if config.Review.ProcessIntervalSeconds == 0 {
+		config.Review.ProcessIntervalSeconds = 60
+	}
+	// Limits of one scope and period share a counter, so they must be declared as a single entry.
+	limitPeriods := make(map[string]bool, len(config.SpendingLimits))
+	for i, l := range config.SpendingLimits {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("invalid spending_limits[%d]: %w", i, err)
+		}
+		if limitPeriods[l.Scope+"/"+l.Period] {
+			return nil, fmt.Errorf("invalid spending_limits[%d]: duplicate %s %s limit, set max_amount and max_count in one entry", i, l.Scope, l.Period)
+		}
+		limitPeriods[l.Scope+"/"+l.Period] = true
+	}
+	if err := config.Anomaly.applyDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid anomaly: %w", err)
+	}
+	if err := config.Analyzer.applyDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid analyzer: %w", err)
+	}
+	switch config.Kafka.Serialization {
+	case "":
+		config.Kafka.Serialization = "json"
+	case "json":
+	case "protobuf", "avro":
+		if config.Kafka.SchemaRegistryURL == "" {
+			return nil, fmt.Errorf("invalid kafka: serialization %q needs schema_registry_url", config.Kafka.Serialization)
+		}
+	default:
+		return nil, fmt.Errorf("invalid kafka: unknown serialization %q", config.Kafka.Serialization)
+	}
 	return config, nil
 
-}
\ No newline at end of file
+}
+
+func (l SpendingLimitConfig) validate() error {
+	switch l.Scope {
+	case "card", "merchant", "customer":
+	default:
+		return fmt.Errorf("unknown scope %q", l.Scope)
+	}
+	switch l.Period {
+	case "daily", "weekly", "monthly":
+	default:
+		return fmt.Errorf("unknown period %q", l.Period)
+	}
+	if l.MaxAmount < 0 || l.MaxCount < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	if l.MaxAmount == 0 && l.MaxCount == 0 {
+		return fmt.Errorf("either max_amount or max_count must be set")
+	}
+	return nil
+}
+
+func (c *CompositeConfig) applyDefaults() error {
+	if len(c.Engines) == 0 {
+		return nil
+	}
+	switch c.Mode {
+	case "":
+		c.Mode = "parallel"
+	case "parallel", "sequential":
+	default:
+		return fmt.Errorf("unknown mode %q", c.Mode)
+	}
+	switch c.Strategy {
+	case "":
+		c.Strategy = "any_fraud"
+	case "any_fraud", "weighted_score", "short_circuit":
+	default:
+		return fmt.Errorf("unknown strategy %q", c.Strategy)
+	}
+	if c.ReviewScore == 0 {
+		c.ReviewScore = 0.5
+	}
+	if c.DeclineScore == 0 {
+		c.DeclineScore = 0.9
+	}
+	if c.ReviewScore > c.DeclineScore || c.DeclineScore > 1 {
+		return fmt.Errorf("thresholds must satisfy review_score <= decline_score <= 1")
+	}
+
+	names := make(map[string]bool, len(c.Engines))
+	for i := range c.Engines {
+		e := &c.Engines[i]
+		switch e.Type {
+		case "rules":
+		case "model":
+			if e.ModelFile == "" {
+				return fmt.Errorf("engines[%d]: model_file is required for the model engine", i)
+			}
+		case "external":
+			if e.URL == "" {
+				return fmt.Errorf("engines[%d]: url is required for the external engine", i)
+			}
+		default:
+			return fmt.Errorf("engines[%d]: unknown type %q", i, e.Type)
+		}
+		if e.Name == "" {
+			e.Name = e.Type
+		}
+		if names[e.Name] {
+			return fmt.Errorf("engines[%d]: duplicate name %q", i, e.Name)
+		}
+		names[e.Name] = true
+		if e.TimeoutMs == 0 {
+			e.TimeoutMs = 100
+		}
+		if e.Weight == 0 {
+			e.Weight = 1
+		}
+		if e.TimeoutMs < 0 || e.Weight < 0 {
+			return fmt.Errorf("engines[%d]: timeout_ms and weight must not be negative", i)
+		}
+	}
+	return nil
+}
+
+func (c *RingsConfig) applyDefaults() error {
+	if c.KeyPrefix == "" {
+		c.KeyPrefix = "fraud_rings"
+	}
+	if c.HubCards == 0 {
+		c.HubCards = 100
+	}
+	if c.WindowDays == 0 {
+		c.WindowDays = 30
+	}
+	switch c.Action {
+	case "":
+		c.Action = "review"
+	case "review", "decline":
+	default:
+		return fmt.Errorf("unknown action %q, expected review or decline", c.Action)
+	}
+	if c.HubCards < 0 || c.MaxClusterCards < 0 || c.MaxAttributeCards < 0 || c.WindowDays < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	// The card set of an attribute stops growing past HubCards, so larger limits could never be exceeded.
+	if c.MaxAttributeCards >= c.HubCards {
+		return fmt.Errorf("max_attribute_cards %d must be below hub_cards %d", c.MaxAttributeCards, c.HubCards)
+	}
+	return nil
+}
+
+func (c *AnalyzerConfig) applyDefaults() error {
+	if c.Workers == 0 {
+		c.Workers = 8
+	}
+	if c.MaxInFlight == 0 {
+		c.MaxInFlight = 1000
+	}
+	if c.MaxPartitionInFlight == 0 {
+		c.MaxPartitionInFlight = 200
+	}
+	if c.CommitIntervalMs == 0 {
+		c.CommitIntervalMs = 1000
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = 500
+	}
+	if c.FlushIntervalMs == 0 {
+		c.FlushIntervalMs = 1000
+	}
+	if c.WriteAttempts == 0 {
+		c.WriteAttempts = 5
+	}
+	if c.WriteBackoffMs == 0 {
+		c.WriteBackoffMs = 200
+	}
+	if c.RetryDelaysSeconds == nil {
+		c.RetryDelaysSeconds = []int{60, 600, 3600}
+	}
+	for _, d := range c.RetryDelaysSeconds {
+		if d <= 0 {
+			return fmt.Errorf("retry_delays_seconds must be positive")
+		}
+	}
+	if c.Workers < 0 || c.MaxPartitionInFlight < 0 || c.CommitIntervalMs < 0 ||
+		c.BatchSize < 0 || c.FlushIntervalMs < 0 || c.WriteAttempts < 0 || c.WriteBackoffMs < 0 {
+		return fmt.Errorf("analyzer settings must not be negative")
+	}
+	if c.MaxInFlight < c.Workers {
+		return fmt.Errorf("max_in_flight must be at least workers")
+	}
+	if c.MaxPartitionInFlight > c.MaxInFlight {
+		return fmt.Errorf("max_partition_in_flight must not exceed max_in_flight")
+	}
+	return nil
+}
+
+func (c *AnomalyConfig) applyDefaults() error {
+	if c.IntervalSeconds == 0 {
+		c.IntervalSeconds = 300
+	}
+	if c.BucketMinutes == 0 {
+		c.BucketMinutes = 60
+	}
+	if c.HistoryDays == 0 {
+		c.HistoryDays = 14
+	}
+	if c.EWMAAlpha == 0 {
+		c.EWMAAlpha = 0.1
+	}
+	if c.MinHistoryBuckets == 0 {
+		c.MinHistoryBuckets = 24
+	}
+	if c.MinTransactions == 0 {
+		c.MinTransactions = 20
+	}
+	if c.KafkaTopic == "" {
+		c.KafkaTopic = "merchant.anomalies"
+	}
+	if c.ZScores == nil {
+		c.ZScores = map[string]float64{"volume": 4, "avg_ticket": 4, "decline_rate": 3, "new_country_share": 3}
+	}
+	switch c.Baseline {
+	case "":
+		c.Baseline = "ewma"
+	case "ewma", "seasonal":
+	default:
+		return fmt.Errorf("unknown baseline %q, expected ewma or seasonal", c.Baseline)
+	}
+	if c.IntervalSeconds < 0 || c.BucketMinutes < 0 || c.HistoryDays < 0 || c.MinHistoryBuckets < 0 || c.MinTransactions < 0 {
+		return fmt.Errorf("intervals and minimums must not be negative")
+	}
+	if c.EWMAAlpha <= 0 || c.EWMAAlpha > 1 {
+		return fmt.Errorf("ewma_alpha must be within (0, 1]")
+	}
+	if c.Baseline == "seasonal" && (24*60)%c.BucketMinutes != 0 {
+		return fmt.Errorf("the seasonal baseline needs bucket_minutes that divide a day")
+	}
+	for metric, z := range c.ZScores {
+		switch metric {
+		case "volume", "avg_ticket", "decline_rate", "new_country_share":
+		default:
+			return fmt.Errorf("z_scores: unknown metric %q", metric)
+		}
+		if z <= 0 {
+			return fmt.Errorf("z_scores: %s must be positive", metric)
+		}
+	}
+	return nil
+}
+
+func (c *ShadowEngineConfig) applyDefaults() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch c.Type {
+	case "rules":
+		if c.RulesFile == "" {
+			return fmt.Errorf("rules_file is required for the rules engine")
+		}
+	case "model":
+		if c.ModelFile == "" {
+			return fmt.Errorf("model_file is required for the model engine")
+		}
+	case "external":
+		if c.URL == "" {
+			return fmt.Errorf("url is required for the external engine")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", c.Type)
+	}
+	if c.TimeoutMs == 0 {
+		c.TimeoutMs = 200
+	}
+	if c.TimeoutMs < 0 {
+		return fmt.Errorf("timeout_ms must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MerchantMetric is a merchant-level quantity watched for anomalies.
+type MerchantMetric string
+
+const (
+	MetricVolume          MerchantMetric = "volume"            // number of transactions
+	MetricAvgTicket       MerchantMetric = "avg_ticket"        // average amount
+	MetricDeclineRate     MerchantMetric = "decline_rate"      // share of transactions declined by the fraud engine
+	MetricNewCountryShare MerchantMetric = "new_country_share" // share of transactions from countries not seen before
+)
+
+// Valid reports whether m is a known metric.
+func (m MerchantMetric) Valid() bool {
+	switch m {
+	case MetricVolume, MetricAvgTicket, MetricDeclineRate, MetricNewCountryShare:
+		return true
+	}
+	return false
+}
+
+// MerchantActivity is what a merchant processed in one time bucket from one country.
+// The country is the card issuer country, or the IP country when the issuer is unknown; "" when both are.
+type MerchantActivity struct {
+	MerchantID   string
+	BucketStart  time.Time
+	Country      string
+	Transactions int
+	Amount       float64
+	Declines     int
+}
+
+// MerchantBucket is what a merchant processed in one time bucket.
+type MerchantBucket struct {
+	Start        time.Time
+	Transactions int
+	Amount       float64
+	Declines     int
+	// NewCountry counts the transactions from countries the merchant had not seen before the bucket.
+	NewCountry int
+}
+
+// Value returns the metric of the bucket; ok is false when it is undefined, e.g. a rate without transactions.
+func (b MerchantBucket) Value(m MerchantMetric) (v float64, ok bool) {
+	if m == MetricVolume {
+		return float64(b.Transactions), true
+	}
+	if b.Transactions == 0 {
+		return 0, false
+	}
+	n := float64(b.Transactions)
+	switch m {
+	case MetricAvgTicket:
+		return b.Amount / n, true
+	case MetricDeclineRate:
+		return float64(b.Declines) / n, true
+	case MetricNewCountryShare:
+		return float64(b.NewCountry) / n, true
+	}
+	return 0, false
+}
+
+// MerchantAnomaly is a merchant metric that deviates from its baseline by at least the configured z-score.
+type MerchantAnomaly struct {
+	// ID is stable for a merchant, metric and bucket, so consumers can drop repeats.
+	ID          string
+	MerchantID  string
+	Metric      MerchantMetric
+	BucketStart time.Time
+	BucketEnd   time.Time
+	Value       float64
+	Baseline    float64
+	StdDev      float64
+	ZScore      float64
+	Threshold   float64
+	DetectedAt  time.Time
+}
+
+// AnomalyID returns the ID of the anomaly of a merchant metric in the bucket starting at start.
+func AnomalyID(merchantID string, m MerchantMetric, start time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", merchantID, m, start.Unix())
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
 
 var (
 	ErrInvalidAmount         = errors.New("amount must be positive")
@@ -8,4 +12,33 @@ var (
 	ErrIdempotencyKeyUsed    = errors.New("idempotency key already used")
 	ErrBrokerUnavailable     = errors.New("kafka broker is unavailable")
 	ErrStorageUnavailable    = errors.New("database is unavailable")
-)
\ No newline at end of file
+	ErrInvalidReserveConfig  = errors.New("invalid reserve configuration")
+	ErrReserveConfigNotFound = errors.New("reserve configuration not found")
+	ErrLimitExceeded         = errors.New("spending limit exceeded")
+	ErrTransactionDeclined   = errors.New("transaction declined")
+	ErrReviewCaseNotFound    = errors.New("review case not found")
+	ErrReviewCaseClosed      = errors.New("review case already decided")
+	ErrReviewCaseClaimed     = errors.New("review case claimed by another analyst")
+	// ErrReviewCaseConflict is returned when a case changed between reading and updating it.
+	ErrReviewCaseConflict  = errors.New("review case was changed concurrently")
+	ErrInvalidListEntry    = errors.New("invalid list entry")
+	ErrListEntryNotFound   = errors.New("list entry not found")
+	ErrInvalidSignal       = errors.New("invalid buyer signal")
+	ErrInvalidLabel        = errors.New("invalid fraud label")
+	ErrFraudReportNotFound = errors.New("fraud report not found")
+)
+
+// DeclinedError is returned when a transaction was recorded but rejected by the pre-authorization fraud check.
+// It matches ErrTransactionDeclined with errors.Is.
+type DeclinedError struct {
+	TransactionID uuid.UUID
+	Reason        string
+}
+
+func (e *DeclinedError) Error() string {
+	return "transaction declined: " + e.Reason
+}
+
+func (e *DeclinedError) Unwrap() error {
+	return ErrTransactionDeclined
+}
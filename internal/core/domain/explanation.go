@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FraudExplanation is the structured account of how an engine reached its decision.
+type FraudExplanation struct {
+	Engine   string        `json:"engine"`
+	Decision FraudDecision `json:"decision"`
+	Score    float64       `json:"score"`
+	// The thresholds the score was compared with; zero when the engine has none.
+	ReviewThreshold  float64 `json:"review_threshold,omitempty"`
+	DeclineThreshold float64 `json:"decline_threshold,omitempty"`
+	// Rules lists every rule evaluated, matched or not, in evaluation order.
+	Rules []RuleEvaluation `json:"rules,omitempty"`
+	// Inputs are the values the decision was based on, e.g. "amount" or "velocity_count:card:1h".
+	// An unknown value is null.
+	Inputs map[string]any `json:"inputs,omitempty"`
+}
+
+// RuleEvaluation is the outcome of one rule.
+type RuleEvaluation struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Condition   string `json:"condition,omitempty"`
+	Matched     bool   `json:"matched"`
+	Action      string `json:"action,omitempty"`
+	// Score is what the rule added to the total score; zero when it did not match.
+	Score float64 `json:"score"`
+	// Inputs names the explanation inputs the rule read.
+	Inputs []string `json:"inputs,omitempty"`
+}
+
+// Explain returns the explanation of the result. Engines that do not explain their decisions
+// get one built from the result itself, with the triggered rules as the matched ones.
+func (r FraudResult) Explain() FraudExplanation {
+	if r.Explanation != nil {
+		return *r.Explanation
+	}
+	e := FraudExplanation{Engine: r.EngineVersion, Decision: r.Decision, Score: r.RiskScore}
+	for _, name := range r.TriggeredRules {
+		e.Rules = append(e.Rules, RuleEvaluation{Name: name, Matched: true})
+	}
+	return e
+}
+
+// FraudReport is the stored outcome of the fraud check of a transaction.
+type FraudReport struct {
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	ProcessedAt   time.Time   `json:"processed_at"`
+	CardHash      string      `json:"card_hash,omitempty"`
+	MerchantID    string      `json:"merchant_id,omitempty"`
+	Country       string      `json:"country,omitempty"`
+	Amount        float64     `json:"amount"`
+	Result        FraudResult `json:"result"`
+}
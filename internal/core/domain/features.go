@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeatureAggregate is how a feature summarizes the transactions of an entity.
+type FeatureAggregate string
+
+const (
+	FeatureCount    FeatureAggregate = "count"    // number of transactions
+	FeatureSum      FeatureAggregate = "sum"      // summed amount
+	FeatureAvg      FeatureAggregate = "avg"      // average amount, 0 without transactions
+	FeatureDistinct FeatureAggregate = "distinct" // number of distinct values of Field
+	// FeatureFirstSeen is the number of seconds since the first transaction of the entity, 0 for a new entity.
+	FeatureFirstSeen FeatureAggregate = "first_seen"
+)
+
+var featureNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// FeatureDefinition declares one aggregated feature, e.g. the distinct merchants of a card in 24 hours.
+// Features describe the history of the entity before the transaction being evaluated.
+type FeatureDefinition struct {
+	Name      string
+	Entity    VelocityDimension // whose transactions are aggregated
+	Aggregate FeatureAggregate
+	// Field is the attribute counted by FeatureDistinct.
+	Field VelocityDimension
+	// Window is the trailing window; zero aggregates the whole lifetime of the entity.
+	// FeatureFirstSeen is always a lifetime feature.
+	Window time.Duration
+}
+
+// Validate checks the definition.
+func (d FeatureDefinition) Validate() error {
+	if !featureNameRe.MatchString(d.Name) {
+		return fmt.Errorf("feature name %q must be snake_case", d.Name)
+	}
+	if !d.Entity.Valid() {
+		return fmt.Errorf("feature %s: unknown entity %q", d.Name, d.Entity)
+	}
+	switch d.Aggregate {
+	case FeatureCount, FeatureSum, FeatureAvg:
+	case FeatureDistinct:
+		if !d.Field.Valid() || d.Field == d.Entity {
+			return fmt.Errorf("feature %s: distinct needs a field other than the entity, got %q", d.Name, d.Field)
+		}
+	case FeatureFirstSeen:
+		if d.Window != 0 {
+			return fmt.Errorf("feature %s: first_seen has no window", d.Name)
+		}
+	default:
+		return fmt.Errorf("feature %s: unknown aggregate %q", d.Name, d.Aggregate)
+	}
+	if d.Aggregate != FeatureDistinct && d.Field != "" {
+		return fmt.Errorf("feature %s: field is only used by distinct", d.Name)
+	}
+	if d.Window < 0 {
+		return fmt.Errorf("feature %s: window must not be negative", d.Name)
+	}
+	return nil
+}
+
+// FeatureValues maps feature names to values. Features whose entity the transaction does not carry are absent.
+type FeatureValues map[string]float64
+
+// FeatureSnapshot is the value of every declared feature when the fraud decision on a transaction was made.
+// Training datasets are built from snapshots, so a model learns from what the engines saw.
+type FeatureSnapshot struct {
+	TransactionID uuid.UUID
+	DecidedAt     time.Time
+	EngineVersion string
+	Features      FeatureValues
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Label sources reported from outside the review queue.
+const (
+	LabelSourceChargeback     = "chargeback"      // a chargeback with a fraud reason code confirms fraud
+	LabelSourceMerchantReport = "merchant_report" // the merchant reports the outcome of its own investigation
+)
+
+// maxLabelNote bounds the free-text note of a label.
+const maxLabelNote = 1000
+
+// ValidReportedLabelSource reports whether a label with this source may be recorded through the label API.
+// Review labels are only written by the review queue.
+func ValidReportedLabelSource(source string) bool {
+	return source == LabelSourceChargeback || source == LabelSourceMerchantReport
+}
+
+// Validate checks a label before it is recorded. A label must not be dated in the future,
+// otherwise training exports as of an earlier date would see it.
+func (l FraudLabel) Validate(now time.Time) error {
+	if l.TransactionID == uuid.Nil {
+		return fmt.Errorf("%w: transaction_id is required", ErrInvalidLabel)
+	}
+	if l.LabeledAt.After(now) {
+		return fmt.Errorf("%w: labeled_at is in the future", ErrInvalidLabel)
+	}
+	if len(l.Note) > maxLabelNote {
+		return fmt.Errorf("%w: note is longer than %d bytes", ErrInvalidLabel, maxLabelNote)
+	}
+	return nil
+}
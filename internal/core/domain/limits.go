@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitScope tells which party a spending limit applies to.
+type LimitScope string
+
+const (
+	LimitScopeCard     LimitScope = "card"
+	LimitScopeMerchant LimitScope = "merchant"
+	LimitScopeCustomer LimitScope = "customer"
+)
+
+// LimitPeriod is the calendar window a spending limit is counted over (UTC).
+type LimitPeriod string
+
+const (
+	LimitPeriodDaily   LimitPeriod = "daily"
+	LimitPeriodWeekly  LimitPeriod = "weekly"
+	LimitPeriodMonthly LimitPeriod = "monthly"
+)
+
+// LimitKind tells whether the amount or the number of transactions went over the limit.
+type LimitKind string
+
+const (
+	LimitKindAmount LimitKind = "amount"
+	LimitKindCount  LimitKind = "count"
+)
+
+// SpendingLimit caps the total amount and/or the number of transactions of one scope within a period.
+// A zero MaxAmount or MaxCount means that dimension is not limited.
+type SpendingLimit struct {
+	Scope     LimitScope
+	Period    LimitPeriod
+	MaxAmount float64
+	MaxCount  int
+}
+
+// Window returns the bounds of the calendar period that contains t.
+func (p LimitPeriod) Window(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case LimitPeriodWeekly:
+		// Weeks start on Monday.
+		offset := (int(day.Weekday()) + 6) % 7
+		start = day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case LimitPeriodMonthly:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
+
+// LimitCounter is the storage counter a transaction is accounted in for one limit.
+type LimitCounter struct {
+	Limit     SpendingLimit
+	Key       string
+	ExpiresAt time.Time
+}
+
+// LimitExceededError reports which limit a transaction would have exceeded.
+// It matches ErrLimitExceeded with errors.Is.
+type LimitExceededError struct {
+	Limit SpendingLimit
+	Kind  LimitKind
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s %s %s limit exceeded", e.Limit.Period, e.Limit.Scope, e.Kind)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitPeriod_Window(t *testing.T) {
+	// Wednesday, 2025-03-12 15:04 UTC
+	ts := time.Date(2025, 3, 12, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		period     LimitPeriod
+		start, end time.Time
+	}{
+		{LimitPeriodDaily, time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{LimitPeriodWeekly, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{LimitPeriodMonthly, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			start, end := tt.period.Window(ts)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestLimitPeriod_WindowOnSunday(t *testing.T) {
+	sunday := time.Date(2025, 3, 16, 23, 59, 0, 0, time.UTC)
+
+	start, _ := LimitPeriodWeekly.Window(sunday)
+
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), start)
+}
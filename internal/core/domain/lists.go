@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListType tells what happens to a transaction matching a list entry.
+type ListType string
+
+const (
+	// ListBlock declines matching transactions whatever the other rules say.
+	ListBlock ListType = "block"
+	// ListAllow trusts matching transactions: they are allowed unless they are also blocklisted.
+	ListAllow ListType = "allow"
+)
+
+// Valid reports whether t is a known list type.
+func (t ListType) Valid() bool {
+	return t == ListBlock || t == ListAllow
+}
+
+// ListKind is the transaction attribute a list entry is keyed by.
+type ListKind string
+
+const (
+	ListCard   ListKind = "card"   // card fingerprint, i.e. Transaction.CardNumberHash
+	ListIP     ListKind = "ip"     // IP address or CIDR range
+	ListEmail  ListKind = "email"  // customer email, matched by Transaction.EmailHash
+	ListDevice ListKind = "device" // device fingerprint
+	ListBIN    ListKind = "bin"    // first 6 to 8 digits of the card number
+)
+
+// ListKinds are all list kinds, in a stable order.
+var ListKinds = []ListKind{ListCard, ListIP, ListEmail, ListDevice, ListBIN}
+
+// Valid reports whether k is a known list kind.
+func (k ListKind) Valid() bool {
+	for _, known := range ListKinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// NamesBuyer reports whether an entry of kind k stands for one buyer. Other kinds are shared by many buyers
+// (an issuer BIN, a carrier IP) or sent by the merchant, so an allowlist entry of those kinds must not clear
+// a transaction on its own.
+func (k ListKind) NamesBuyer() bool {
+	return k == ListCard || k == ListEmail
+}
+
+// ListEntry is one value on a blocklist or an allowlist. An entry is unique per list, kind and value.
+type ListEntry struct {
+	ID        uuid.UUID
+	List      ListType
+	Kind      ListKind
+	Value     string
+	Reason    string
+	CreatedBy string
+	CreatedAt time.Time
+	// ExpiresAt is nil for permanent entries.
+	ExpiresAt *time.Time
+}
+
+// Active reports whether the entry has not expired at now.
+func (e ListEntry) Active(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+// IsRange reports whether the entry is an IP range rather than a single value.
+func (e ListEntry) IsRange() bool {
+	return e.Kind == ListIP && strings.Contains(e.Value, "/")
+}
+
+// LookupValue returns the value a transaction attribute is compared with. Emails are listed in clear text
+// for the analysts but transactions only carry their hash, so email entries are looked up by HashPII of the value.
+func (e ListEntry) LookupValue() string {
+	if e.Kind == ListEmail {
+		return HashPII(e.Value)
+	}
+	return e.Value
+}
+
+// Matches reports whether the normalized value of a transaction attribute of the entry kind matches the entry.
+func (e ListEntry) Matches(value string) bool {
+	if !e.IsRange() {
+		return e.LookupValue() == value
+	}
+	prefix, err := netip.ParsePrefix(e.Value)
+	if err != nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(value)
+	return err == nil && prefix.Contains(addr.Unmap())
+}
+
+// ListSubject is a transaction attribute looked up in the lists. Email subjects carry the hash of the address.
+type ListSubject struct {
+	Kind  ListKind
+	Value string
+}
+
+// NormalizeListValue validates value for kind and returns its canonical form, the one stored and compared.
+// Errors match ErrInvalidListEntry.
+func NormalizeListValue(kind ListKind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("%w: value is required", ErrInvalidListEntry)
+	}
+
+	switch kind {
+	case ListCard:
+		value = strings.ToLower(value)
+		if len(value) != 64 || strings.Trim(value, "0123456789abcdef") != "" {
+			return "", fmt.Errorf("%w: card fingerprint must be a hex SHA-256 hash", ErrInvalidListEntry)
+		}
+		return value, nil
+
+	case ListIP:
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid CIDR %q", ErrInvalidListEntry, value)
+			}
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()).Masked()
+			if prefix.IsSingleIP() {
+				return prefix.Addr().String(), nil
+			}
+			return prefix.String(), nil
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid IP address %q", ErrInvalidListEntry, value)
+		}
+		return addr.Unmap().String(), nil
+
+	case ListEmail:
+		email, err := NormalizeEmail(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid email %q", ErrInvalidListEntry, value)
+		}
+		return email, nil
+
+	case ListDevice:
+		device, err := NormalizeDeviceID(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: device fingerprint is longer than %d characters", ErrInvalidListEntry, maxDeviceIDLength)
+		}
+		return device, nil
+
+	case ListBIN:
+		if len(value) < 6 || len(value) > 8 || strings.Trim(value, "0123456789") != "" {
+			return "", fmt.Errorf("%w: BIN must be 6 to 8 digits", ErrInvalidListEntry)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("%w: unknown kind %q", ErrInvalidListEntry, kind)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeListValue(t *testing.T) {
+	tests := []struct {
+		kind  ListKind
+		value string
+		want  string
+	}{
+		{ListIP, " 10.1.2.3 ", "10.1.2.3"},
+		{ListIP, "10.1.2.3/8", "10.0.0.0/8"},
+		{ListIP, "::ffff:192.0.2.1", "192.0.2.1"},
+		{ListIP, "2001:db8::1/128", "2001:db8::1"},
+		{ListEmail, "Fraudster@Example.COM", "fraudster@example.com"},
+		{ListBIN, "41111111", "41111111"},
+		{ListCard, "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind)+"/"+tt.value, func(t *testing.T) {
+			got, err := NormalizeListValue(tt.kind, tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for kind, value := range map[ListKind]string{
+		ListIP:    "10.0.0.300",
+		ListEmail: "John <john@example.com>",
+		ListBIN:   "4111",
+		ListCard:  "4111111111111111",
+		"phone":   "+100000000",
+	} {
+		_, err := NormalizeListValue(kind, value)
+		assert.ErrorIs(t, err, ErrInvalidListEntry, "%s %s", kind, value)
+	}
+}
+
+func TestListEntry_MatchesIPRange(t *testing.T) {
+	e := ListEntry{Kind: ListIP, Value: "10.0.0.0/8"}
+
+	assert.True(t, e.Matches("10.20.30.40"))
+	assert.False(t, e.Matches("11.0.0.1"))
+	assert.False(t, e.Matches("not an ip"))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReserveHeldStatuses are the statuses of the accepted transactions a reserve holds: a payment the fraud checks
+// let through stays PROCESSING, one approved in manual review becomes COMPLETED.
+var ReserveHeldStatuses = []TransactionStatus{StatusProcessing, StatusCompleted}
+
+// ReserveConfig describes how much of a merchant's volume is held back.
+// RollingPercent of every accepted transaction is held for HoldDays days,
+// and the held balance is never released below MinimumReserve.
+type ReserveConfig struct {
+	MerchantID     string
+	RollingPercent float64
+	HoldDays       int
+	MinimumReserve float64
+	Currency       string
+	// EffectiveFrom is when the reserve was first configured; only transactions
+	// created after it are held. Reconfiguring the reserve keeps it.
+	EffectiveFrom time.Time
+	UpdatedAt     time.Time
+}
+
+// Validate checks that the configuration can be applied.
+func (c ReserveConfig) Validate() error {
+	if c.MerchantID == "" || c.Currency == "" {
+		return ErrInvalidReserveConfig
+	}
+	if c.RollingPercent < 0 || c.RollingPercent > 100 {
+		return ErrInvalidReserveConfig
+	}
+	if c.HoldDays <= 0 || c.MinimumReserve < 0 {
+		return ErrInvalidReserveConfig
+	}
+	return nil
+}
+
+// ReserveEntry is an amount held from a single transaction.
+// ReleasedAt stays nil while the amount is still part of the reserve.
+type ReserveEntry struct {
+	ID            uuid.UUID
+	MerchantID    string
+	TransactionID uuid.UUID
+	Amount        float64
+	Currency      string
+	HeldAt        time.Time
+	ReleaseAt     time.Time
+	ReleasedAt    *time.Time
+}
+
+// ReserveRelease is one point of the release schedule: the amount
+// that becomes eligible for release on a given day.
+type ReserveRelease struct {
+	Date   time.Time
+	Amount float64
+}
+
+// ReserveBalance is the current state of a merchant's reserve.
+type ReserveBalance struct {
+	MerchantID     string
+	Currency       string
+	Held           float64
+	MinimumReserve float64
+	Schedule       []ReserveRelease
+}
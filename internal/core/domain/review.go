@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusInReview marks a transaction waiting for an analyst decision.
+const StatusInReview TransactionStatus = "IN_REVIEW"
+
+// ReviewStatus is the state of a manual review case.
+type ReviewStatus string
+
+const (
+	ReviewOpen     ReviewStatus = "OPEN"
+	ReviewClaimed  ReviewStatus = "CLAIMED"
+	ReviewApproved ReviewStatus = "APPROVED"
+	ReviewRejected ReviewStatus = "REJECTED"
+)
+
+// Closed reports whether the case has been decided.
+func (s ReviewStatus) Closed() bool {
+	return s == ReviewApproved || s == ReviewRejected
+}
+
+// ReviewCase is a transaction the fraud check could neither allow nor decline on its own.
+// A case is OPEN until an analyst claims it, and is closed by an approval or a rejection,
+// either by the analyst or automatically when DueAt passes.
+type ReviewCase struct {
+	ID             uuid.UUID
+	TransactionID  uuid.UUID
+	Status         ReviewStatus
+	RiskScore      float64
+	Reason         string
+	TriggeredRules []string
+	AssignedTo     string
+	DecidedBy      string
+	AutoDecided    bool
+	Note           string
+	CreatedAt      time.Time
+	DueAt          time.Time
+	ClaimedAt      *time.Time
+	DecidedAt      *time.Time
+}
+
+// ReviewDecision closes a case. Approve moves the transaction to COMPLETED, rejecting declines it as fraud.
+type ReviewDecision struct {
+	CaseID      uuid.UUID
+	Approve     bool
+	DecidedBy   string
+	AutoDecided bool
+	Note        string
+	DecidedAt   time.Time
+}
+
+// Status returns the case status the decision leads to.
+func (d ReviewDecision) Status() ReviewStatus {
+	if d.Approve {
+		return ReviewApproved
+	}
+	return ReviewRejected
+}
+
+// FraudLabel is the ground truth about a transaction, e.g. the outcome of a manual review.
+type FraudLabel struct {
+	TransactionID uuid.UUID
+	IsFraud       bool
+	Source        string
+	LabeledBy     string
+	Note          string
+	LabeledAt     time.Time
+}
+
+// LabelSourceManualReview is the label source of analyst decisions; LabelSourceReviewSLA of automatic ones.
+const (
+	LabelSourceManualReview = "manual_review"
+	LabelSourceReviewSLA    = "review_sla"
+)
@@ -0,0 +1,34 @@
+package domain
+
+// RingAttributes are the buyer attributes that link cards into a fraud ring: two cards used with the same
+// device, email or IP belong to the same cluster.
+var RingAttributes = []VelocityDimension{VelocityDevice, VelocityEmail, VelocityIP}
+
+// RingLink is an attribute of a transaction that links its card to other cards.
+type RingLink struct {
+	Kind  VelocityDimension
+	Value string
+}
+
+// RingLinks returns the linking attributes tx carries.
+func RingLinks(tx Transaction) []RingLink {
+	links := make([]RingLink, 0, len(RingAttributes))
+	for _, kind := range RingAttributes {
+		if v := kind.Value(tx); v != "" {
+			links = append(links, RingLink{Kind: kind, Value: v})
+		}
+	}
+	return links
+}
+
+// RingStats describes the cluster of a card after its transaction was linked.
+type RingStats struct {
+	// ClusterCards is the number of cards connected to the card through shared attributes, the card included.
+	ClusterCards int
+	// ClusterSize is the number of cards and attributes in the cluster.
+	ClusterSize int
+	// SharedAttributes is the number of attributes of the transaction also used with other cards.
+	SharedAttributes int
+	// MaxAttributeCards is the largest number of cards seen with one attribute of the transaction.
+	MaxAttributeCards int
+}
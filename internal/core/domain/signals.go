@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"strings"
+)
+
+// Buyer signals are the optional device, network and contact attributes a merchant may send with a payment.
+// Personal data (email, phone, street address) never leaves the gateway in clear text: the transaction
+// carries its SHA-256 hash, so rules and lists can still compare it.
+
+// Length bounds of free-form signals.
+const (
+	maxDeviceIDLength  = 128
+	maxUserAgentLength = 512
+)
+
+// Address is a postal address as sent by the caller.
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string // ISO 3166-1 alpha-2
+}
+
+// AddressFingerprint is what a transaction keeps of an address: the coarse location in clear text
+// and a hash of the whole address for equality checks.
+type AddressFingerprint struct {
+	Country    string
+	PostalCode string
+	Hash       string
+}
+
+// IsZero reports whether no address was given.
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Normalize validates the address and returns it trimmed, with the country and the postal code upper-cased.
+// The country is required. Errors match ErrInvalidSignal.
+func (a Address) Normalize() (Address, error) {
+	a = Address{
+		Line1:      strings.TrimSpace(a.Line1),
+		Line2:      strings.TrimSpace(a.Line2),
+		City:       strings.TrimSpace(a.City),
+		Region:     strings.TrimSpace(a.Region),
+		PostalCode: strings.ToUpper(strings.TrimSpace(a.PostalCode)),
+		Country:    a.Country,
+	}
+	country, err := NormalizeCountry(a.Country)
+	if err != nil {
+		return Address{}, err
+	}
+	a.Country = country
+	return a, nil
+}
+
+// Fingerprint returns the transaction view of the address. Two addresses differing only in case
+// or whitespace have the same hash. The zero address has the zero fingerprint.
+func (a Address) Fingerprint() AddressFingerprint {
+	if a.IsZero() {
+		return AddressFingerprint{}
+	}
+	parts := []string{a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country}
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(p), " "))
+	}
+	return AddressFingerprint{
+		Country:    a.Country,
+		PostalCode: a.PostalCode,
+		Hash:       HashPII(strings.Join(parts, "\n")),
+	}
+}
+
+// HashPII returns the hex SHA-256 hash of a normalized personal value, the form stored and compared.
+func HashPII(value string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))
+}
+
+// NormalizeEmail validates a bare email address and returns it lower-cased.
+func NormalizeEmail(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return "", fmt.Errorf("%w: invalid email %q", ErrInvalidSignal, value)
+	}
+	return strings.ToLower(value), nil
+}
+
+// EmailDomain returns the domain part of a normalized email.
+func EmailDomain(email string) string {
+	if i := strings.LastIndexByte(email, '@'); i >= 0 {
+		return email[i+1:]
+	}
+	return ""
+}
+
+// NormalizeIP validates a single IP address and returns its canonical form; IPv4-mapped IPv6 addresses become IPv4.
+func NormalizeIP(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	addr, err := netip.ParseAddr(value)
+	if err != nil || addr.Zone() != "" {
+		return "", fmt.Errorf("%w: invalid IP address %q", ErrInvalidSignal, value)
+	}
+	return addr.Unmap().String(), nil
+}
+
+// NormalizePhone validates a phone number in international format and returns it as E.164,
+// i.e. "+" followed by 8 to 15 digits. Spaces, dashes, dots and parentheses are dropped.
+func NormalizePhone(value string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+
+	rest, ok := strings.CutPrefix(digits, "+")
+	if !ok || len(rest) < 8 || len(rest) > 15 || rest[0] == '0' || strings.Trim(rest, "0123456789") != "" {
+		return "", fmt.Errorf("%w: phone must be in international format, e.g. +14155550100", ErrInvalidSignal)
+	}
+	return digits, nil
+}
+
+// NormalizeCountry validates an ISO 3166-1 alpha-2 code and returns it upper-cased.
+func NormalizeCountry(value string) (string, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 2 || strings.Trim(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code, got %q", ErrInvalidSignal, value)
+	}
+	return value, nil
+}
+
+// NormalizeDeviceID validates a device fingerprint and returns it trimmed.
+func NormalizeDeviceID(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > maxDeviceIDLength {
+		return "", fmt.Errorf("%w: device fingerprint must be 1 to %d characters", ErrInvalidSignal, maxDeviceIDLength)
+	}
+	return value, nil
+}
+
+// NormalizeUserAgent returns the user agent trimmed and cut to a sane length; any value is accepted.
+func NormalizeUserAgent(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > maxUserAgentLength {
+		value = strings.ToValidUTF8(value[:maxUserAgentLength], "")
+	}
+	return value
+}
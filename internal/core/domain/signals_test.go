@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSignals(t *testing.T) {
+	email, err := NormalizeEmail(" Buyer@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "buyer@example.com", email)
+	assert.Equal(t, "example.com", EmailDomain(email))
+
+	ip, err := NormalizeIP("::ffff:203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", ip)
+
+	phone, err := NormalizePhone("+1 (415) 555-0100")
+	require.NoError(t, err)
+	assert.Equal(t, "+14155550100", phone)
+
+	country, err := NormalizeCountry(" de ")
+	require.NoError(t, err)
+	assert.Equal(t, "DE", country)
+
+	_, err = NormalizeEmail("Buyer <buyer@example.com>")
+	assert.ErrorIs(t, err, ErrInvalidSignal)
+	_, err = NormalizeIP("10.0.0.0/8")
+	assert.ErrorIs(t, err, ErrInvalidSignal)
+	_, err = NormalizePhone("4155550100")
+	assert.ErrorIs(t, err, ErrInvalidSignal)
+	_, err = NormalizeCountry("DEU")
+	assert.ErrorIs(t, err, ErrInvalidSignal)
+}
+
+func TestAddress_Fingerprint(t *testing.T) {
+	a, err := Address{Line1: "1 Main  St", City: "Berlin", PostalCode: "10115", Country: "de"}.Normalize()
+	require.NoError(t, err)
+	b, err := Address{Line1: " 1 main st", City: "BERLIN", PostalCode: "10115", Country: "DE"}.Normalize()
+	require.NoError(t, err)
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.Equal(t, "DE", a.Fingerprint().Country)
+	assert.NotEqual(t, a.Fingerprint().Hash, Address{Line1: "2 Main St", Country: "DE"}.Fingerprint().Hash)
+	assert.Equal(t, AddressFingerprint{}, Address{}.Fingerprint())
+
+	_, err = Address{Line1: "1 Main St"}.Normalize()
+	assert.ErrorIs(t, err, ErrInvalidSignal, "the country is required")
+}
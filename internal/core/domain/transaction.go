@@ -1,8 +1,10 @@
 package domain
 
 import (
-	"github.com/google/uuid"
+	"context"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Package domain contains the core business logic and models for the application.
@@ -16,6 +18,7 @@ const (
 	StatusProcessing TransactionStatus = "PROCESSING"
 	StatusCompleted  TransactionStatus = "COMPLETED"
 	StatusFailed     TransactionStatus = "FAILED"
+	StatusDeclined   TransactionStatus = "DECLINED"
 )
 
 // Transaction is the central entity of our domain.
@@ -26,20 +29,79 @@ type Transaction struct {
 	Amount         float64
 	Currency       string
 	CardNumberHash string //TODO: Хэш номера карты, а не сам номер
-	IdempotencyKey uuid.UUID
-	CreatedAt      time.Time
+	MerchantID     string
+	CustomerID     string
+	BIN            string // first 8 digits of the card number
+	BINCountry     string // ISO 3166-1 alpha-2 country of the card issuer, empty if unknown
+	// Buyer signals, all optional; see signals.go.
+	EmailHash       string // HashPII of the normalized email
+	EmailDomain     string
+	PhoneHash       string // HashPII of the E.164 phone
+	IP              string
+	IPCountry       string // ISO 3166-1 alpha-2 country of the IP, empty if unknown
+	UserAgent       string
+	DeviceID        string // device fingerprint
+	BillingAddress  AddressFingerprint
+	ShippingAddress AddressFingerprint
+	IdempotencyKey  uuid.UUID
+	CreatedAt       time.Time
+	IsFraudulent    bool
+	DeclineReason   string
 }
 
+// Country is the country the transaction is attributed to: the card issuer's, or the IP's when the BIN is unknown.
+func (t Transaction) Country() string {
+	if t.BINCountry != "" {
+		return t.BINCountry
+	}
+	return t.IPCountry
+}
+
+// FraudDecision is the action a fraud engine recommends for a transaction.
+type FraudDecision string
+
+const (
+	DecisionAllow   FraudDecision = "ALLOW"
+	DecisionReview  FraudDecision = "REVIEW"
+	DecisionDecline FraudDecision = "DECLINE"
+)
+
 // FraudResult represents the outcome of a fraud check.
 type FraudResult struct {
 	IsFraudulent bool   `json:"is_fraudulent"`
 	Reason       string `json:"reason,omitempty"`
+	// RiskScore is the estimated fraud risk in the range [0, 1].
+	RiskScore      float64       `json:"risk_score"`
+	TriggeredRules []string      `json:"triggered_rules,omitempty"`
+	Decision       FraudDecision `json:"decision"`
+	EngineVersion  string        `json:"engine_version,omitempty"`
+	// Contributions lists the outcome of every engine when the result combines several engines.
+	Contributions []EngineContribution `json:"contributions,omitempty"`
+	// Explanation details the rules evaluated and the inputs used; nil when the engine does not explain itself.
+	Explanation *FraudExplanation `json:"explanation,omitempty"`
+}
+
+// EngineContribution is the outcome of one engine within a combined fraud check.
+type EngineContribution struct {
+	Engine        string        `json:"engine"`
+	EngineVersion string        `json:"engine_version,omitempty"`
+	Decision      FraudDecision `json:"decision,omitempty"`
+	RiskScore     float64       `json:"risk_score"`
+	Weight        float64       `json:"weight,omitempty"`
+	// Error is set when the engine could not evaluate the transaction.
+	Error string `json:"error,omitempty"`
+	// Skipped is set when the engine was not run, or was cancelled, because another engine already declined.
+	Skipped bool `json:"skipped,omitempty"`
+	// Explanation is the explanation of the engine's own result.
+	Explanation *FraudExplanation `json:"explanation,omitempty"`
 }
 
 // FraudRuleEngine is an interface (a "port" in Hexagonal Architecture).
 // It defines the contract for any component that can check a transaction for fraud.
 // The core logic doesn't care HOW the check is performed (in-memory, Redis, external service),
 // only that it can be done.
+//
+// An error means the transaction could not be evaluated; it must never be read as "clean".
 type FraudRuleEngine interface {
-	CheckTransaction(tx Transaction) FraudResult
+	CheckTransaction(ctx context.Context, tx Transaction) (FraudResult, error)
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// VelocityDimension is the attribute velocity aggregates are grouped by.
+type VelocityDimension string
+
+const (
+	VelocityCard     VelocityDimension = "card"
+	VelocityMerchant VelocityDimension = "merchant"
+	VelocityCustomer VelocityDimension = "customer"
+	VelocityIP       VelocityDimension = "ip"
+	VelocityDevice   VelocityDimension = "device"
+	VelocityEmail    VelocityDimension = "email"
+)
+
+// Valid reports whether d is a known dimension.
+func (d VelocityDimension) Valid() bool {
+	switch d {
+	case VelocityCard, VelocityMerchant, VelocityCustomer, VelocityIP, VelocityDevice, VelocityEmail:
+		return true
+	}
+	return false
+}
+
+// Value returns the value of dimension d carried by tx, or "" when tx does not carry it.
+func (d VelocityDimension) Value(tx Transaction) string {
+	switch d {
+	case VelocityCard:
+		return tx.CardNumberHash
+	case VelocityMerchant:
+		return tx.MerchantID
+	case VelocityCustomer:
+		return tx.CustomerID
+	case VelocityIP:
+		return tx.IP
+	case VelocityDevice:
+		return tx.DeviceID
+	case VelocityEmail:
+		return tx.EmailHash
+	}
+	return ""
+}
+
+// VelocityKey returns the counter key of a dimension value, e.g. "card:<hash>".
+func VelocityKey(d VelocityDimension, value string) string {
+	return fmt.Sprintf("%s:%s", d, value)
+}
+
+// VelocityEvent is one occurrence accounted in velocity counters.
+// Recording the same ID twice is a no-op, so retried evaluations are not counted again.
+type VelocityEvent struct {
+	ID     string
+	Amount float64
+	At     time.Time
+}
+
+// VelocityQuery asks for the aggregate of a key over the trailing window.
+type VelocityQuery struct {
+	Key    string
+	Window time.Duration
+}
+
+// VelocityAggregate is the number and summed amount of events within a window.
+type VelocityAggregate struct {
+	Count  int64
+	Amount float64
+}
@@ -19,12 +19,220 @@ type MessageBroker interface {
 	PublishTransactionCreated(ctx context.Context, tx domain.Transaction) error
 }
 
+// CreateTransactionInput carries everything the caller knows about a new payment.
+// The buyer signals are optional and already normalized (see domain/signals.go); the service hashes personal data.
+type CreateTransactionInput struct {
+	Amount         float64
+	Currency       string
+	CardNumber     string
+	MerchantID     string
+	CustomerID     string
+	IdempotencyKey uuid.UUID
+
+	Email           string
+	Phone           string
+	IP              string
+	UserAgent       string
+	DeviceID        string
+	BillingAddress  domain.Address
+	ShippingAddress domain.Address
+}
+
+// GeoLookup resolves countries from a local database. Unknown values resolve to "".
+type GeoLookup interface {
+	IPCountry(ip string) string
+	BINCountry(bin string) string
+}
+
 // TransactionService is an "incoming port" that defines how the outside world can interact with our kernel.
 type TransactionService interface {
-	CreateTransaction(ctx context.Context, amount float64, currency, cardNum string, idemKey uuid.UUID) (*domain.Transaction, error)
+	CreateTransaction(ctx context.Context, in CreateTransactionInput) (*domain.Transaction, error)
 }
 // RateLimiterRepository defines the port for a rate limiting storage.
 type RateLimiterRepository interface {
 	// IsAllowed checks if a request for a given key is within the defined limit.
 	IsAllowed(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
 }
+
+// VelocityCounter keeps exact sliding-window aggregates of events per key.
+type VelocityCounter interface {
+	// Record adds the event to every key of the queries and returns the aggregates in query order.
+	// The window of a query ends at the event time and includes the event.
+	Record(ctx context.Context, event domain.VelocityEvent, queries []domain.VelocityQuery) ([]domain.VelocityAggregate, error)
+}
+
+// SpendingLimitRepository stores the counters used for spending limit enforcement.
+type SpendingLimitRepository interface {
+	// Consume atomically checks every counter and, if none of them would go over its limit,
+	// adds the amount and one transaction to all of them.
+	// It returns *domain.LimitExceededError when a limit would be exceeded.
+	Consume(ctx context.Context, counters []domain.LimitCounter, amount float64) error
+	// Revert takes back an amount consumed earlier, e.g. when the transaction could not be saved.
+	Revert(ctx context.Context, counters []domain.LimitCounter, amount float64) error
+}
+
+// ReserveRepository is the storage port for merchant reserves.
+type ReserveRepository interface {
+	SaveReserveConfig(ctx context.Context, cfg domain.ReserveConfig) error
+	GetReserveConfig(ctx context.Context, merchantID string) (domain.ReserveConfig, error)
+	ListReserveConfigs(ctx context.Context) ([]domain.ReserveConfig, error)
+	// ListUnreservedTransactions returns the transactions of the merchant in one of domain.ReserveHeldStatuses
+	// in the given currency created since the given time that have no reserve entry yet.
+	ListUnreservedTransactions(ctx context.Context, merchantID, currency string, since time.Time) ([]domain.Transaction, error)
+	// SaveReserveEntries stores new holds. Entries for transactions that are already held are skipped.
+	SaveReserveEntries(ctx context.Context, entries []domain.ReserveEntry) error
+	// ListHeldEntries returns entries that have not been released, ordered by ReleaseAt.
+	ListHeldEntries(ctx context.Context, merchantID string) ([]domain.ReserveEntry, error)
+	ReleaseReserveEntries(ctx context.Context, ids []uuid.UUID, releasedAt time.Time) error
+}
+
+// ReserveService is the incoming port for managing merchant rolling reserves.
+type ReserveService interface {
+	ConfigureReserve(ctx context.Context, cfg domain.ReserveConfig) (*domain.ReserveConfig, error)
+	GetReserveBalance(ctx context.Context, merchantID string) (*domain.ReserveBalance, error)
+	// ProcessReserves holds new volume and releases aged-out entries for every configured merchant.
+	ProcessReserves(ctx context.Context) error
+}
+
+// ReviewRepository is the storage port for the manual review queue.
+type ReviewRepository interface {
+	// OpenReviewCase stores the case and moves its PROCESSING transaction to IN_REVIEW.
+	// It returns false if the transaction already has a case.
+	OpenReviewCase(ctx context.Context, c domain.ReviewCase) (bool, error)
+	// GetReviewCase returns domain.ErrReviewCaseNotFound if there is no such case.
+	GetReviewCase(ctx context.Context, id uuid.UUID) (domain.ReviewCase, error)
+	// ListReviewCases returns cases in the given status, the ones due first. An empty status lists the undecided ones.
+	ListReviewCases(ctx context.Context, status domain.ReviewStatus, limit int) ([]domain.ReviewCase, error)
+	// ClaimReviewCase assigns the case to the analyst if it is still in the expected state,
+	// and returns domain.ErrReviewCaseConflict otherwise.
+	ClaimReviewCase(ctx context.Context, expected domain.ReviewCase, analyst string, at time.Time) error
+	// DecideReviewCase closes the case and updates its transaction in one database transaction,
+	// if the case is still in the expected state, and returns domain.ErrReviewCaseConflict otherwise.
+	DecideReviewCase(ctx context.Context, expected domain.ReviewCase, d domain.ReviewDecision) error
+	// ListOverdueReviewCases returns undecided cases whose DueAt is before now.
+	ListOverdueReviewCases(ctx context.Context, now time.Time, limit int) ([]domain.ReviewCase, error)
+	// ListUnexportedReviewCases returns decided cases whose label has not been exported yet.
+	ListUnexportedReviewCases(ctx context.Context, limit int) ([]domain.ReviewCase, error)
+	MarkReviewLabelsExported(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
+
+// FraudLabelSink receives ground-truth fraud labels for analytics and model training.
+type FraudLabelSink interface {
+	SaveFraudLabels(ctx context.Context, labels []domain.FraudLabel) error
+}
+
+// LabelService is the incoming port for ground-truth labels reported from outside the review queue,
+// e.g. chargebacks and merchant reports.
+type LabelService interface {
+	// RecordLabels validates all labels first and stores them only if every one is valid.
+	// A later label of the same transaction and source replaces the earlier one.
+	RecordLabels(ctx context.Context, labels []domain.FraudLabel) error
+}
+
+// FraudReportRepository is the storage port for fraud check results.
+type FraudReportRepository interface {
+	// SaveFraudReports writes a batch of reports. Repeating a batch with the same non-empty dedupToken,
+	// e.g. after a timeout, does not write it again.
+	SaveFraudReports(ctx context.Context, reports []domain.FraudReport, dedupToken string) error
+	// GetFraudReport returns the latest report of the transaction, or domain.ErrFraudReportNotFound.
+	GetFraudReport(ctx context.Context, transactionID uuid.UUID) (domain.FraudReport, error)
+}
+
+// FraudReportService is the incoming port for explaining fraud decisions to support staff.
+type FraudReportService interface {
+	GetFraudReport(ctx context.Context, transactionID uuid.UUID) (*domain.FraudReport, error)
+}
+
+// ReviewService is the incoming port of the manual review queue.
+type ReviewService interface {
+	// OpenCase places a transaction with a REVIEW verdict into the queue. Opening a case twice is a no-op.
+	OpenCase(ctx context.Context, tx domain.Transaction, result domain.FraudResult) error
+	ListCases(ctx context.Context, status domain.ReviewStatus, limit int) ([]domain.ReviewCase, error)
+	GetCase(ctx context.Context, id uuid.UUID) (*domain.ReviewCase, error)
+	ClaimCase(ctx context.Context, id uuid.UUID, analyst string) (*domain.ReviewCase, error)
+	DecideCase(ctx context.Context, id uuid.UUID, analyst string, approve bool, note string) (*domain.ReviewCase, error)
+	// ProcessReviewQueue auto-decides overdue cases and exports the labels that could not be exported before.
+	ProcessReviewQueue(ctx context.Context) error
+}
+
+// ListRepository is the storage port for blocklists and allowlists.
+type ListRepository interface {
+	// SaveListEntries inserts the entries in one database transaction. An entry that already exists for the same
+	// list, kind and value is updated instead; the returned entries carry the stored ID and creation time.
+	SaveListEntries(ctx context.Context, entries []domain.ListEntry) ([]domain.ListEntry, error)
+	// GetListEntry returns domain.ErrListEntryNotFound if there is no such entry.
+	GetListEntry(ctx context.Context, id uuid.UUID) (domain.ListEntry, error)
+	// UpdateListEntry changes the reason and the expiry of an entry.
+	UpdateListEntry(ctx context.Context, e domain.ListEntry) error
+	// DeleteListEntry returns the deleted entry, or domain.ErrListEntryNotFound.
+	DeleteListEntry(ctx context.Context, id uuid.UUID) (domain.ListEntry, error)
+	// ListListEntries returns entries of the list and kind, newest first; empty filters match everything.
+	ListListEntries(ctx context.Context, list domain.ListType, kind domain.ListKind, limit int) ([]domain.ListEntry, error)
+	// AllListEntries returns every entry that has not expired at now.
+	AllListEntries(ctx context.Context, now time.Time) ([]domain.ListEntry, error)
+}
+
+// ListCache keeps the lists where the fraud engine can read them quickly.
+type ListCache interface {
+	PutListEntries(ctx context.Context, entries []domain.ListEntry) error
+	DeleteListEntry(ctx context.Context, e domain.ListEntry) error
+	// ReplaceListEntries atomically replaces the whole cache content.
+	ReplaceListEntries(ctx context.Context, entries []domain.ListEntry) error
+}
+
+// ListMatcher is the read side of the list cache used by the fraud engine.
+type ListMatcher interface {
+	// MatchLists returns the active entries of both lists that match any of the subjects.
+	MatchLists(ctx context.Context, subjects []domain.ListSubject) ([]domain.ListEntry, error)
+}
+
+// ListService is the incoming port for managing blocklists and allowlists.
+type ListService interface {
+	AddEntry(ctx context.Context, e domain.ListEntry) (*domain.ListEntry, error)
+	// ImportEntries validates all entries first and stores them only if every one is valid.
+	ImportEntries(ctx context.Context, entries []domain.ListEntry) ([]domain.ListEntry, error)
+	GetEntry(ctx context.Context, id uuid.UUID) (*domain.ListEntry, error)
+	UpdateEntry(ctx context.Context, id uuid.UUID, reason string, expiresAt *time.Time) (*domain.ListEntry, error)
+	DeleteEntry(ctx context.Context, id uuid.UUID) error
+	ListEntries(ctx context.Context, list domain.ListType, kind domain.ListKind, limit int) ([]domain.ListEntry, error)
+	// SyncCache rebuilds the cache from the repository, dropping expired entries and repairing missed writes.
+	SyncCache(ctx context.Context) error
+}
+
+// FeatureStore keeps the aggregated fraud features of every entity for online reads.
+type FeatureStore interface {
+	// GetFeatures returns the values of defs for the entities of tx, as of tx.CreatedAt, in one round trip.
+	GetFeatures(ctx context.Context, defs []domain.FeatureDefinition, tx domain.Transaction) (domain.FeatureValues, error)
+	// UpdateFeatures accounts the transactions in every definition. Transactions accounted recently are skipped,
+	// so a redelivered stream message is not counted twice.
+	UpdateFeatures(ctx context.Context, defs []domain.FeatureDefinition, txs []domain.Transaction) error
+	// ResetFeatures drops the state of defs.
+	ResetFeatures(ctx context.Context, defs []domain.FeatureDefinition) error
+	// BackfillFeatures accounts historical transactions in defs without deduplication; it follows ResetFeatures.
+	BackfillFeatures(ctx context.Context, defs []domain.FeatureDefinition, txs []domain.Transaction) error
+}
+
+// TransactionHistory is the offline record of past transactions used to backfill features.
+type TransactionHistory interface {
+	// ScanTransactions calls fn with batches of the transactions created since since, oldest first.
+	// The batch is only valid until fn returns.
+	ScanTransactions(ctx context.Context, since time.Time, fn func(batch []domain.Transaction) error) error
+}
+
+// RingGraph is the link graph between cards and the attributes they were used with.
+type RingGraph interface {
+	// LinkCard connects the card with the attributes and returns its cluster afterwards.
+	// Linking the same card and attributes again changes nothing.
+	LinkCard(ctx context.Context, cardHash string, links []domain.RingLink) (domain.RingStats, error)
+}
+
+// MerchantActivitySource aggregates the transaction history per merchant.
+type MerchantActivitySource interface {
+	// MerchantActivity returns the activity of every merchant in [from, to) by bucket and country, ordered by bucket.
+	MerchantActivity(ctx context.Context, from, to time.Time, bucket time.Duration) ([]domain.MerchantActivity, error)
+}
+
+// AnomalyPublisher delivers detected merchant anomalies.
+type AnomalyPublisher interface {
+	PublishAnomalies(ctx context.Context, anomalies []domain.MerchantAnomaly) error
+}
@@ -0,0 +1,202 @@
+package events
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+//go:embed schemas/transaction_created.avsc
+var transactionCreatedAvsc string
+
+// AvroSerializer encodes events with schemas/transaction_created.avsc.
+type AvroSerializer struct {
+	schema avro.Schema
+
+	mu      sync.Mutex
+	writers map[string]avro.Schema
+}
+
+// NewAvroSerializer parses the embedded schema; the contract tests keep it valid.
+func NewAvroSerializer() *AvroSerializer {
+	return &AvroSerializer{
+		schema:  avro.MustParse(transactionCreatedAvsc),
+		writers: make(map[string]avro.Schema),
+	}
+}
+
+func (s *AvroSerializer) Format() Format { return FormatAvro }
+
+func (s *AvroSerializer) SchemaType() string { return SchemaTypeAvro }
+
+func (s *AvroSerializer) Schema() string { return transactionCreatedAvsc }
+
+// avroEvent and avroPayload follow the field names of the Avro schema.
+type avroEvent struct {
+	ID          string      `avro:"id"`
+	Source      string      `avro:"source"`
+	Type        string      `avro:"type"`
+	Time        time.Time   `avro:"time"`
+	Version     int         `avro:"version"`
+	TraceParent string      `avro:"traceparent"`
+	TraceState  string      `avro:"tracestate"`
+	Data        avroPayload `avro:"data"`
+}
+
+type avroPayload struct {
+	TransactionID   string       `avro:"transaction_id"`
+	Amount          float64      `avro:"amount"`
+	Currency        string       `avro:"currency"`
+	CardNumberHash  string       `avro:"card_number_hash"`
+	MerchantID      string       `avro:"merchant_id"`
+	CustomerID      string       `avro:"customer_id"`
+	BIN             string       `avro:"bin"`
+	BINCountry      string       `avro:"bin_country"`
+	EmailHash       string       `avro:"email_hash"`
+	EmailDomain     string       `avro:"email_domain"`
+	PhoneHash       string       `avro:"phone_hash"`
+	IP              string       `avro:"ip"`
+	IPCountry       string       `avro:"ip_country"`
+	UserAgent       string       `avro:"user_agent"`
+	DeviceID        string       `avro:"device_id"`
+	BillingAddress  *avroAddress `avro:"billing_address"`
+	ShippingAddress *avroAddress `avro:"shipping_address"`
+	Status          string       `avro:"status"`
+	IdempotencyKey  string       `avro:"idempotency_key"`
+	CreatedAt       time.Time    `avro:"created_at"`
+}
+
+type avroAddress struct {
+	Country    string `avro:"country"`
+	PostalCode string `avro:"postal_code"`
+	Hash       string `avro:"hash"`
+}
+
+func (s *AvroSerializer) Marshal(e TransactionCreatedEvent) ([]byte, error) {
+	p := e.Data
+	value, err := avro.Marshal(s.schema, avroEvent{
+		ID:          e.ID,
+		Source:      e.Source,
+		Type:        e.Type,
+		Time:        e.Time,
+		Version:     e.Version,
+		TraceParent: e.TraceParent,
+		TraceState:  e.TraceState,
+		Data: avroPayload{
+			TransactionID:   p.TransactionID.String(),
+			Amount:          p.Amount,
+			Currency:        p.Currency,
+			CardNumberHash:  p.CardNumberHash,
+			MerchantID:      p.MerchantID,
+			CustomerID:      p.CustomerID,
+			BIN:             p.BIN,
+			BINCountry:      p.BINCountry,
+			EmailHash:       p.EmailHash,
+			EmailDomain:     p.EmailDomain,
+			PhoneHash:       p.PhoneHash,
+			IP:              p.IP,
+			IPCountry:       p.IPCountry,
+			UserAgent:       p.UserAgent,
+			DeviceID:        p.DeviceID,
+			BillingAddress:  toAvroAddress(p.BillingAddress),
+			ShippingAddress: toAvroAddress(p.ShippingAddress),
+			Status:          p.Status,
+			IdempotencyKey:  p.IdempotencyKey.String(),
+			CreatedAt:       p.CreatedAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", e.Type, err)
+	}
+	return value, nil
+}
+
+// Unmarshal reads the event with the schema it was written with: fields added since are skipped,
+// fields the writer did not have yet are zero.
+func (s *AvroSerializer) Unmarshal(value []byte, writer string) (TransactionCreatedEvent, error) {
+	schema, err := s.writerSchema(writer)
+	if err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+	var msg avroEvent
+	if err := avro.Unmarshal(schema, value, &msg); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid avro event: %w", err)
+	}
+	e := TransactionCreatedEvent{Attributes: Attributes{
+		ID:          msg.ID,
+		Source:      msg.Source,
+		Type:        msg.Type,
+		Time:        msg.Time.UTC(),
+		Version:     msg.Version,
+		TraceParent: msg.TraceParent,
+		TraceState:  msg.TraceState,
+	}}
+	if err := e.check(TransactionCreatedType, TransactionCreatedVersion); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+
+	p := msg.Data
+	if e.Data.TransactionID, err = parseUUID(p.TransactionID); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid %s payload: transaction_id: %w", e.Type, err)
+	}
+	if e.Data.IdempotencyKey, err = parseUUID(p.IdempotencyKey); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid %s payload: idempotency_key: %w", e.Type, err)
+	}
+	e.Data.Amount = p.Amount
+	e.Data.Currency = p.Currency
+	e.Data.CardNumberHash = p.CardNumberHash
+	e.Data.MerchantID = p.MerchantID
+	e.Data.CustomerID = p.CustomerID
+	e.Data.BIN = p.BIN
+	e.Data.BINCountry = p.BINCountry
+	e.Data.EmailHash = p.EmailHash
+	e.Data.EmailDomain = p.EmailDomain
+	e.Data.PhoneHash = p.PhoneHash
+	e.Data.IP = p.IP
+	e.Data.IPCountry = p.IPCountry
+	e.Data.UserAgent = p.UserAgent
+	e.Data.DeviceID = p.DeviceID
+	e.Data.BillingAddress = fromAvroAddress(p.BillingAddress)
+	e.Data.ShippingAddress = fromAvroAddress(p.ShippingAddress)
+	e.Data.Status = p.Status
+	e.Data.CreatedAt = p.CreatedAt.UTC()
+	if err := e.validate(); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+	return e, nil
+}
+
+// writerSchema parses a writer schema once; an empty one is the schema of the serializer.
+func (s *AvroSerializer) writerSchema(writer string) (avro.Schema, error) {
+	if writer == "" {
+		return s.schema, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if schema, ok := s.writers[writer]; ok {
+		return schema, nil
+	}
+	schema, err := avro.Parse(writer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro writer schema: %w", err)
+	}
+	s.writers[writer] = schema
+	return schema, nil
+}
+
+func toAvroAddress(a *AddressPayload) *avroAddress {
+	if a == nil {
+		return nil
+	}
+	return &avroAddress{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
+
+func fromAvroAddress(a *avroAddress) *AddressPayload {
+	if a == nil {
+		return nil
+	}
+	return &AddressPayload{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
@@ -0,0 +1,84 @@
+// Package events is the contract of the events exchanged over Kafka: CloudEvents-style attributes
+// around a versioned payload, encoded by a pluggable serializer (JSON, Protobuf or Avro). Producers
+// and consumers encode and decode events only through this package, so both sides read the same fields.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// SpecVersion is the CloudEvents specification the envelope follows.
+const SpecVersion = "1.0"
+
+// ContentType is the content type of a record carrying an envelope.
+const ContentType = "application/cloudevents+json"
+
+// ErrUnsupportedEvent means the event has another type or a newer version than the consumer understands.
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
+// Attributes are the CloudEvents context attributes of an event, the same in every wire format.
+type Attributes struct {
+	ID     string    `json:"id"`
+	Source string    `json:"source"`
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	// Version is the version of the payload schema; a consumer rejects versions newer than it knows.
+	Version int `json:"version"`
+	// TraceParent and TraceState are the W3C trace context of the producer (CloudEvents distributed tracing extension).
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+// Envelope is an event in the CloudEvents JSON structured mode.
+type Envelope struct {
+	SpecVersion string `json:"specversion"`
+	Attributes
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// newAttributes describes a new event and records the trace context of ctx.
+// The time keeps microseconds, the precision every wire format can carry.
+func newAttributes(ctx context.Context, eventType string, version int, source string) Attributes {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return Attributes{
+		ID:          uuid.NewString(),
+		Source:      source,
+		Type:        eventType,
+		Time:        time.Now().UTC().Truncate(time.Microsecond),
+		Version:     version,
+		TraceParent: carrier.Get("traceparent"),
+		TraceState:  carrier.Get("tracestate"),
+	}
+}
+
+// Context returns ctx with the trace context of the producer, so the consumer's spans join its trace.
+func (a Attributes) Context(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	if a.TraceParent != "" {
+		carrier.Set("traceparent", a.TraceParent)
+	}
+	if a.TraceState != "" {
+		carrier.Set("tracestate", a.TraceState)
+	}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
+
+// check accepts an event of the given type and a version up to maxVersion.
+func (a Attributes) check(eventType string, maxVersion int) error {
+	if a.Type != eventType {
+		return fmt.Errorf("%w: type %q, expected %q", ErrUnsupportedEvent, a.Type, eventType)
+	}
+	if a.Version < 1 || a.Version > maxVersion {
+		return fmt.Errorf("%w: %s version %d, supported up to %d", ErrUnsupportedEvent, a.Type, a.Version, maxVersion)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	transactionsv1 "payment-processing-system/gen/go/proto/v1"
+	protov1 "payment-processing-system/proto/v1"
+)
+
+// ProtobufSerializer encodes events as transactions.v1.TransactionCreatedEvent.
+type ProtobufSerializer struct{}
+
+func (ProtobufSerializer) Format() Format { return FormatProtobuf }
+
+func (ProtobufSerializer) SchemaType() string { return SchemaTypeProtobuf }
+
+// Schema is the source of events.proto; TransactionCreatedEvent is its first message.
+func (ProtobufSerializer) Schema() string { return protov1.EventsProto }
+
+func (ProtobufSerializer) Marshal(e TransactionCreatedEvent) ([]byte, error) {
+	p := e.Data
+	msg := &transactionsv1.TransactionCreatedEvent{
+		Id:          e.ID,
+		Source:      e.Source,
+		Type:        e.Type,
+		Time:        timestamppb.New(e.Time),
+		Version:     int32(e.Version),
+		Traceparent: e.TraceParent,
+		Tracestate:  e.TraceState,
+		Data: &transactionsv1.TransactionCreated{
+			TransactionId:   p.TransactionID.String(),
+			Amount:          p.Amount,
+			Currency:        p.Currency,
+			CardNumberHash:  p.CardNumberHash,
+			MerchantId:      p.MerchantID,
+			CustomerId:      p.CustomerID,
+			Bin:             p.BIN,
+			BinCountry:      p.BINCountry,
+			EmailHash:       p.EmailHash,
+			EmailDomain:     p.EmailDomain,
+			PhoneHash:       p.PhoneHash,
+			Ip:              p.IP,
+			IpCountry:       p.IPCountry,
+			UserAgent:       p.UserAgent,
+			DeviceId:        p.DeviceID,
+			BillingAddress:  protoAddress(p.BillingAddress),
+			ShippingAddress: protoAddress(p.ShippingAddress),
+			Status:          p.Status,
+			IdempotencyKey:  p.IdempotencyKey.String(),
+			CreatedAt:       timestamppb.New(p.CreatedAt),
+		},
+	}
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", e.Type, err)
+	}
+	return value, nil
+}
+
+// Unmarshal ignores the writer schema: fields added since are skipped, missing fields are zero.
+func (ProtobufSerializer) Unmarshal(value []byte, _ string) (TransactionCreatedEvent, error) {
+	var msg transactionsv1.TransactionCreatedEvent
+	if err := proto.Unmarshal(value, &msg); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid protobuf event: %w", err)
+	}
+	e := TransactionCreatedEvent{Attributes: Attributes{
+		ID:          msg.GetId(),
+		Source:      msg.GetSource(),
+		Type:        msg.GetType(),
+		Time:        protoTime(msg.GetTime()),
+		Version:     int(msg.GetVersion()),
+		TraceParent: msg.GetTraceparent(),
+		TraceState:  msg.GetTracestate(),
+	}}
+	if err := e.check(TransactionCreatedType, TransactionCreatedVersion); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+
+	p := msg.GetData()
+	var err error
+	if e.Data.TransactionID, err = parseUUID(p.GetTransactionId()); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid %s payload: transaction_id: %w", e.Type, err)
+	}
+	if e.Data.IdempotencyKey, err = parseUUID(p.GetIdempotencyKey()); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid %s payload: idempotency_key: %w", e.Type, err)
+	}
+	e.Data.Amount = p.GetAmount()
+	e.Data.Currency = p.GetCurrency()
+	e.Data.CardNumberHash = p.GetCardNumberHash()
+	e.Data.MerchantID = p.GetMerchantId()
+	e.Data.CustomerID = p.GetCustomerId()
+	e.Data.BIN = p.GetBin()
+	e.Data.BINCountry = p.GetBinCountry()
+	e.Data.EmailHash = p.GetEmailHash()
+	e.Data.EmailDomain = p.GetEmailDomain()
+	e.Data.PhoneHash = p.GetPhoneHash()
+	e.Data.IP = p.GetIp()
+	e.Data.IPCountry = p.GetIpCountry()
+	e.Data.UserAgent = p.GetUserAgent()
+	e.Data.DeviceID = p.GetDeviceId()
+	e.Data.BillingAddress = fromProtoAddress(p.GetBillingAddress())
+	e.Data.ShippingAddress = fromProtoAddress(p.GetShippingAddress())
+	e.Data.Status = p.GetStatus()
+	e.Data.CreatedAt = protoTime(p.GetCreatedAt())
+	if err := e.validate(); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+	return e, nil
+}
+
+func protoAddress(a *AddressPayload) *transactionsv1.Address {
+	if a == nil {
+		return nil
+	}
+	return &transactionsv1.Address{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
+
+func fromProtoAddress(a *transactionsv1.Address) *AddressPayload {
+	if a == nil {
+		return nil
+	}
+	return &AddressPayload{Country: a.GetCountry(), PostalCode: a.GetPostalCode(), Hash: a.GetHash()}
+}
+
+// protoTime keeps an unset timestamp the zero time rather than the Unix epoch.
+func protoTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// parseUUID reads a UUID of the binary formats, where an empty string is the nil UUID.
+func parseUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Format is the wire format of the events, chosen by config.
+type Format string
+
+const (
+	// FormatJSON is the CloudEvents JSON structured mode; it needs no schema registry.
+	FormatJSON Format = "json"
+	// FormatProtobuf encodes events with the messages of proto/v1/events.proto.
+	FormatProtobuf Format = "protobuf"
+	// FormatAvro encodes events with schemas/transaction_created.avsc.
+	FormatAvro Format = "avro"
+)
+
+// Schema types of the schema registry.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// ErrUnknownFormat means the config names a format without a serializer.
+var ErrUnknownFormat = errors.New("unknown serialization format")
+
+// Serializer encodes payment.transaction.created events in one wire format.
+type Serializer interface {
+	Format() Format
+	Marshal(e TransactionCreatedEvent) ([]byte, error)
+	// Unmarshal decodes and validates an event. writer is the schema the event was written with,
+	// empty for the schema of the serializer.
+	Unmarshal(data []byte, writer string) (TransactionCreatedEvent, error)
+}
+
+// SchemaSerializer is a Serializer whose schema is kept in the schema registry.
+type SchemaSerializer interface {
+	Serializer
+	SchemaType() string
+	Schema() string
+}
+
+// NewSerializer returns the serializer of the format.
+func NewSerializer(format Format) (Serializer, error) {
+	switch format {
+	case FormatJSON:
+		return JSONSerializer{}, nil
+	case FormatProtobuf:
+		return ProtobufSerializer{}, nil
+	case FormatAvro:
+		return NewAvroSerializer(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// JSONSerializer encodes events in the CloudEvents JSON structured mode.
+type JSONSerializer struct{}
+
+func (JSONSerializer) Format() Format { return FormatJSON }
+
+func (JSONSerializer) Marshal(e TransactionCreatedEvent) ([]byte, error) {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", e.Type, err)
+	}
+	return json.Marshal(Envelope{
+		SpecVersion:     SpecVersion,
+		Attributes:      e.Attributes,
+		DataContentType: "application/json",
+		Data:            data,
+	})
+}
+
+// Unmarshal decodes an envelope. A record without specversion is a bare version 1 payload
+// published before the envelope was introduced.
+func (JSONSerializer) Unmarshal(value []byte, _ string) (TransactionCreatedEvent, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid event envelope: %w", err)
+	}
+	switch envelope.SpecVersion {
+	case "":
+		envelope = Envelope{Attributes: Attributes{Type: TransactionCreatedType, Version: 1}, Data: value}
+	case SpecVersion:
+	default:
+		return TransactionCreatedEvent{}, fmt.Errorf("%w: specversion %q", ErrUnsupportedEvent, envelope.SpecVersion)
+	}
+	if err := envelope.check(TransactionCreatedType, TransactionCreatedVersion); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+
+	e := TransactionCreatedEvent{Attributes: envelope.Attributes}
+	if err := json.Unmarshal(envelope.Data, &e.Data); err != nil {
+		return TransactionCreatedEvent{}, fmt.Errorf("invalid %s payload: %w", e.Type, err)
+	}
+	if err := e.validate(); err != nil {
+		return TransactionCreatedEvent{}, err
+	}
+	return e, nil
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serializers(t *testing.T) []Serializer {
+	t.Helper()
+	var all []Serializer
+	for _, format := range []Format{FormatJSON, FormatProtobuf, FormatAvro} {
+		s, err := NewSerializer(format)
+		require.NoError(t, err)
+		all = append(all, s)
+	}
+	return all
+}
+
+// Every format carries the whole contract: the golden event survives a round trip unchanged.
+func TestSerializers_RoundTripContract(t *testing.T) {
+	golden, err := JSONSerializer{}.Unmarshal(readGolden(t), "")
+	require.NoError(t, err)
+
+	for _, s := range serializers(t) {
+		t.Run(string(s.Format()), func(t *testing.T) {
+			value, err := s.Marshal(golden)
+			require.NoError(t, err)
+
+			event, err := s.Unmarshal(value, "")
+
+			require.NoError(t, err)
+			assert.Equal(t, golden, event)
+		})
+	}
+}
+
+func TestSerializers_RejectUnknownEvents(t *testing.T) {
+	event := NewTransactionCreated(context.Background(), "/payment-api", contractTransaction())
+	newer := event
+	newer.Version = TransactionCreatedVersion + 1
+	other := event
+	other.Type = "payment.transaction.refunded"
+
+	for _, s := range serializers(t) {
+		for _, e := range []TransactionCreatedEvent{newer, other} {
+			value, err := s.Marshal(e)
+			require.NoError(t, err)
+			_, err = s.Unmarshal(value, "")
+			assert.ErrorIs(t, err, ErrUnsupportedEvent, s.Format())
+		}
+	}
+}
+
+func TestAvroSerializer_ReadsEventsOfOlderWriterSchema(t *testing.T) {
+	s := NewAvroSerializer()
+	writer := strings.Replace(s.Schema(), `{"name": "device_id", "type": "string", "default": ""},`, "", 1)
+	require.NotEqual(t, s.Schema(), writer)
+	event := NewTransactionCreated(context.Background(), "/payment-api", contractTransaction())
+	value, err := s.Marshal(event)
+	require.NoError(t, err)
+	old, err := avro.Parse(writer)
+	require.NoError(t, err)
+	var msg avroEvent
+	require.NoError(t, avro.Unmarshal(s.schema, value, &msg))
+	value, err = avro.Marshal(old, msg)
+	require.NoError(t, err)
+
+	decoded, err := s.Unmarshal(value, writer)
+
+	require.NoError(t, err)
+	assert.Empty(t, decoded.Data.DeviceID)
+	assert.Equal(t, event.Data.CardNumberHash, decoded.Data.CardNumberHash)
+}
+
+func TestNewSerializer_UnknownFormat(t *testing.T) {
+	_, err := NewSerializer("xml")
+
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
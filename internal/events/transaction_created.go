@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"payment-processing-system/internal/core/domain"
+)
+
+const (
+	// TransactionCreatedType is published on transactions.created for every accepted transaction.
+	TransactionCreatedType = "payment.transaction.created"
+	// TransactionCreatedVersion is the current version of the TransactionCreated payload.
+	// A new optional field keeps the version; renaming or removing a field needs a new one.
+	TransactionCreatedVersion = 1
+)
+
+// TransactionCreatedEvent is a payment.transaction.created event.
+type TransactionCreatedEvent struct {
+	Attributes
+	Data TransactionCreated
+}
+
+// TransactionCreated is the payload of the payment.transaction.created event.
+type TransactionCreated struct {
+	TransactionID   uuid.UUID       `json:"transaction_id"`
+	Amount          float64         `json:"amount"`
+	Currency        string          `json:"currency"`
+	CardNumberHash  string          `json:"card_number_hash"`
+	MerchantID      string          `json:"merchant_id"`
+	CustomerID      string          `json:"customer_id"`
+	BIN             string          `json:"bin"`
+	BINCountry      string          `json:"bin_country"`
+	EmailHash       string          `json:"email_hash"`
+	EmailDomain     string          `json:"email_domain"`
+	PhoneHash       string          `json:"phone_hash"`
+	IP              string          `json:"ip"`
+	IPCountry       string          `json:"ip_country"`
+	UserAgent       string          `json:"user_agent"`
+	DeviceID        string          `json:"device_id"`
+	BillingAddress  *AddressPayload `json:"billing_address"`
+	ShippingAddress *AddressPayload `json:"shipping_address"`
+	Status          string          `json:"status"`
+	IdempotencyKey  uuid.UUID       `json:"idempotency_key"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// AddressPayload is an address fingerprint; a missing address is null.
+type AddressPayload struct {
+	Country    string `json:"country"`
+	PostalCode string `json:"postal_code"`
+	Hash       string `json:"hash"`
+}
+
+// NewTransactionCreated builds the event of a created transaction.
+func NewTransactionCreated(ctx context.Context, source string, tx domain.Transaction) TransactionCreatedEvent {
+	return TransactionCreatedEvent{
+		Attributes: newAttributes(ctx, TransactionCreatedType, TransactionCreatedVersion, source),
+		Data: TransactionCreated{
+			TransactionID:   tx.ID,
+			Amount:          tx.Amount,
+			Currency:        tx.Currency,
+			CardNumberHash:  tx.CardNumberHash,
+			MerchantID:      tx.MerchantID,
+			CustomerID:      tx.CustomerID,
+			BIN:             tx.BIN,
+			BINCountry:      tx.BINCountry,
+			EmailHash:       tx.EmailHash,
+			EmailDomain:     tx.EmailDomain,
+			PhoneHash:       tx.PhoneHash,
+			IP:              tx.IP,
+			IPCountry:       tx.IPCountry,
+			UserAgent:       tx.UserAgent,
+			DeviceID:        tx.DeviceID,
+			BillingAddress:  addressPayload(tx.BillingAddress),
+			ShippingAddress: addressPayload(tx.ShippingAddress),
+			Status:          string(tx.Status),
+			IdempotencyKey:  tx.IdempotencyKey,
+			CreatedAt:       tx.CreatedAt,
+		},
+	}
+}
+
+// Transaction returns the transaction the event describes.
+func (e TransactionCreatedEvent) Transaction() domain.Transaction {
+	p := e.Data
+	return domain.Transaction{
+		ID:              p.TransactionID,
+		Status:          domain.TransactionStatus(p.Status),
+		Amount:          p.Amount,
+		Currency:        p.Currency,
+		CardNumberHash:  p.CardNumberHash,
+		MerchantID:      p.MerchantID,
+		CustomerID:      p.CustomerID,
+		BIN:             p.BIN,
+		BINCountry:      p.BINCountry,
+		EmailHash:       p.EmailHash,
+		EmailDomain:     p.EmailDomain,
+		PhoneHash:       p.PhoneHash,
+		IP:              p.IP,
+		IPCountry:       p.IPCountry,
+		UserAgent:       p.UserAgent,
+		DeviceID:        p.DeviceID,
+		BillingAddress:  p.BillingAddress.fingerprint(),
+		ShippingAddress: p.ShippingAddress.fingerprint(),
+		IdempotencyKey:  p.IdempotencyKey,
+		CreatedAt:       p.CreatedAt,
+	}
+}
+
+// validate rejects events the consumer cannot process, whatever format they were read from.
+func (e TransactionCreatedEvent) validate() error {
+	if err := e.check(TransactionCreatedType, TransactionCreatedVersion); err != nil {
+		return err
+	}
+	if e.Data.TransactionID == uuid.Nil {
+		return fmt.Errorf("invalid %s payload: transaction_id is required", e.Type)
+	}
+	return nil
+}
+
+func addressPayload(a domain.AddressFingerprint) *AddressPayload {
+	if a == (domain.AddressFingerprint{}) {
+		return nil
+	}
+	return &AddressPayload{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
+
+func (a *AddressPayload) fingerprint() domain.AddressFingerprint {
+	if a == nil {
+		return domain.AddressFingerprint{}
+	}
+	return domain.AddressFingerprint{Country: a.Country, PostalCode: a.PostalCode, Hash: a.Hash}
+}
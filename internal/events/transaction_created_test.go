@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"payment-processing-system/internal/core/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The contract tests pin the wire format of payment.transaction.created to testdata: the producer side
+// must encode exactly the golden payload and the consumer side must read every field of it back.
+// A change breaking either side fails here, not in the analyzer reading zero values.
+
+const transactionCreatedGolden = "testdata/transaction_created.v1.json"
+
+func contractTransaction() domain.Transaction {
+	return domain.Transaction{
+		ID:             uuid.MustParse("0b7e4a52-8a1f-4c3e-9d2b-5f6e7a8b9c0d"),
+		Status:         domain.StatusProcessing,
+		Amount:         149.99,
+		Currency:       "EUR",
+		CardNumberHash: "c4ca4238a0b923820dcc509a6f75849b",
+		MerchantID:     "merchant-42",
+		CustomerID:     "customer-7",
+		BIN:            "411111",
+		BINCountry:     "DE",
+		EmailHash:      "e1f2a3b4",
+		EmailDomain:    "example.com",
+		PhoneHash:      "p5q6r7s8",
+		IP:             "203.0.113.10",
+		IPCountry:      "DE",
+		UserAgent:      "Mozilla/5.0",
+		DeviceID:       "device-1",
+		BillingAddress: domain.AddressFingerprint{Country: "DE", PostalCode: "10115", Hash: "addr-1"},
+		IdempotencyKey: uuid.MustParse("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"),
+		CreatedAt:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func readGolden(t *testing.T) []byte {
+	t.Helper()
+	golden, err := os.ReadFile(transactionCreatedGolden)
+	require.NoError(t, err)
+	return golden
+}
+
+func TestTransactionCreated_ProducerMatchesContract(t *testing.T) {
+	var golden Envelope
+	require.NoError(t, json.Unmarshal(readGolden(t), &golden))
+
+	value, err := JSONSerializer{}.Marshal(NewTransactionCreated(context.Background(), "/payment-api", contractTransaction()))
+	require.NoError(t, err)
+
+	var event Envelope
+	require.NoError(t, json.Unmarshal(value, &event))
+	assert.Equal(t, golden.SpecVersion, event.SpecVersion)
+	assert.Equal(t, golden.Type, event.Type)
+	assert.Equal(t, golden.Version, event.Version)
+	assert.Equal(t, golden.DataContentType, event.DataContentType)
+	assert.JSONEq(t, string(golden.Data), string(event.Data))
+	assert.NotEmpty(t, event.ID)
+	assert.False(t, event.Time.IsZero())
+}
+
+func TestTransactionCreated_ConsumerReadsContract(t *testing.T) {
+	event, err := JSONSerializer{}.Unmarshal(readGolden(t), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, contractTransaction(), event.Transaction())
+	assert.Equal(t, "6f1c2b0e-3d4a-4c8e-9b7a-2e5f6a7b8c9d", event.ID)
+	assert.Equal(t, "/payment-api", event.Source)
+}
+
+func TestTransactionCreated_PropagatesTraceContext(t *testing.T) {
+	received, err := JSONSerializer{}.Unmarshal(readGolden(t), "")
+	require.NoError(t, err)
+
+	event := NewTransactionCreated(received.Context(context.Background()), "/payment-api", contractTransaction())
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", event.TraceParent)
+}
+
+func TestTransactionCreated_AcceptsBareJSONPayload(t *testing.T) {
+	var golden Envelope
+	require.NoError(t, json.Unmarshal(readGolden(t), &golden))
+
+	event, err := JSONSerializer{}.Unmarshal(golden.Data, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, contractTransaction(), event.Transaction())
+	assert.Equal(t, TransactionCreatedVersion, event.Version)
+}
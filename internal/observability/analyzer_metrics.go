@@ -0,0 +1,83 @@
+package observability
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	antifraudProcessedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "antifraud_transactions_processed_total",
+			Help: "Transactions checked by the anti-fraud analyzer, by decision.",
+		},
+		[]string{"decision"},
+	)
+	antifraudFailedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "antifraud_transactions_failed_total",
+			Help: "Failed attempts to process a transaction, by error type; a retried transaction is counted on every attempt.",
+		},
+		[]string{"error_type"},
+	)
+	antifraudRuleHitsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "antifraud_rule_hits_total",
+			Help: "Triggered anti-fraud rules.",
+		},
+		[]string{"rule"},
+	)
+	antifraudProcessingDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "antifraud_processing_duration_seconds",
+			Help:    "Time from reading a transaction to its verdict.",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+)
+
+// RecordFraudCheck counts a checked transaction with its triggered rules and processing time.
+func RecordFraudCheck(decision string, rules []string, duration time.Duration) {
+	antifraudProcessedTotal.WithLabelValues(decision).Inc()
+	for _, rule := range rules {
+		antifraudRuleHitsTotal.WithLabelValues(rule).Inc()
+	}
+	antifraudProcessingDuration.Observe(duration.Seconds())
+}
+
+// RecordFraudCheckFailure counts a transaction sent to a retry topic or to the DLQ.
+func RecordFraudCheckFailure(errorType string) {
+	antifraudFailedTotal.WithLabelValues(errorType).Inc()
+}
+
+// consumerLagCollector exports the lag of consumer groups, read at scrape time.
+type consumerLagCollector struct {
+	lags map[string]func() map[string]map[int32]int64
+	desc *prometheus.Desc
+}
+
+// NewConsumerLagCollector creates the kafka_consumer_lag gauge; lags returns by group the lag by topic and partition.
+func NewConsumerLagCollector(lags map[string]func() map[string]map[int32]int64) prometheus.Collector {
+	return &consumerLagCollector{
+		lags: lags,
+		desc: prometheus.NewDesc("kafka_consumer_lag", "Records of the partition not processed by the consumer group yet.",
+			[]string{"group", "topic", "partition"}, nil),
+	}
+}
+
+func (c *consumerLagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *consumerLagCollector) Collect(ch chan<- prometheus.Metric) {
+	for group, groupLag := range c.lags {
+		for topic, partitions := range groupLag() {
+			for partition, lag := range partitions {
+				ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(lag), group, topic, strconv.Itoa(int(partition)))
+			}
+		}
+	}
+}
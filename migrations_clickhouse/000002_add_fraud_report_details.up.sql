@@ -0,0 +1,5 @@
+ALTER TABLE default.fraud_reports
+    ADD COLUMN IF NOT EXISTS risk_score      Float64,
+    ADD COLUMN IF NOT EXISTS decision        LowCardinality(String),
+    ADD COLUMN IF NOT EXISTS triggered_rules Array(String),
+    ADD COLUMN IF NOT EXISTS engine_version  LowCardinality(String);
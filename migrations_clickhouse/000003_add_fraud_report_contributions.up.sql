@@ -0,0 +1,3 @@
+-- Вклад каждого движка при комбинированной проверке (JSON-массив EngineContribution)
+ALTER TABLE default.fraud_reports
+    ADD COLUMN IF NOT EXISTS engine_contributions String DEFAULT '[]';
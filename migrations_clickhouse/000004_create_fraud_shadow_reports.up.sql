@@ -0,0 +1,15 @@
+-- Вердикты теневых движков рядом с боевым решением (live_decision); decision пустой, если движок не смог проверить транзакцию.
+-- Одна строка на транзакцию и движок: повторная проверка (replay, повтор записи после сбоя) заменяет прежнюю,
+-- иначе shadow-compare считал бы её дважды. Побеждает строка с большим processed_at; читать с FINAL.
+CREATE TABLE IF NOT EXISTS default.fraud_shadow_reports (
+    transaction_id  UUID,
+    engine          LowCardinality(String),
+    engine_version  LowCardinality(String),
+    decision        LowCardinality(String),
+    risk_score      Float64,
+    triggered_rules Array(String),
+    live_decision   LowCardinality(String),
+    error           String,
+    processed_at    DateTime
+) ENGINE = ReplacingMergeTree(processed_at)
+ORDER BY (engine, transaction_id);
@@ -0,0 +1,11 @@
+-- Подтверждённые исходы (ground truth), только дописываются: переразметка добавляет новую строку, прежние остаются,
+-- так что выборку можно построить по меткам, известным на любой момент (argMax по labeled_at <= момента)
+CREATE TABLE IF NOT EXISTS default.fraud_labels (
+    transaction_id UUID,
+    is_fraud       UInt8,
+    source         LowCardinality(String),
+    labeled_by     String,
+    note           String,
+    labeled_at     DateTime
+) ENGINE = MergeTree()
+ORDER BY (transaction_id, source, labeled_at);
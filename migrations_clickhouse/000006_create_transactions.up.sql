@@ -0,0 +1,17 @@
+-- История транзакций для офлайн-пересчёта признаков антифрода (features backfill); пишет anti-fraud analyzer
+CREATE TABLE IF NOT EXISTS default.transactions (
+    transaction_id UUID,
+    created_at     DateTime64(3),
+    amount         Float64,
+    currency       LowCardinality(String),
+    card_hash      String,
+    merchant_id    String,
+    customer_id    String,
+    bin_country    LowCardinality(String),
+    ip             String,
+    ip_country     LowCardinality(String),
+    device_id      String,
+    email_hash     String
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(created_at)
+ORDER BY (created_at, transaction_id);
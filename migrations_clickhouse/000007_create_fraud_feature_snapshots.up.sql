@@ -0,0 +1,9 @@
+-- Значения признаков на момент решения антифрода: обучающие выборки строятся по ним, а не по текущим значениям
+CREATE TABLE IF NOT EXISTS default.fraud_feature_snapshots (
+    transaction_id UUID,
+    decided_at     DateTime64(3),
+    engine_version LowCardinality(String),
+    features       Map(String, Float64)
+) ENGINE = ReplacingMergeTree(decided_at)
+PARTITION BY toYYYYMM(decided_at)
+ORDER BY transaction_id;
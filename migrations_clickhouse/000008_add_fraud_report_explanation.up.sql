@@ -0,0 +1,3 @@
+-- Структурированное объяснение решения (JSON FraudExplanation): правила, входные значения, пороги
+ALTER TABLE default.fraud_reports
+    ADD COLUMN IF NOT EXISTS explanation String DEFAULT '';
@@ -0,0 +1,30 @@
+-- Одна строка на транзакцию: повторная запись отчёта (replay, ребалансировка consumer group) заменяет прежнюю.
+-- Побеждает строка с большей version (время проверки в наносекундах); до слияния кусков читать с FINAL или argMax.
+-- insert_deduplication_token (позиции записей в Kafka) отбрасывает повтор той же пачки после неудачного ответа на INSERT;
+-- для нереплицируемой таблицы его включает non_replicated_deduplication_window.
+CREATE TABLE IF NOT EXISTS default.fraud_reports_dedup (
+    transaction_id       UUID,
+    is_fraudulent        UInt8,
+    reason               String,
+    card_hash            String,
+    amount               Float64,
+    processed_at         DateTime,
+    risk_score           Float64,
+    decision             LowCardinality(String),
+    triggered_rules      Array(String),
+    engine_version       LowCardinality(String),
+    engine_contributions String DEFAULT '[]',
+    explanation          String DEFAULT '',
+    version              UInt64
+) ENGINE = ReplacingMergeTree(version)
+ORDER BY transaction_id
+SETTINGS non_replicated_deduplication_window = 1000;
+
+INSERT INTO default.fraud_reports_dedup
+SELECT transaction_id, is_fraudulent, reason, card_hash, amount, processed_at, risk_score, decision,
+    triggered_rules, engine_version, engine_contributions, explanation, toUInt64(toUnixTimestamp(processed_at)) * 1000000000
+FROM default.fraud_reports;
+
+-- Миграция одноразовая: повторный EXCHANGE вернул бы старую таблицу.
+EXCHANGE TABLES default.fraud_reports AND default.fraud_reports_dedup;
+DROP TABLE default.fraud_reports_dedup;
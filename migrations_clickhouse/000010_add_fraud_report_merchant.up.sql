@@ -0,0 +1,4 @@
+-- Мерчант и страна транзакции: по ним аномалии мерчантов учитывают отказы до публикации (pre-auth), у которых нет строки в default.transactions
+ALTER TABLE default.fraud_reports
+    ADD COLUMN IF NOT EXISTS merchant_id String DEFAULT '',
+    ADD COLUMN IF NOT EXISTS country     LowCardinality(String) DEFAULT '';
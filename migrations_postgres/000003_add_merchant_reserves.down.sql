@@ -0,0 +1,9 @@
+-- Удаление таблиц резерва
+DROP INDEX IF EXISTS idx_reserve_entries_held;
+DROP TABLE IF EXISTS reserve_entries;
+DROP TABLE IF EXISTS merchant_reserve_configs;
+
+-- Удаление привязки к мерчанту
+DROP INDEX IF EXISTS idx_transactions_merchant;
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS merchant_id;
@@ -0,0 +1,29 @@
+-- Привязка транзакций к мерчанту
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS merchant_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_merchant ON transactions(merchant_id, status);
+
+-- Настройки резерва мерчанта
+CREATE TABLE IF NOT EXISTS merchant_reserve_configs (
+    merchant_id VARCHAR(64) PRIMARY KEY,
+    rolling_percent DECIMAL(5,2) NOT NULL,
+    hold_days INTEGER NOT NULL,
+    minimum_reserve DECIMAL(12,2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Удержанные суммы резерва, по одной записи на транзакцию
+CREATE TABLE IF NOT EXISTS reserve_entries (
+    id UUID PRIMARY KEY,
+    merchant_id VARCHAR(64) NOT NULL REFERENCES merchant_reserve_configs(merchant_id),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id),
+    amount DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    held_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    release_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reserve_entries_held ON reserve_entries(merchant_id, release_at) WHERE released_at IS NULL;
@@ -0,0 +1,4 @@
+-- Удаление привязки к покупателю
+DROP INDEX IF EXISTS idx_transactions_customer;
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS customer_id;
@@ -0,0 +1,5 @@
+-- Привязка транзакций к покупателю (для лимитов по клиенту)
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS customer_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_customer ON transactions(customer_id);
@@ -0,0 +1,4 @@
+-- Удаление очереди ручной проверки
+DROP INDEX IF EXISTS idx_review_cases_unexported;
+DROP INDEX IF EXISTS idx_review_cases_queue;
+DROP TABLE IF EXISTS review_cases;
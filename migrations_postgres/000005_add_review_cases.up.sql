@@ -0,0 +1,21 @@
+-- Очередь ручной проверки транзакций с вердиктом REVIEW
+CREATE TABLE IF NOT EXISTS review_cases (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id),
+    status VARCHAR(16) NOT NULL,
+    risk_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    triggered_rules TEXT[] NOT NULL DEFAULT '{}',
+    assigned_to VARCHAR(255),
+    decided_by VARCHAR(255),
+    auto_decided BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    label_exported_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_cases_queue ON review_cases(status, due_at);
+CREATE INDEX IF NOT EXISTS idx_review_cases_unexported ON review_cases(decided_at) WHERE decided_at IS NOT NULL AND label_exported_at IS NULL;
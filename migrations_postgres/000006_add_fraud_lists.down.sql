@@ -0,0 +1,3 @@
+-- Удаление блок- и allow-листов
+DROP INDEX IF EXISTS idx_fraud_list_entries_created;
+DROP TABLE IF EXISTS fraud_list_entries;
@@ -0,0 +1,14 @@
+-- Блок- и allow-листы для антифрода
+CREATE TABLE IF NOT EXISTS fraud_list_entries (
+    id UUID PRIMARY KEY,
+    list_type VARCHAR(8) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (list_type, kind, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_list_entries_created ON fraud_list_entries(created_at);
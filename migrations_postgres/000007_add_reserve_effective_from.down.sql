@@ -0,0 +1,4 @@
+-- Удаление начала действия резерва
+DROP INDEX IF EXISTS idx_transactions_merchant_created;
+ALTER TABLE merchant_reserve_configs
+DROP COLUMN IF EXISTS effective_from;
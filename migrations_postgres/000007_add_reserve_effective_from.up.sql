@@ -0,0 +1,6 @@
+-- Момент, с которого действует резерв мерчанта: удерживаются только транзакции, созданные после него.
+-- Для уже настроенных мерчантов отсчёт начинается с применения миграции, история не удерживается задним числом.
+ALTER TABLE merchant_reserve_configs
+ADD COLUMN IF NOT EXISTS effective_from TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_transactions_merchant_created ON transactions(merchant_id, status, created_at);
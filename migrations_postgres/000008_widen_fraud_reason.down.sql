@@ -0,0 +1,3 @@
+-- Возврат к VARCHAR(255): длинные причины обрезаются
+ALTER TABLE transactions
+ALTER COLUMN fraud_reason TYPE VARCHAR(255) USING LEFT(fraud_reason, 255);
@@ -0,0 +1,3 @@
+-- Причина отказа перечисляет все сработавшие правила и не помещается в 255 символов
+ALTER TABLE transactions
+ALTER COLUMN fraud_reason TYPE TEXT;
@@ -42,4 +42,45 @@ allow {
     input.user.roles[_] == "manager"
     input.method == "GET"
     input.path == "/api/v1/analytics"
-}
\ No newline at end of file
+}
+
+# ПРАВИЛО 5: Менеджеры могут смотреть резерв мерчанта
+allow {
+    input.user.roles[_] == "manager"
+    input.method == "GET"
+    path_parts := split(input.path, "/")
+    count(path_parts) == 6
+    path_parts[3] == "merchants"
+    path_parts[5] == "reserve"
+}
+
+# ПРАВИЛО 6: Аналитики фрода работают с очередью ручной проверки
+allow {
+    input.user.roles[_] == "fraud_analyst"
+    startswith(input.path, "/api/v1/review/")
+}
+
+# ПРАВИЛО 7: Аналитики фрода ведут блок- и allow-листы
+allow {
+    input.user.roles[_] == "fraud_analyst"
+    startswith(input.path, "/api/v1/lists/")
+}
+
+# ПРАВИЛО 8: Аналитики фрода записывают подтверждённые исходы (чарджбэки, отчёты мерчантов)
+allow {
+    input.user.roles[_] == "fraud_analyst"
+    input.method == "POST"
+    input.path == "/api/v1/fraud/labels"
+}
+
+# ПРАВИЛО 9: Поддержка и аналитики фрода смотрят, почему транзакция отклонена
+allow {
+    fraud_report_readers[input.user.roles[_]]
+    input.method == "GET"
+    path_parts := split(input.path, "/")
+    count(path_parts) == 6
+    path_parts[3] == "transaction"
+    path_parts[5] == "fraud"
+}
+
+fraud_report_readers := {"support", "fraud_analyst"}
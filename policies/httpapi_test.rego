@@ -14,4 +14,94 @@ test_manager_can_access {
 
     # Проверяем, что с этим input'ом правило "allow" вернёт true
     allow with input as mock_input
-}
\ No newline at end of file
+}
+
+# Тест: менеджер видит резерв мерчанта, но не может его настраивать
+test_manager_can_view_reserve {
+    allow with input as {
+        "method": "GET",
+        "path": "/api/v1/merchants/merchant-42/reserve",
+        "user": {"sub": "user-manager-789", "roles": ["manager"]}
+    }
+}
+
+test_manager_cannot_configure_reserve {
+    not allow with input as {
+        "method": "PUT",
+        "path": "/api/v1/merchants/merchant-42/reserve/config",
+        "user": {"sub": "user-manager-789", "roles": ["manager"]}
+    }
+}
+
+# Тест: аналитик фрода разбирает очередь ручной проверки, но не создаёт транзакции
+test_fraud_analyst_can_decide_review_case {
+    allow with input as {
+        "method": "POST",
+        "path": "/api/v1/review/cases/6f1c2a3e-0000-4000-8000-000000000001/approve",
+        "user": {"sub": "analyst-1", "roles": ["fraud_analyst"]}
+    }
+}
+
+test_fraud_analyst_cannot_create_transaction {
+    not allow with input as {
+        "method": "POST",
+        "path": "/api/v1/transaction",
+        "user": {"sub": "analyst-1", "roles": ["fraud_analyst"]}
+    }
+}
+
+test_customer_cannot_list_review_cases {
+    not allow with input as {
+        "method": "GET",
+        "path": "/api/v1/review/cases",
+        "user": {"sub": "user-123", "roles": ["customer"]}
+    }
+}
+
+test_fraud_analyst_can_import_list_entries {
+    allow with input as {
+        "method": "POST",
+        "path": "/api/v1/lists/entries/import",
+        "user": {"sub": "analyst-1", "roles": ["fraud_analyst"]}
+    }
+}
+
+test_manager_cannot_change_lists {
+    not allow with input as {
+        "method": "DELETE",
+        "path": "/api/v1/lists/entries/6f1c2a3e-0000-4000-8000-000000000001",
+        "user": {"sub": "user-manager-789", "roles": ["manager"]}
+    }
+}
+
+test_fraud_analyst_can_record_labels {
+    allow with input as {
+        "method": "POST",
+        "path": "/api/v1/fraud/labels",
+        "user": {"sub": "analyst-1", "roles": ["fraud_analyst"]}
+    }
+}
+
+test_customer_cannot_record_labels {
+    not allow with input as {
+        "method": "POST",
+        "path": "/api/v1/fraud/labels",
+        "user": {"sub": "user-123", "roles": ["customer"]}
+    }
+}
+
+test_support_can_read_fraud_explanation {
+    allow with input as {
+        "method": "GET",
+        "path": "/api/v1/transaction/6f1c2a3e-0000-4000-8000-000000000001/fraud",
+        "user": {"sub": "support-1", "roles": ["support"]}
+    }
+}
+
+test_customer_cannot_read_fraud_explanation {
+    not allow with input as {
+        "method": "GET",
+        "path": "/api/v1/transaction/6f1c2a3e-0000-4000-8000-000000000001/fraud",
+        "user": {"sub": "user-123", "roles": ["customer"]}
+    }
+}
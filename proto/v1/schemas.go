@@ -0,0 +1,9 @@
+// Package protov1 embeds the .proto sources: the schema registry keeps the source of a Protobuf schema.
+package protov1
+
+import _ "embed"
+
+// EventsProto is the source of events.proto, registered for the Protobuf format of transactions.created.
+//
+//go:embed events.proto
+var EventsProto string